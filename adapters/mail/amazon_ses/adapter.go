@@ -7,14 +7,21 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/gonstruct/providers/mail"
 )
 
+// Adapter sends mail with the Amazon SES v2 API. SES has no scheduled delivery, so messages
+// sent with mail.SendAt are persisted by the local scheduler, see mail.WithScheduleStore.
 type Adapter struct {
 	Region   string
 	Host     string
 	Port     int
 	Username string
 	Password string
+	// BulkTemplate is the name of an SES template used by SendBatch. When set, batches that share
+	// a sender and have no attachments or custom headers are sent with SES bulk sending, rendering
+	// the template with the data of each message instead of the mailable's view.
+	BulkTemplate string
 }
 
 func (adapter Adapter) NewClient(ctx context.Context) (*sesv2.Client, error) {
@@ -29,3 +36,8 @@ func (adapter Adapter) NewClient(ctx context.Context) (*sesv2.Client, error) {
 
 	return sesv2.NewFromConfig(cfg), nil
 }
+
+// MaxMessageSize returns the SES limit of 40 MB per message, including attachments after encoding.
+func (adapter Adapter) MaxMessageSize() int64 {
+	return 40 * mail.MB
+}
@@ -0,0 +1,188 @@
+package amazon_ses
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	netmail "net/mail"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/mail"
+)
+
+// maxBulkEntries is the maximum number of destinations of a single SES bulk request.
+const maxBulkEntries = 50
+
+// SendBatch sends every input with a single SES client. Inputs are sent with SES bulk
+// sending when BulkTemplate is set and the inputs allow it, one by one otherwise.
+func (adapter Adapter) SendBatch(ctx context.Context, inputs []entities.MailInput) []error {
+	_, errs := adapter.SendBatchWithReceipt(ctx, inputs)
+
+	return errs
+}
+
+// SendBatchWithReceipt sends every input like SendBatch and returns the receipts with the SES
+// MessageId of every message as provider ID, like SendWithReceipt.
+func (adapter Adapter) SendBatchWithReceipt(ctx context.Context, inputs []entities.MailInput) ([]*entities.MailReceipt, []error) {
+	receipts := make([]*entities.MailReceipt, len(inputs))
+	errs := make([]error, len(inputs))
+
+	client, err := adapter.NewClient(ctx)
+	if err != nil {
+		for i := range errs {
+			errs[i] = mail.Err("create SES client", err)
+		}
+
+		return receipts, errs
+	}
+
+	if adapter.BulkTemplate != "" && bulkable(inputs) {
+		for start := 0; start < len(inputs); start += maxBulkEntries {
+			end := min(start+maxBulkEntries, len(inputs))
+			adapter.sendBulk(ctx, client, inputs[start:end], receipts[start:end], errs[start:end])
+		}
+
+		return receipts, errs
+	}
+
+	for i, input := range inputs {
+		message, err := buildMessage(ctx, input)
+		if err != nil {
+			errs[i] = err
+
+			continue
+		}
+
+		output, err := client.SendEmail(ctx, message)
+		if err != nil {
+			errs[i] = mail.Err("send via SES", err)
+
+			continue
+		}
+
+		receipts[i] = adapter.receipt(input, aws.ToString(output.MessageId))
+	}
+
+	return receipts, errs
+}
+
+// bulkable reports whether the inputs can be sent as one SES bulk request,
+// which shares the sender and content between all destinations.
+func bulkable(inputs []entities.MailInput) bool {
+	for _, input := range inputs {
+		if input.Envelope.From == nil || len(input.Envelope.To) == 0 ||
+			len(input.Attachments) > 0 || len(input.Envelope.Headers) > 0 || len(input.Processors) > 0 ||
+			input.Envelope.InReplyTo != "" || len(input.Envelope.References) > 0 {
+			return false
+		}
+
+		if !sameAddress(input.Envelope.From, inputs[0].Envelope.From) ||
+			!sameAddress(input.Envelope.ReplyTo, inputs[0].Envelope.ReplyTo) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sameAddress(a, b *netmail.Address) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.String() == b.String()
+}
+
+func (adapter Adapter) sendBulk(
+	ctx context.Context,
+	client *sesv2.Client,
+	inputs []entities.MailInput,
+	receipts []*entities.MailReceipt,
+	errs []error,
+) {
+	first := inputs[0].Envelope
+	request := &sesv2.SendBulkEmailInput{
+		FromEmailAddress: aws.String(first.From.String()),
+		DefaultContent: &types.BulkEmailContent{
+			Template: &types.Template{
+				TemplateName: aws.String(adapter.BulkTemplate),
+				TemplateData: aws.String("{}"),
+			},
+		},
+	}
+
+	if first.ReplyTo != nil {
+		request.ReplyToAddresses = []string{first.ReplyTo.String()}
+	}
+
+	for i, input := range inputs {
+		data, err := json.Marshal(input.Data)
+		if err != nil {
+			errs[i] = mail.Err("encode template data", err)
+
+			continue
+		}
+
+		entry := types.BulkEmailEntry{
+			Destination: &types.Destination{
+				ToAddresses:  input.Envelope.To.String(),
+				CcAddresses:  input.Envelope.Cc.String(),
+				BccAddresses: input.Envelope.Bcc.String(),
+			},
+			ReplacementEmailContent: &types.ReplacementEmailContent{
+				ReplacementTemplate: &types.ReplacementTemplate{ReplacementTemplateData: aws.String(string(data))},
+			},
+		}
+
+		for _, name := range sortedKeys(input.Envelope.Metadata) {
+			entry.ReplacementTags = append(entry.ReplacementTags, types.MessageTag{
+				Name:  aws.String(name),
+				Value: aws.String(input.Envelope.Metadata[name]),
+			})
+		}
+
+		request.BulkEmailEntries = append(request.BulkEmailEntries, entry)
+	}
+
+	// Entries that failed to encode are not part of the request, results map to the remaining inputs
+	var sent []int
+
+	for i := range inputs {
+		if errs[i] == nil {
+			sent = append(sent, i)
+		}
+	}
+
+	if len(sent) == 0 {
+		return
+	}
+
+	response, err := client.SendBulkEmail(ctx, request)
+	if err != nil {
+		for _, i := range sent {
+			errs[i] = mail.Err("send via SES", err)
+		}
+
+		return
+	}
+
+	for j, i := range sent {
+		if j >= len(response.BulkEmailEntryResults) {
+			errs[i] = mail.Err("send via SES", mail.ErrSendFailed)
+
+			continue
+		}
+
+		result := response.BulkEmailEntryResults[j]
+		if result.Status != types.BulkEmailStatusSuccess {
+			errs[i] = mail.Err("send via SES", fmt.Errorf("%w: %s: %s", mail.ErrSendFailed, result.Status, aws.ToString(result.Error)))
+
+			continue
+		}
+
+		receipts[i] = adapter.receipt(inputs[i], aws.ToString(result.MessageId))
+	}
+}
@@ -2,6 +2,7 @@ package amazon_ses
 
 import (
 	"context"
+	"sort"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sesv2"
@@ -10,8 +11,56 @@ import (
 	"github.com/gonstruct/providers/mail"
 )
 
-//nolint:cyclop,funlen
 func (adapter Adapter) Send(ctx context.Context, input entities.MailInput) error {
+	_, err := adapter.SendWithReceipt(ctx, input)
+
+	return err
+}
+
+// SendWithReceipt sends the message and returns the SES MessageId as provider ID. SES replaces
+// the Message-ID header with one built from the MessageId, which the receipt reports instead.
+func (adapter Adapter) SendWithReceipt(ctx context.Context, input entities.MailInput) (*entities.MailReceipt, error) {
+	message, err := buildMessage(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := adapter.NewClient(ctx)
+	if err != nil {
+		return nil, mail.Err("create SES client", err)
+	}
+
+	output, err := client.SendEmail(ctx, message)
+	if err != nil {
+		return nil, mail.Err("send via SES", err)
+	}
+
+	return adapter.receipt(input, aws.ToString(output.MessageId)), nil
+}
+
+// receipt returns the receipt of a message SES accepted with the given MessageId, with the
+// Message-ID SES replaces the generated one with.
+func (adapter Adapter) receipt(input entities.MailInput, id string) *entities.MailReceipt {
+	receipt := mail.NewReceipt(input, id)
+	if receipt.ProviderID != "" {
+		receipt.MessageID = adapter.messageID(receipt.ProviderID)
+	}
+
+	return receipt
+}
+
+// messageID returns the Message-ID header SES sets for the given MessageId.
+func (adapter Adapter) messageID(id string) string {
+	domain := "email.amazonses.com"
+	if adapter.Region != "" && adapter.Region != "us-east-1" {
+		domain = adapter.Region + ".amazonses.com"
+	}
+
+	return "<" + id + "@" + domain + ">"
+}
+
+//nolint:cyclop,funlen
+func buildMessage(ctx context.Context, input entities.MailInput) (*sesv2.SendEmailInput, error) {
 	message := &sesv2.SendEmailInput{}
 
 	var subject *types.Content
@@ -21,20 +70,20 @@ func (adapter Adapter) Send(ctx context.Context, input entities.MailInput) error
 			Charset: aws.String("UTF-8"),
 		}
 	} else {
-		return mail.Err("validate", mail.ErrNoSubject)
+		return nil, mail.Err("validate", mail.ErrNoSubject)
 	}
 
 	if input.Envelope.From != nil {
 		message.FromEmailAddress = aws.String(input.Envelope.From.String())
 	} else {
-		return mail.Err("validate", mail.ErrNoSender)
+		return nil, mail.Err("validate", mail.ErrNoSender)
 	}
 
 	message.Destination = &types.Destination{}
 	if len(input.Envelope.To) > 0 {
 		message.Destination.ToAddresses = input.Envelope.To.String()
 	} else {
-		return mail.Err("validate", mail.ErrNoRecipients)
+		return nil, mail.Err("validate", mail.ErrNoRecipients)
 	}
 
 	if input.Envelope.ReplyTo != nil {
@@ -49,21 +98,69 @@ func (adapter Adapter) Send(ctx context.Context, input entities.MailInput) error
 		message.Destination.BccAddresses = input.Envelope.Bcc.String()
 	}
 
+	for _, name := range sortedKeys(input.Envelope.Metadata) {
+		message.EmailTags = append(message.EmailTags, types.MessageTag{
+			Name:  aws.String(name),
+			Value: aws.String(input.Envelope.Metadata[name]),
+		})
+	}
+
+	// Calendar invites need a text/calendar alternative part, custom headers need to be in
+	// the message itself and processed messages are signed or encrypted as a whole,
+	// which only raw messages support
+	if input.Attachments.HasCalendar() || len(input.Envelope.Headers) > 0 || len(input.Processors) > 0 {
+		raw, err := mail.Raw(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		message.Content = &types.EmailContent{
+			Raw: &types.RawMessage{Data: raw},
+		}
+	} else {
+		content, err := simpleMessage(ctx, subject, input)
+		if err != nil {
+			return nil, err
+		}
+
+		message.Content = content
+	}
+
+	return message, nil
+}
+
+// simpleMessage builds the SES simple content. The SES API takes attachments as bytes,
+// so lazy attachments are read into memory here.
+func simpleMessage(ctx context.Context, subject *types.Content, input entities.MailInput) (*types.EmailContent, error) {
 	var attachments []types.Attachment
 
-	if len(input.Attachments) > 0 {
-		for _, attachment := range input.Attachments {
-			attachments = append(attachments, types.Attachment{
-				FileName:                aws.String(attachment.Name),
-				ContentType:             aws.String(attachment.Mime),
-				ContentTransferEncoding: types.AttachmentContentTransferEncodingBase64,
-				RawContent:              attachment.Content(),
-			})
+	for _, attachment := range input.Attachments {
+		content, err := attachment.ReadContent(ctx)
+		if err != nil {
+			return nil, mail.Err("read attachment", err)
+		}
+
+		attachments = append(attachments, types.Attachment{
+			FileName:                aws.String(attachment.Name),
+			ContentType:             aws.String(attachment.Mime),
+			ContentTransferEncoding: types.AttachmentContentTransferEncodingBase64,
+			RawContent:              content,
+		})
+	}
+
+	// SES sets the Message-ID itself, so only the threading headers are sent
+	var headers []types.MessageHeader
+
+	threading := mail.Headers(input)
+	for _, name := range []string{"In-Reply-To", "References"} {
+		if value, ok := threading[name]; ok {
+			headers = append(headers, types.MessageHeader{Name: aws.String(name), Value: aws.String(value)})
 		}
 	}
 
-	message.Content = &types.EmailContent{
+	return &types.EmailContent{
 		Simple: &types.Message{
+			Headers: headers,
 			Subject: subject,
 			Body: &types.Body{
 				Html: &types.Content{
@@ -74,16 +171,16 @@ func (adapter Adapter) Send(ctx context.Context, input entities.MailInput) error
 			},
 			Attachments: attachments,
 		},
-	}
+	}, nil
+}
 
-	client, err := adapter.NewClient(ctx)
-	if err != nil {
-		return mail.Err("create SES client", err)
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
 	}
 
-	if _, err := client.SendEmail(ctx, message); err != nil {
-		return mail.Err("send via SES", err)
-	}
+	sort.Strings(keys)
 
-	return nil
+	return keys
 }
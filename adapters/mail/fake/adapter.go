@@ -3,6 +3,7 @@ package fake
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/gonstruct/providers/entities"
 )
@@ -13,21 +14,49 @@ type Adapter struct {
 
 	// Call tracking
 	Calls []SendCall
+	// Queued are the messages scheduled with mail.SendAt that were not cancelled
+	Queued    []QueuedCall
+	Cancelled []string
+	scheduled int
 
 	// Error injection
 	SendError error
 
-	// Custom send function
+	// Custom send function, it replaces Send and is called before Schedule queues a message,
+	// which fails with its error
 	SendFunc func(ctx context.Context, input entities.MailInput) error
 }
 
 type SendCall struct {
 	To          []string
+	Cc          []string
+	Bcc         []string
+	ReplyTo     string
 	From        string
 	Subject     string
 	HTML        string
-	Attachments int
-	Input       entities.MailInput
+	Attachments []Attachment
+	// MessageID, InReplyTo and References are the Message-ID and threading headers, with angle brackets
+	MessageID  string
+	InReplyTo  string
+	References []string
+	// Mailable is the mailable passed to mail.Send, nil when the adapter was called directly
+	Mailable any
+	Input    entities.MailInput
+}
+
+// QueuedCall is a message scheduled for delivery at a later time.
+type QueuedCall struct {
+	SendCall
+	ID string
+	At time.Time
+}
+
+// Attachment is an attachment of a sent email with its content read into memory.
+type Attachment struct {
+	Name    string
+	Mime    string
+	Content []byte
 }
 
 // New creates a new fake mail adapter.
@@ -41,6 +70,8 @@ func (a *Adapter) Reset() {
 	defer a.mu.Unlock()
 
 	a.Calls = nil
+	a.Queued = nil
+	a.Cancelled = nil
 	a.SendError = nil
 	a.SendFunc = nil
 }
@@ -55,6 +86,14 @@ func (a *Adapter) SentCount() int {
 	return len(a.Calls)
 }
 
+// QueuedCount returns the number of emails queued.
+func (a *Adapter) QueuedCount() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return len(a.Queued)
+}
+
 // LastCall returns the last send call, or nil if none.
 func (a *Adapter) LastCall() *SendCall {
 	a.mu.RLock()
@@ -1,7 +1,12 @@
 package fake
 
 import (
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/gonstruct/providers/entities/mailables"
 )
 
 // AssertSent asserts that at least one email was sent.
@@ -107,3 +112,246 @@ func (a *Adapter) AssertNothingSent(t testing.TB) {
 		t.Errorf("Expected no emails to be sent, but %d were sent", len(a.Calls))
 	}
 }
+
+// AssertSentMatching asserts that at least one email matching the predicate was sent.
+func (a *Adapter) AssertSentMatching(t testing.TB, predicate func(call SendCall) bool) {
+	t.Helper()
+
+	if !a.sent(predicate) {
+		t.Error("Expected an email matching the predicate to be sent, but none was")
+	}
+}
+
+// AssertNotSentMatching asserts that no email matching the predicate was sent.
+func (a *Adapter) AssertNotSentMatching(t testing.TB, predicate func(call SendCall) bool) {
+	t.Helper()
+
+	if a.sent(predicate) {
+		t.Error("Expected no email matching the predicate to be sent, but one was")
+	}
+}
+
+// AssertSentCc asserts that an email was sent with the given address in Cc.
+func (a *Adapter) AssertSentCc(t testing.TB, email string) {
+	t.Helper()
+
+	if !a.sent(func(call SendCall) bool { return slices.Contains(call.Cc, email) }) {
+		t.Errorf("Expected email to be sent with %q in Cc, but it was not", email)
+	}
+}
+
+// AssertSentBcc asserts that an email was sent with the given address in Bcc.
+func (a *Adapter) AssertSentBcc(t testing.TB, email string) {
+	t.Helper()
+
+	if !a.sent(func(call SendCall) bool { return slices.Contains(call.Bcc, email) }) {
+		t.Errorf("Expected email to be sent with %q in Bcc, but it was not", email)
+	}
+}
+
+// AssertSentReplyTo asserts that an email was sent with the given Reply-To address.
+func (a *Adapter) AssertSentReplyTo(t testing.TB, email string) {
+	t.Helper()
+
+	if !a.sent(func(call SendCall) bool { return call.ReplyTo == email }) {
+		t.Errorf("Expected email to be sent with Reply-To %q, but it was not", email)
+	}
+}
+
+// AssertSentInReplyTo asserts that an email was sent as a reply to the message with the given Message-ID.
+func (a *Adapter) AssertSentInReplyTo(t testing.TB, messageID string) {
+	t.Helper()
+
+	want := mailables.MessageID(messageID)
+	if !a.sent(func(call SendCall) bool { return call.InReplyTo == want }) {
+		t.Errorf("Expected email to be sent in reply to %q, but it was not", want)
+	}
+}
+
+// AssertSentWithAttachment asserts that an email was sent with an attachment of the given name.
+func (a *Adapter) AssertSentWithAttachment(t testing.TB, name string) {
+	t.Helper()
+
+	a.AssertSentWithAttachmentMatching(t, func(attachment Attachment) bool { return attachment.Name == name })
+}
+
+// AssertSentWithAttachmentMatching asserts that an email was sent with an attachment matching the
+// predicate, for example to check its MIME type or content.
+func (a *Adapter) AssertSentWithAttachmentMatching(t testing.TB, predicate func(attachment Attachment) bool) {
+	t.Helper()
+
+	if !a.sent(func(call SendCall) bool { return slices.ContainsFunc(call.Attachments, predicate) }) {
+		t.Error("Expected email to be sent with a matching attachment, but it was not")
+	}
+}
+
+// AssertSentHTMLContains asserts that an email was sent whose HTML body contains the substring.
+func (a *Adapter) AssertSentHTMLContains(t testing.TB, substring string) {
+	t.Helper()
+
+	if !a.sent(func(call SendCall) bool { return strings.Contains(call.HTML, substring) }) {
+		t.Errorf("Expected email to be sent with %q in the HTML body, but it was not", substring)
+	}
+}
+
+// AssertSentHTMLNotContains asserts that no email was sent whose HTML body contains the substring.
+func (a *Adapter) AssertSentHTMLNotContains(t testing.TB, substring string) {
+	t.Helper()
+
+	if a.sent(func(call SendCall) bool { return strings.Contains(call.HTML, substring) }) {
+		t.Errorf("Expected no email to be sent with %q in the HTML body, but one was", substring)
+	}
+}
+
+// AssertSentMailable asserts that a mailable of type T was sent with mail.Send and,
+// when predicates are given, that one of them matches all predicates.
+//
+//	fake.AssertSentMailable(t, adapter, func(m WelcomeMail) bool { return m.User.ID == 42 })
+func AssertSentMailable[T any](t testing.TB, a *Adapter, predicates ...func(mailable T) bool) {
+	t.Helper()
+
+	for _, mailable := range SentMailables[T](a) {
+		if matchesAll(mailable, predicates) {
+			return
+		}
+	}
+
+	var zero T
+
+	t.Errorf("Expected a %T mailable to be sent, but none matched", zero)
+}
+
+// AssertNotSentMailable asserts that no mailable of type T was sent with mail.Send.
+func AssertNotSentMailable[T any](t testing.TB, a *Adapter) {
+	t.Helper()
+
+	if sent := SentMailables[T](a); len(sent) > 0 {
+		var zero T
+
+		t.Errorf("Expected no %T mailable to be sent, but %d were sent", zero, len(sent))
+	}
+}
+
+// SentMailables returns the sent mailables of type T.
+func SentMailables[T any](a *Adapter) []T {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var mailables []T
+
+	for _, call := range a.Calls {
+		if mailable, ok := call.Mailable.(T); ok {
+			mailables = append(mailables, mailable)
+		}
+	}
+
+	return mailables
+}
+
+func matchesAll[T any](value T, predicates []func(T) bool) bool {
+	for _, predicate := range predicates {
+		if !predicate(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a *Adapter) sent(predicate func(call SendCall) bool) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return slices.ContainsFunc(a.Calls, predicate)
+}
+
+// AssertQueued asserts that at least one email was queued with mail.SendAt.
+func (a *Adapter) AssertQueued(t testing.TB) {
+	t.Helper()
+
+	if a.QueuedCount() == 0 {
+		t.Error("Expected at least one email to be queued, but none were queued")
+	}
+}
+
+// AssertQueuedCount asserts the exact number of emails queued and not cancelled.
+func (a *Adapter) AssertQueuedCount(t testing.TB, count int) {
+	t.Helper()
+
+	if queued := a.QueuedCount(); queued != count {
+		t.Errorf("Expected %d emails to be queued, got %d", count, queued)
+	}
+}
+
+// AssertNothingQueued asserts that no emails are queued.
+func (a *Adapter) AssertNothingQueued(t testing.TB) {
+	t.Helper()
+
+	if queued := a.QueuedCount(); queued > 0 {
+		t.Errorf("Expected no emails to be queued, but %d were queued", queued)
+	}
+}
+
+// AssertQueuedTo asserts that an email to the given recipient was queued.
+func (a *Adapter) AssertQueuedTo(t testing.TB, email string) {
+	t.Helper()
+
+	if !a.queued(func(call QueuedCall) bool { return slices.Contains(call.To, email) }) {
+		t.Errorf("Expected email to be queued to %q, but it was not", email)
+	}
+}
+
+// AssertQueuedAt asserts that an email was queued for delivery at the given time.
+func (a *Adapter) AssertQueuedAt(t testing.TB, at time.Time) {
+	t.Helper()
+
+	if !a.queued(func(call QueuedCall) bool { return call.At.Equal(at) }) {
+		t.Errorf("Expected email to be queued at %s, but it was not", at)
+	}
+}
+
+// AssertQueuedMatching asserts that at least one queued email matches the predicate.
+func (a *Adapter) AssertQueuedMatching(t testing.TB, predicate func(call QueuedCall) bool) {
+	t.Helper()
+
+	if !a.queued(predicate) {
+		t.Error("Expected an email matching the predicate to be queued, but none was")
+	}
+}
+
+// AssertCancelled asserts that the queued email with the given ID was cancelled.
+func (a *Adapter) AssertCancelled(t testing.TB, id string) {
+	t.Helper()
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if !slices.Contains(a.Cancelled, id) {
+		t.Errorf("Expected queued email %q to be cancelled, but it was not", id)
+	}
+}
+
+// AssertQueuedMailable asserts that a mailable of type T was queued and,
+// when predicates are given, that one of them matches all predicates.
+func AssertQueuedMailable[T any](t testing.TB, a *Adapter, predicates ...func(mailable T) bool) {
+	t.Helper()
+
+	matched := a.queued(func(call QueuedCall) bool {
+		mailable, ok := call.Mailable.(T)
+
+		return ok && matchesAll(mailable, predicates)
+	})
+
+	if !matched {
+		var zero T
+
+		t.Errorf("Expected a %T mailable to be queued, but none matched", zero)
+	}
+}
+
+func (a *Adapter) queued(predicate func(call QueuedCall) bool) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return slices.ContainsFunc(a.Queued, predicate)
+}
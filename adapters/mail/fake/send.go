@@ -2,6 +2,10 @@ package fake
 
 import (
 	"context"
+	"fmt"
+	"net/mail"
+	"slices"
+	"time"
 
 	"github.com/gonstruct/providers/entities"
 )
@@ -15,32 +19,135 @@ func (a *Adapter) Send(ctx context.Context, input entities.MailInput) error {
 		return a.SendError
 	}
 
+	call, err := newSendCall(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.Calls = append(a.Calls, call)
+	a.mu.Unlock()
+
+	return nil
+}
+
+// SendWithReceipt records the message like Send and returns a receipt with every recipient accepted.
+func (a *Adapter) SendWithReceipt(ctx context.Context, input entities.MailInput) (*entities.MailReceipt, error) {
+	if err := a.Send(ctx, input); err != nil {
+		return nil, err
+	}
+
 	envelope := input.Envelope
 
-	to := make([]string, len(envelope.To))
-	for i, addr := range envelope.To {
-		to[i] = addr.Address
+	return &entities.MailReceipt{
+		MessageID: input.MessageID,
+		Accepted:  slices.Concat(addresses(envelope.To), addresses(envelope.Cc), addresses(envelope.Bcc)),
+		SentAt:    time.Now(),
+	}, nil
+}
+
+// Schedule records the message as queued for delivery at the given time.
+func (a *Adapter) Schedule(ctx context.Context, input entities.MailInput, at time.Time) (string, error) {
+	if a.SendFunc != nil {
+		if err := a.SendFunc(ctx, input); err != nil {
+			return "", err
+		}
+	} else if a.SendError != nil {
+		return "", a.SendError
+	}
+
+	call, err := newSendCall(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.scheduled++
+
+	id := input.IdempotencyKey
+	if id == "" {
+		id = fmt.Sprintf("fake-%d", a.scheduled)
+	}
+
+	// Scheduling with the same key replaces the queued message, like the real schedulers
+	a.Queued = slices.DeleteFunc(a.Queued, func(queued QueuedCall) bool { return queued.ID == id })
+	a.Queued = append(a.Queued, QueuedCall{SendCall: call, ID: id, At: at})
+
+	return id, nil
+}
+
+// CancelScheduled removes the queued message and records the cancellation.
+func (a *Adapter) CancelScheduled(_ context.Context, id string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	queued := len(a.Queued)
+
+	a.Queued = slices.DeleteFunc(a.Queued, func(queued QueuedCall) bool { return queued.ID == id })
+	if len(a.Queued) == queued {
+		return fmt.Errorf("no queued message with ID %q", id)
 	}
 
-	var from string
+	a.Cancelled = append(a.Cancelled, id)
+
+	return nil
+}
+
+// MaxScheduleDelay returns 0, the fake adapter queues messages for any time.
+func (a *Adapter) MaxScheduleDelay() time.Duration {
+	return 0
+}
+
+// newSendCall records the input like a real transport would read it, so unreadable attachments fail.
+func newSendCall(ctx context.Context, input entities.MailInput) (SendCall, error) {
+	envelope := input.Envelope
+
+	var from, replyTo string
 	if envelope.From != nil {
 		from = envelope.From.Address
 	}
 
-	call := SendCall{
-		To:          to,
+	if envelope.ReplyTo != nil {
+		replyTo = envelope.ReplyTo.Address
+	}
+
+	attachments := make([]Attachment, len(input.Attachments))
+
+	for i, attachment := range input.Attachments {
+		content, err := attachment.ReadContent(ctx)
+		if err != nil {
+			return SendCall{}, err
+		}
+
+		attachments[i] = Attachment{Name: attachment.Name, Mime: attachment.Mime, Content: content}
+	}
+
+	return SendCall{
+		To:          addresses(envelope.To),
+		Cc:          addresses(envelope.Cc),
+		Bcc:         addresses(envelope.Bcc),
+		ReplyTo:     replyTo,
 		From:        from,
 		Subject:     envelope.Subject,
 		HTML:        input.Html.String(),
-		Attachments: len(input.Attachments),
+		Attachments: attachments,
+		MessageID:   input.MessageID,
+		InReplyTo:   envelope.InReplyTo,
+		References:  envelope.References,
+		Mailable:    input.Mailable,
 		Input:       input,
-	}
+	}, nil
+}
 
-	a.mu.Lock()
-	a.Calls = append(a.Calls, call)
-	a.mu.Unlock()
+func addresses(list []*mail.Address) []string {
+	emails := make([]string, len(list))
+	for i, address := range list {
+		emails[i] = address.Address
+	}
 
-	return nil
+	return emails
 }
 
 // Ensure Adapter implements the interface.
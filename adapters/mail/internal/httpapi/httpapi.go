@@ -0,0 +1,152 @@
+// Package httpapi contains the request handling shared by the HTTP API mail adapters.
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gonstruct/providers/entities/mailables"
+	"github.com/gonstruct/providers/mail"
+)
+
+// maxErrorBody limits how much of an error response is read.
+const maxErrorBody = 64 << 10
+
+// Attachment is an attachment read into memory, as the HTTP APIs take attachments inline.
+type Attachment struct {
+	Name    string
+	Mime    string
+	Content string // base64 encoded
+	Raw     []byte
+}
+
+// Attachments reads the content of every attachment.
+func Attachments(ctx context.Context, attachments mailables.AttachmentSlice) ([]Attachment, error) {
+	resolved := make([]Attachment, 0, len(attachments))
+
+	for _, attachment := range attachments {
+		content, err := attachment.ReadContent(ctx)
+		if err != nil {
+			return nil, mail.Err("read attachment", err)
+		}
+
+		resolved = append(resolved, Attachment{
+			Name:    attachment.Name,
+			Mime:    attachment.Mime,
+			Content: base64.StdEncoding.EncodeToString(content),
+			Raw:     content,
+		})
+	}
+
+	return resolved, nil
+}
+
+// JSON creates a POST request with body encoded as JSON, or without a body when body is nil.
+func JSON(ctx context.Context, url string, body any) (*http.Request, error) {
+	var reader io.Reader = http.NoBody
+
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+
+		reader = bytes.NewReader(payload)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	return request, nil
+}
+
+// Do sends the request and turns error responses into a *mail.APIError.
+// The message function extracts the provider's error message from the response body.
+func Do(client *http.Client, provider string, request *http.Request, message func(body []byte) string) error {
+	return DoJSON(client, provider, request, message, nil)
+}
+
+// DoJSON is Do that decodes a successful JSON response into result, unless result is nil.
+func DoJSON(client *http.Client, provider string, request *http.Request, message func(body []byte) string, result any) error {
+	_, err := DoHeader(client, provider, request, message, result)
+
+	return err
+}
+
+// DoHeader is DoJSON that also returns the headers of a successful response,
+// for providers that return the message ID in a header.
+func DoHeader(client *http.Client, provider string, request *http.Request, message func(body []byte) string, result any) (http.Header, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		if result == nil {
+			_, _ = io.Copy(io.Discard, response.Body)
+
+			return response.Header, nil
+		}
+
+		// An empty body leaves result unchanged
+		if err := json.NewDecoder(response.Body).Decode(result); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to decode %s response: %w", provider, err)
+		}
+
+		return response.Header, nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBody))
+
+	apiError := &mail.APIError{
+		Provider:   provider,
+		StatusCode: response.StatusCode,
+		Message:    message(body),
+	}
+
+	// Providers answer with plain text from proxies and load balancers
+	if apiError.Message == "" && !json.Valid(body) {
+		apiError.Message = strings.TrimSpace(string(body))
+	}
+
+	return nil, apiError
+}
+
+// BaseURL returns base without a trailing slash, or fallback when base is empty.
+func BaseURL(base, fallback string) string {
+	if base == "" {
+		return fallback
+	}
+
+	return strings.TrimRight(base, "/")
+}
+
+// Keys returns the keys of values in sorted order, so requests are built deterministically.
+func Keys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
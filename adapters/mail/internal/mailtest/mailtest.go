@@ -0,0 +1,71 @@
+// Package mailtest contains the fixtures and assertions shared by the tests of the HTTP API mail adapters.
+package mailtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/entities/mailables"
+	"github.com/gonstruct/providers/mail"
+)
+
+// Input returns a message that uses every envelope field the adapters map, with one attachment.
+func Input() entities.MailInput {
+	return entities.MailInput{
+		Envelope: mailables.Envelope{
+			From:     mailables.Address("billing@example.com", "Billing"),
+			To:       mailables.Addresses("anna@example.com", "bob@example.com"),
+			Cc:       mailables.Addresses("finance@example.com"),
+			Bcc:      mailables.Addresses("archive@example.com"),
+			ReplyTo:  mailables.Address("support@example.com", ""),
+			Subject:  "Your invoice",
+			Tags:     []string{"invoice", "monthly"},
+			Metadata: map[string]string{"invoice_id": "2026-001"},
+			Headers:  map[string]string{"X-Entity-Ref": "abc"},
+		},
+		Attachments: mailables.Attachments(mailables.Attachment(
+			mailables.WithName("invoice.pdf"),
+			mailables.WithMime("application/pdf"),
+			mailables.WithContent([]byte("%PDF")),
+		)),
+		Html: *bytes.NewBufferString("<p>Hi</p>"),
+	}
+}
+
+// AssertJSON compares a request body with the expected JSON, ignoring formatting and key order.
+func AssertJSON(t testing.TB, got []byte, want string) {
+	t.Helper()
+
+	var gotValue, wantValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("request body is not JSON: %v\n%s", err, got)
+	}
+
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("want is not JSON: %v", err)
+	}
+
+	gotJSON, _ := json.Marshal(gotValue)
+	wantJSON, _ := json.Marshal(wantValue)
+
+	if !bytes.Equal(gotJSON, wantJSON) {
+		t.Errorf("request body =\n%s\nwant\n%s", gotJSON, wantJSON)
+	}
+}
+
+// AssertAPIError checks that err is want, wrapping a *mail.APIError with the status and the message of the response.
+func AssertAPIError(t testing.TB, err error, status int, want error, message string) {
+	t.Helper()
+
+	if !errors.Is(err, want) {
+		t.Errorf("status %d: Send() error = %v, want %v", status, err, want)
+	}
+
+	var apiError *mail.APIError
+	if !errors.As(err, &apiError) || apiError.StatusCode != status || apiError.Message != message {
+		t.Errorf("status %d: Send() error = %#v, want APIError with message %q", status, apiError, message)
+	}
+}
@@ -0,0 +1,23 @@
+package mailgun
+
+import (
+	"net/http"
+
+	"github.com/gonstruct/providers/mail"
+)
+
+// DefaultBaseURL is the Mailgun US region API used when BaseURL is empty.
+// Use "https://api.eu.mailgun.net" for domains in the EU region.
+const DefaultBaseURL = "https://api.mailgun.net"
+
+type Adapter struct {
+	Domain     string
+	APIKey     string
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// MaxMessageSize returns the Mailgun limit of 25 MB per message.
+func (adapter *Adapter) MaxMessageSize() int64 {
+	return 25 * mail.MB
+}
@@ -0,0 +1,169 @@
+package mailgun_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gonstruct/providers/adapters/mail/internal/mailtest"
+	"github.com/gonstruct/providers/adapters/mail/mailgun"
+	"github.com/gonstruct/providers/entities/mailables"
+	"github.com/gonstruct/providers/mail"
+)
+
+func TestSend_Payload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v3/mg.example.com/messages" {
+			t.Errorf("request = %s %s, want POST /v3/mg.example.com/messages", r.Method, r.URL.Path)
+		}
+
+		if username, password, ok := r.BasicAuth(); !ok || username != "api" || password != "key-123" {
+			t.Errorf("BasicAuth() = %q, %q, want api, key-123", username, password)
+		}
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("ParseMultipartForm() error = %v", err)
+		}
+
+		want := map[string][]string{
+			"from":           {`"Billing" <billing@example.com>`},
+			"subject":        {"Your invoice"},
+			"html":           {"<p>Hi</p>"},
+			"to":             {"<anna@example.com>", "<bob@example.com>"},
+			"cc":             {"<finance@example.com>"},
+			"bcc":            {"<archive@example.com>"},
+			"h:Reply-To":     {"<support@example.com>"},
+			"o:tag":          {"invoice", "monthly"},
+			"v:invoice_id":   {"2026-001"},
+			"h:X-Entity-Ref": {"abc"},
+		}
+
+		if !reflect.DeepEqual(r.MultipartForm.Value, want) {
+			t.Errorf("form values = %v, want %v", r.MultipartForm.Value, want)
+		}
+
+		files := r.MultipartForm.File["attachment"]
+		if len(files) != 1 {
+			t.Fatalf("attachment count = %d, want 1", len(files))
+		}
+
+		if files[0].Filename != "invoice.pdf" || files[0].Header.Get("Content-Type") != "application/pdf" {
+			t.Errorf("attachment = %q (%s), want invoice.pdf (application/pdf)", files[0].Filename, files[0].Header.Get("Content-Type"))
+		}
+
+		file, _ := files[0].Open()
+		content, _ := io.ReadAll(file)
+
+		if string(content) != "%PDF" {
+			t.Errorf("attachment content = %q, want %q", content, "%PDF")
+		}
+
+		_, _ = w.Write([]byte(`{"id":"<20260101.1@mg.example.com>","message":"Queued. Thank you."}`))
+	}))
+	defer server.Close()
+
+	adapter := &mailgun.Adapter{Domain: "mg.example.com", APIKey: "key-123", BaseURL: server.URL + "/"}
+	if err := adapter.Send(context.Background(), mailtest.Input()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+}
+
+func TestSend_Errors(t *testing.T) {
+	tests := []struct {
+		status  int
+		body    string
+		want    error
+		message string
+	}{
+		{http.StatusUnauthorized, `Forbidden`, mail.ErrUnauthorized, "Forbidden"},
+		{http.StatusBadRequest, `{"message":"'from' parameter is not a valid address. please check documentation"}`, mail.ErrRejected, "'from' parameter is not a valid address. please check documentation"},
+		{http.StatusTooManyRequests, `{"message":"Too many requests"}`, mail.ErrRateLimited, "Too many requests"},
+		{http.StatusInternalServerError, `{"message":"Internal error"}`, mail.ErrSendFailed, "Internal error"},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(tt.status)
+			_, _ = w.Write([]byte(tt.body))
+		}))
+
+		err := (&mailgun.Adapter{Domain: "mg.example.com", BaseURL: server.URL}).Send(context.Background(), mailtest.Input())
+
+		server.Close()
+
+		mailtest.AssertAPIError(t, err, tt.status, tt.want, tt.message)
+	}
+}
+
+func TestSendWithReceipt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("ParseMultipartForm() error = %v", err)
+		}
+
+		if inReplyTo := r.MultipartForm.Value["h:In-Reply-To"]; !reflect.DeepEqual(inReplyTo, []string{"<order-1@example.com>"}) {
+			t.Errorf("h:In-Reply-To = %v, want <order-1@example.com>", inReplyTo)
+		}
+
+		_, _ = w.Write([]byte(`{"id":"<20260101.1@mg.example.com>","message":"Queued. Thank you."}`))
+	}))
+	defer server.Close()
+
+	input := mailtest.Input()
+	input.Envelope = input.Envelope.Threaded("order-1@example.com")
+
+	adapter := &mailgun.Adapter{Domain: "mg.example.com", BaseURL: server.URL}
+
+	receipt, err := adapter.SendWithReceipt(context.Background(), input)
+	if err != nil {
+		t.Fatalf("SendWithReceipt() error = %v", err)
+	}
+
+	if receipt.ProviderID != "20260101.1@mg.example.com" || receipt.MessageID != "<20260101.1@mg.example.com>" {
+		t.Errorf("receipt = %+v, want the Mailgun id as provider ID and Message-ID", receipt)
+	}
+}
+
+// passthrough is a processor that returns the message unchanged.
+type passthrough struct{}
+
+func (passthrough) Process(_ context.Context, _ mailables.Envelope, message []byte) ([]byte, error) {
+	return message, nil
+}
+
+func TestSend_ProcessorsOpenAttachmentsOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/mg.example.com/messages.mime" {
+			t.Errorf("path = %s, want /v3/mg.example.com/messages.mime", r.URL.Path)
+		}
+
+		_, _ = w.Write([]byte(`{"id":"<20260101.1@mg.example.com>","message":"Queued. Thank you."}`))
+	}))
+	defer server.Close()
+
+	opened := 0
+	input := mailtest.Input()
+	input.Processors = []mailables.MessageProcessor{passthrough{}}
+	input.Attachments = mailables.Attachments(mailables.Attachment(
+		mailables.WithName("report.csv"),
+		mailables.WithMime("text/csv"),
+		mailables.WithReader(func() (io.ReadCloser, error) {
+			opened++
+
+			return io.NopCloser(bytes.NewReader([]byte("a,b"))), nil
+		}),
+	))
+
+	adapter := &mailgun.Adapter{Domain: "mg.example.com", APIKey: "key-123", BaseURL: server.URL + "/"}
+	if err := adapter.Send(context.Background(), input); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if opened != 1 {
+		t.Errorf("attachment opened %d times, want 1", opened)
+	}
+}
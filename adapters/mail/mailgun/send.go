@@ -0,0 +1,207 @@
+package mailgun
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+
+	"github.com/gonstruct/providers/adapters/mail/internal/httpapi"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/entities/mailables"
+	"github.com/gonstruct/providers/mail"
+)
+
+type field struct {
+	name, value string
+}
+
+type file struct {
+	field string
+	httpapi.Attachment
+}
+
+// Send sends the message with the Mailgun messages API. Tags are sent as "o:tag",
+// metadata as "v:" variables and headers as "h:" fields.
+//
+// Messages with processors, like S/MIME or OpenPGP, are built and processed locally and
+// sent as a raw MIME message with the messages.mime API.
+func (adapter *Adapter) Send(ctx context.Context, input entities.MailInput) error {
+	_, err := adapter.SendWithReceipt(ctx, input)
+
+	return err
+}
+
+// SendWithReceipt sends the message and returns the Mailgun message ID as provider ID,
+// which is the Message-ID of the message without the angle brackets.
+func (adapter *Adapter) SendWithReceipt(ctx context.Context, input entities.MailInput) (*entities.MailReceipt, error) {
+	if err := mail.Validate(input); err != nil {
+		return nil, err
+	}
+
+	var (
+		fields   []field
+		files    []file
+		endpoint string
+		err      error
+	)
+
+	if len(input.Processors) > 0 {
+		fields, files, endpoint, err = mimeForm(ctx, input)
+	} else {
+		fields, files, endpoint, err = messageForm(ctx, input)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+
+	form := multipart.NewWriter(&body)
+
+	for _, field := range fields {
+		if err := form.WriteField(field.name, field.value); err != nil {
+			return nil, mail.Err("build Mailgun request", err)
+		}
+	}
+
+	for _, file := range files {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, file.field, file.Name))
+		header.Set("Content-Type", file.Mime)
+
+		part, err := form.CreatePart(header)
+		if err != nil {
+			return nil, mail.Err("build Mailgun request", err)
+		}
+
+		if _, err := part.Write(file.Raw); err != nil {
+			return nil, mail.Err("build Mailgun request", err)
+		}
+	}
+
+	if err := form.Close(); err != nil {
+		return nil, mail.Err("build Mailgun request", err)
+	}
+
+	endpoint = httpapi.BaseURL(adapter.BaseURL, DefaultBaseURL) + "/v3/" + url.PathEscape(adapter.Domain) + endpoint
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &body)
+	if err != nil {
+		return nil, mail.Err("send via Mailgun", err)
+	}
+
+	request.SetBasicAuth("api", adapter.APIKey)
+	request.Header.Set("Content-Type", form.FormDataContentType())
+	request.Header.Set("Accept", "application/json")
+
+	var response struct {
+		ID string `json:"id"`
+	}
+
+	if err := httpapi.DoJSON(adapter.HTTPClient, "Mailgun", request, errorMessage, &response); err != nil {
+		return nil, mail.Err("send via Mailgun", err)
+	}
+
+	receipt := mail.NewReceipt(input, strings.Trim(response.ID, "<>"))
+	if response.ID != "" {
+		receipt.MessageID = mailables.MessageID(response.ID)
+	}
+
+	return receipt, nil
+}
+
+func messageForm(ctx context.Context, input entities.MailInput) ([]field, []file, string, error) {
+	attachments, err := httpapi.Attachments(ctx, input.Attachments)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	fields := []field{
+		{"from", input.Envelope.From.String()},
+		{"subject", input.Envelope.Subject},
+		{"html", input.Html.String()},
+	}
+
+	for _, to := range input.Envelope.To.String() {
+		fields = append(fields, field{"to", to})
+	}
+
+	for _, cc := range input.Envelope.Cc.String() {
+		fields = append(fields, field{"cc", cc})
+	}
+
+	for _, bcc := range input.Envelope.Bcc.String() {
+		fields = append(fields, field{"bcc", bcc})
+	}
+
+	if input.Envelope.ReplyTo != nil {
+		fields = append(fields, field{"h:Reply-To", input.Envelope.ReplyTo.String()})
+	}
+
+	fields = append(fields, options(input)...)
+
+	headers := mail.Headers(input)
+	for _, name := range httpapi.Keys(headers) {
+		fields = append(fields, field{"h:" + name, headers[name]})
+	}
+
+	files := make([]file, len(attachments))
+	for i, attachment := range attachments {
+		files[i] = file{field: "attachment", Attachment: attachment}
+	}
+
+	return fields, files, "/messages", nil
+}
+
+// mimeForm sends the processed message as is, the recipients are only used for delivery.
+func mimeForm(ctx context.Context, input entities.MailInput) ([]field, []file, string, error) {
+	raw, err := mail.Raw(ctx, input)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	var fields []field
+
+	for _, recipient := range mail.Recipients(input) {
+		fields = append(fields, field{"to", recipient})
+	}
+
+	fields = append(fields, options(input)...)
+
+	message := file{field: "message", Attachment: httpapi.Attachment{Name: "message.mime", Mime: "message/rfc822", Raw: raw}}
+
+	return fields, []file{message}, "/messages.mime", nil
+}
+
+func options(input entities.MailInput) []field {
+	var fields []field
+
+	for _, tag := range input.Envelope.Tags {
+		fields = append(fields, field{"o:tag", tag})
+	}
+
+	for _, name := range httpapi.Keys(input.Envelope.Metadata) {
+		fields = append(fields, field{"v:" + name, input.Envelope.Metadata[name]})
+	}
+
+	return fields
+}
+
+func errorMessage(body []byte) string {
+	var response struct {
+		Message string `json:"message"`
+	}
+
+	if json.Unmarshal(body, &response) != nil {
+		return ""
+	}
+
+	return response.Message
+}
@@ -0,0 +1,23 @@
+package postmark
+
+import (
+	"net/http"
+
+	"github.com/gonstruct/providers/mail"
+)
+
+// DefaultBaseURL is the Postmark API used when BaseURL is empty.
+const DefaultBaseURL = "https://api.postmarkapp.com"
+
+type Adapter struct {
+	ServerToken string
+	// MessageStream selects the Postmark message stream, Postmark uses "outbound" when empty
+	MessageStream string
+	BaseURL       string
+	HTTPClient    *http.Client
+}
+
+// MaxMessageSize returns the Postmark limit of 10 MB per message, including attachments after encoding.
+func (adapter *Adapter) MaxMessageSize() int64 {
+	return 10 * mail.MB
+}
@@ -0,0 +1,82 @@
+package postmark_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gonstruct/providers/adapters/mail/internal/mailtest"
+	"github.com/gonstruct/providers/adapters/mail/postmark"
+	"github.com/gonstruct/providers/mail"
+)
+
+func TestSend_Payload(t *testing.T) {
+	var body []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/email" {
+			t.Errorf("request = %s %s, want POST /email", r.Method, r.URL.Path)
+		}
+
+		if token := r.Header.Get("X-Postmark-Server-Token"); token != "server-token" {
+			t.Errorf("X-Postmark-Server-Token = %q, want %q", token, "server-token")
+		}
+
+		body, _ = io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ErrorCode":0,"Message":"OK","MessageID":"b7bc2f4a"}`))
+	}))
+	defer server.Close()
+
+	adapter := &postmark.Adapter{ServerToken: "server-token", MessageStream: "outbound", BaseURL: server.URL}
+	if err := adapter.Send(context.Background(), mailtest.Input()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	want := `{
+		"From": "\"Billing\" <billing@example.com>",
+		"To": "<anna@example.com>, <bob@example.com>",
+		"Cc": "<finance@example.com>",
+		"Bcc": "<archive@example.com>",
+		"ReplyTo": "<support@example.com>",
+		"Subject": "Your invoice",
+		"HtmlBody": "<p>Hi</p>",
+		"Tag": "invoice",
+		"Metadata": {"invoice_id": "2026-001"},
+		"Headers": [{"Name": "X-Entity-Ref", "Value": "abc"}],
+		"Attachments": [{"Name": "invoice.pdf", "Content": "JVBERg==", "ContentType": "application/pdf"}],
+		"MessageStream": "outbound"
+	}`
+
+	mailtest.AssertJSON(t, body, want)
+}
+
+func TestSend_Errors(t *testing.T) {
+	tests := []struct {
+		status  int
+		body    string
+		want    error
+		message string
+	}{
+		{http.StatusUnauthorized, `{"ErrorCode":10,"Message":"Bad or missing Server API token."}`, mail.ErrUnauthorized, "Bad or missing Server API token."},
+		{http.StatusUnprocessableEntity, `{"ErrorCode":300,"Message":"Invalid email request"}`, mail.ErrRejected, "Invalid email request"},
+		{http.StatusTooManyRequests, `{}`, mail.ErrRateLimited, ""},
+		{http.StatusInternalServerError, `internal error`, mail.ErrSendFailed, "internal error"},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(tt.status)
+			_, _ = w.Write([]byte(tt.body))
+		}))
+
+		err := (&postmark.Adapter{BaseURL: server.URL}).Send(context.Background(), mailtest.Input())
+
+		server.Close()
+
+		mailtest.AssertAPIError(t, err, tt.status, tt.want, tt.message)
+	}
+}
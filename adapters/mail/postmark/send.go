@@ -0,0 +1,119 @@
+package postmark
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/gonstruct/providers/adapters/mail/internal/httpapi"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/mail"
+)
+
+type message struct {
+	From          string            `json:"From"`
+	To            string            `json:"To"`
+	Cc            string            `json:"Cc,omitempty"`
+	Bcc           string            `json:"Bcc,omitempty"`
+	ReplyTo       string            `json:"ReplyTo,omitempty"`
+	Subject       string            `json:"Subject"`
+	HtmlBody      string            `json:"HtmlBody"`
+	Tag           string            `json:"Tag,omitempty"`
+	Metadata      map[string]string `json:"Metadata,omitempty"`
+	Headers       []header          `json:"Headers,omitempty"`
+	Attachments   []attachment      `json:"Attachments,omitempty"`
+	MessageStream string            `json:"MessageStream,omitempty"`
+}
+
+type header struct {
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+}
+
+type attachment struct {
+	Name        string `json:"Name"`
+	Content     string `json:"Content"`
+	ContentType string `json:"ContentType"`
+}
+
+// Send sends the message with the Postmark email API. Postmark supports a single tag
+// per message, so only the first tag of the envelope is sent.
+func (adapter *Adapter) Send(ctx context.Context, input entities.MailInput) error {
+	_, err := adapter.SendWithReceipt(ctx, input)
+
+	return err
+}
+
+// SendWithReceipt sends the message and returns the Postmark MessageID as provider ID.
+func (adapter *Adapter) SendWithReceipt(ctx context.Context, input entities.MailInput) (*entities.MailReceipt, error) {
+	if err := mail.Validate(input); err != nil {
+		return nil, err
+	}
+
+	if len(input.Processors) > 0 {
+		return nil, mail.Err("send via Postmark", mail.ErrRawUnsupported)
+	}
+
+	attachments, err := httpapi.Attachments(ctx, input.Attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	body := message{
+		From:          input.Envelope.From.String(),
+		To:            strings.Join(input.Envelope.To.String(), ", "),
+		Cc:            strings.Join(input.Envelope.Cc.String(), ", "),
+		Bcc:           strings.Join(input.Envelope.Bcc.String(), ", "),
+		Subject:       input.Envelope.Subject,
+		HtmlBody:      input.Html.String(),
+		Metadata:      input.Envelope.Metadata,
+		MessageStream: adapter.MessageStream,
+	}
+
+	if input.Envelope.ReplyTo != nil {
+		body.ReplyTo = input.Envelope.ReplyTo.String()
+	}
+
+	if len(input.Envelope.Tags) > 0 {
+		body.Tag = input.Envelope.Tags[0]
+	}
+
+	headers := mail.Headers(input)
+	for _, name := range httpapi.Keys(headers) {
+		body.Headers = append(body.Headers, header{Name: name, Value: headers[name]})
+	}
+
+	for _, file := range attachments {
+		body.Attachments = append(body.Attachments, attachment{Name: file.Name, Content: file.Content, ContentType: file.Mime})
+	}
+
+	request, err := httpapi.JSON(ctx, httpapi.BaseURL(adapter.BaseURL, DefaultBaseURL)+"/email", body)
+	if err != nil {
+		return nil, mail.Err("send via Postmark", err)
+	}
+
+	request.Header.Set("X-Postmark-Server-Token", adapter.ServerToken)
+
+	var response struct {
+		MessageID string `json:"MessageID"`
+	}
+
+	if err := httpapi.DoJSON(adapter.HTTPClient, "Postmark", request, errorMessage, &response); err != nil {
+		return nil, mail.Err("send via Postmark", err)
+	}
+
+	return mail.NewReceipt(input, response.MessageID), nil
+}
+
+func errorMessage(body []byte) string {
+	var response struct {
+		ErrorCode int    `json:"ErrorCode"`
+		Message   string `json:"Message"`
+	}
+
+	if json.Unmarshal(body, &response) != nil {
+		return ""
+	}
+
+	return response.Message
+}
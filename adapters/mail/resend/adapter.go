@@ -0,0 +1,21 @@
+package resend
+
+import (
+	"net/http"
+
+	"github.com/gonstruct/providers/mail"
+)
+
+// DefaultBaseURL is the Resend API used when BaseURL is empty.
+const DefaultBaseURL = "https://api.resend.com"
+
+type Adapter struct {
+	APIKey     string
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// MaxMessageSize returns the Resend limit of 40 MB per message, including attachments after encoding.
+func (adapter *Adapter) MaxMessageSize() int64 {
+	return 40 * mail.MB
+}
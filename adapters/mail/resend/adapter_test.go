@@ -0,0 +1,137 @@
+package resend_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gonstruct/providers/adapters/mail/internal/mailtest"
+	"github.com/gonstruct/providers/adapters/mail/resend"
+	"github.com/gonstruct/providers/mail"
+)
+
+func TestSend_Payload(t *testing.T) {
+	var body []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/emails" {
+			t.Errorf("request = %s %s, want POST /emails", r.Method, r.URL.Path)
+		}
+
+		if authorization := r.Header.Get("Authorization"); authorization != "Bearer api-key" {
+			t.Errorf("Authorization = %q, want %q", authorization, "Bearer api-key")
+		}
+
+		body, _ = io.ReadAll(r.Body)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	adapter := &resend.Adapter{APIKey: "api-key", BaseURL: server.URL}
+	if err := adapter.Send(context.Background(), mailtest.Input()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	want := `{
+		"from": "\"Billing\" <billing@example.com>",
+		"to": ["<anna@example.com>", "<bob@example.com>"],
+		"cc": ["<finance@example.com>"],
+		"bcc": ["<archive@example.com>"],
+		"reply_to": ["<support@example.com>"],
+		"subject": "Your invoice",
+		"html": "<p>Hi</p>",
+		"headers": {"X-Entity-Ref": "abc"},
+		"attachments": [{"filename": "invoice.pdf", "content": "JVBERg==", "content_type": "application/pdf"}],
+		"tags": [
+			{"name": "invoice", "value": "true"},
+			{"name": "monthly", "value": "true"},
+			{"name": "invoice_id", "value": "2026-001"}
+		]
+	}`
+
+	mailtest.AssertJSON(t, body, want)
+}
+
+func TestSend_Errors(t *testing.T) {
+	tests := []struct {
+		status  int
+		body    string
+		want    error
+		message string
+	}{
+		{http.StatusUnauthorized, `{"statusCode":401,"name":"missing_api_key","message":"Missing API key in the authorization header"}`, mail.ErrUnauthorized, "Missing API key in the authorization header"},
+		{http.StatusUnprocessableEntity, `{"statusCode":422,"name":"validation_error","message":"Invalid from field."}`, mail.ErrRejected, "Invalid from field."},
+		{http.StatusTooManyRequests, `{"statusCode":429,"name":"rate_limit_exceeded","message":"Too many requests."}`, mail.ErrRateLimited, "Too many requests."},
+		{http.StatusInternalServerError, `{"statusCode":500,"name":"internal_server_error","message":"An unexpected error occurred."}`, mail.ErrSendFailed, "An unexpected error occurred."},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(tt.status)
+			_, _ = w.Write([]byte(tt.body))
+		}))
+
+		err := (&resend.Adapter{BaseURL: server.URL}).Send(context.Background(), mailtest.Input())
+
+		server.Close()
+
+		mailtest.AssertAPIError(t, err, tt.status, tt.want, tt.message)
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	var requests []string
+
+	var body struct {
+		ScheduledAt string `json:"scheduled_at"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		if r.URL.Path == "/emails" {
+			if key := r.Header.Get("Idempotency-Key"); key != "reminder-42" {
+				t.Errorf("Idempotency-Key = %q, want %q", key, "reminder-42")
+			}
+
+			_ = json.NewDecoder(r.Body).Decode(&body)
+		}
+
+		_, _ = io.WriteString(w, `{"id":"49a3999c"}`)
+	}))
+	defer server.Close()
+
+	adapter := &resend.Adapter{APIKey: "api-key", BaseURL: server.URL}
+
+	input := mailtest.Input()
+	input.IdempotencyKey = "reminder-42"
+
+	at := time.Date(2026, time.March, 5, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	id, err := adapter.Schedule(context.Background(), input, at)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+
+	if id != "49a3999c" {
+		t.Errorf("Schedule() = %q, want %q", id, "49a3999c")
+	}
+
+	if body.ScheduledAt != "2026-03-05T08:00:00Z" {
+		t.Errorf("scheduled_at = %q, want %q", body.ScheduledAt, "2026-03-05T08:00:00Z")
+	}
+
+	if err := adapter.CancelScheduled(context.Background(), id); err != nil {
+		t.Fatalf("CancelScheduled() error = %v", err)
+	}
+
+	if want := []string{"POST /emails", "POST /emails/49a3999c/cancel"}; !slices.Equal(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+}
@@ -0,0 +1,171 @@
+package resend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gonstruct/providers/adapters/mail/internal/httpapi"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/mail"
+)
+
+type message struct {
+	From        string            `json:"from"`
+	To          []string          `json:"to"`
+	Cc          []string          `json:"cc,omitempty"`
+	Bcc         []string          `json:"bcc,omitempty"`
+	ReplyTo     []string          `json:"reply_to,omitempty"`
+	Subject     string            `json:"subject"`
+	Html        string            `json:"html"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Attachments []attachment      `json:"attachments,omitempty"`
+	Tags        []tag             `json:"tags,omitempty"`
+	ScheduledAt string            `json:"scheduled_at,omitempty"`
+}
+
+type attachment struct {
+	Filename    string `json:"filename"`
+	Content     string `json:"content"`
+	ContentType string `json:"content_type"`
+}
+
+type tag struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Send sends the message with the Resend emails API. Resend tags are name/value pairs:
+// metadata is sent as tags and every envelope tag is sent with the value "true".
+// The idempotency key is sent in the Idempotency-Key header, so Resend drops duplicates.
+func (adapter *Adapter) Send(ctx context.Context, input entities.MailInput) error {
+	_, err := adapter.send(ctx, input, time.Time{})
+
+	return err
+}
+
+// SendWithReceipt sends the message and returns the Resend email ID as provider ID.
+func (adapter *Adapter) SendWithReceipt(ctx context.Context, input entities.MailInput) (*entities.MailReceipt, error) {
+	id, err := adapter.send(ctx, input, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	return mail.NewReceipt(input, id), nil
+}
+
+// Schedule sends the message with a scheduled_at time and returns the Resend email ID.
+func (adapter *Adapter) Schedule(ctx context.Context, input entities.MailInput, at time.Time) (string, error) {
+	return adapter.send(ctx, input, at)
+}
+
+// CancelScheduled cancels a scheduled email by its Resend email ID.
+func (adapter *Adapter) CancelScheduled(ctx context.Context, id string) error {
+	endpoint := httpapi.BaseURL(adapter.BaseURL, DefaultBaseURL) + "/emails/" + url.PathEscape(id) + "/cancel"
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return mail.Err("cancel via Resend", err)
+	}
+
+	request.Header.Set("Authorization", "Bearer "+adapter.APIKey)
+	request.Header.Set("Accept", "application/json")
+
+	if err := httpapi.Do(adapter.HTTPClient, "Resend", request, errorMessage); err != nil {
+		return mail.Err("cancel via Resend", err)
+	}
+
+	return nil
+}
+
+// MaxScheduleDelay returns 72 hours, how far ahead Resend accepts scheduled emails.
+func (adapter *Adapter) MaxScheduleDelay() time.Duration {
+	return 72 * time.Hour
+}
+
+func (adapter *Adapter) send(ctx context.Context, input entities.MailInput, at time.Time) (string, error) {
+	if err := mail.Validate(input); err != nil {
+		return "", err
+	}
+
+	if len(input.Processors) > 0 {
+		return "", mail.Err("send via Resend", mail.ErrRawUnsupported)
+	}
+
+	attachments, err := httpapi.Attachments(ctx, input.Attachments)
+	if err != nil {
+		return "", err
+	}
+
+	body := message{
+		From:    input.Envelope.From.String(),
+		To:      input.Envelope.To.String(),
+		Subject: input.Envelope.Subject,
+		Html:    input.Html.String(),
+		Headers: mail.Headers(input),
+	}
+
+	if len(input.Envelope.Cc) > 0 {
+		body.Cc = input.Envelope.Cc.String()
+	}
+
+	if len(input.Envelope.Bcc) > 0 {
+		body.Bcc = input.Envelope.Bcc.String()
+	}
+
+	if input.Envelope.ReplyTo != nil {
+		body.ReplyTo = []string{input.Envelope.ReplyTo.String()}
+	}
+
+	for _, name := range input.Envelope.Tags {
+		body.Tags = append(body.Tags, tag{Name: name, Value: "true"})
+	}
+
+	for _, name := range httpapi.Keys(input.Envelope.Metadata) {
+		body.Tags = append(body.Tags, tag{Name: name, Value: input.Envelope.Metadata[name]})
+	}
+
+	for _, file := range attachments {
+		body.Attachments = append(body.Attachments, attachment{Filename: file.Name, Content: file.Content, ContentType: file.Mime})
+	}
+
+	if !at.IsZero() {
+		body.ScheduledAt = at.UTC().Format(time.RFC3339)
+	}
+
+	request, err := httpapi.JSON(ctx, httpapi.BaseURL(adapter.BaseURL, DefaultBaseURL)+"/emails", body)
+	if err != nil {
+		return "", mail.Err("send via Resend", err)
+	}
+
+	request.Header.Set("Authorization", "Bearer "+adapter.APIKey)
+
+	if input.IdempotencyKey != "" {
+		request.Header.Set("Idempotency-Key", input.IdempotencyKey)
+	}
+
+	var response struct {
+		ID string `json:"id"`
+	}
+
+	if err := httpapi.DoJSON(adapter.HTTPClient, "Resend", request, errorMessage, &response); err != nil {
+		return "", mail.Err("send via Resend", err)
+	}
+
+	return response.ID, nil
+}
+
+func errorMessage(body []byte) string {
+	var response struct {
+		Name    string `json:"name"`
+		Message string `json:"message"`
+	}
+
+	if json.Unmarshal(body, &response) != nil {
+		return ""
+	}
+
+	return response.Message
+}
@@ -0,0 +1,21 @@
+package sendgrid
+
+import (
+	"net/http"
+
+	"github.com/gonstruct/providers/mail"
+)
+
+// DefaultBaseURL is the SendGrid API used when BaseURL is empty.
+const DefaultBaseURL = "https://api.sendgrid.com"
+
+type Adapter struct {
+	APIKey     string
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// MaxMessageSize returns the SendGrid limit of 30 MB per message, including attachments after encoding.
+func (adapter *Adapter) MaxMessageSize() int64 {
+	return 30 * mail.MB
+}
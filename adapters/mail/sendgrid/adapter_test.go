@@ -0,0 +1,183 @@
+package sendgrid_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gonstruct/providers/adapters/mail/internal/mailtest"
+	"github.com/gonstruct/providers/adapters/mail/sendgrid"
+	"github.com/gonstruct/providers/mail"
+)
+
+func TestSend_Payload(t *testing.T) {
+	var body []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v3/mail/send" {
+			t.Errorf("request = %s %s, want POST /v3/mail/send", r.Method, r.URL.Path)
+		}
+
+		if authorization := r.Header.Get("Authorization"); authorization != "Bearer api-key" {
+			t.Errorf("Authorization = %q, want %q", authorization, "Bearer api-key")
+		}
+
+		body, _ = io.ReadAll(r.Body)
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	adapter := &sendgrid.Adapter{APIKey: "api-key", BaseURL: server.URL}
+	if err := adapter.Send(context.Background(), mailtest.Input()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	want := `{
+		"personalizations": [{
+			"to": [{"email": "anna@example.com"}, {"email": "bob@example.com"}],
+			"cc": [{"email": "finance@example.com"}],
+			"bcc": [{"email": "archive@example.com"}]
+		}],
+		"from": {"email": "billing@example.com", "name": "Billing"},
+		"reply_to": {"email": "support@example.com"},
+		"subject": "Your invoice",
+		"content": [{"type": "text/html", "value": "<p>Hi</p>"}],
+		"attachments": [{"content": "JVBERg==", "type": "application/pdf", "filename": "invoice.pdf", "disposition": "attachment"}],
+		"categories": ["invoice", "monthly"],
+		"custom_args": {"invoice_id": "2026-001"},
+		"headers": {"X-Entity-Ref": "abc"}
+	}`
+
+	mailtest.AssertJSON(t, body, want)
+}
+
+func TestSend_Errors(t *testing.T) {
+	tests := []struct {
+		status  int
+		body    string
+		want    error
+		message string
+	}{
+		{http.StatusUnauthorized, `{"errors":[{"message":"The provided authorization grant is invalid, expired, or revoked","field":null}]}`, mail.ErrUnauthorized, "The provided authorization grant is invalid, expired, or revoked"},
+		{http.StatusBadRequest, `{"errors":[{"message":"Does not contain a valid address.","field":"from.email"}]}`, mail.ErrRejected, "from.email: Does not contain a valid address."},
+		{http.StatusTooManyRequests, `{"errors":[{"message":"too many requests"}]}`, mail.ErrRateLimited, "too many requests"},
+		{http.StatusServiceUnavailable, `upstream unavailable`, mail.ErrSendFailed, "upstream unavailable"},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(tt.status)
+			_, _ = w.Write([]byte(tt.body))
+		}))
+
+		err := (&sendgrid.Adapter{BaseURL: server.URL}).Send(context.Background(), mailtest.Input())
+
+		server.Close()
+
+		mailtest.AssertAPIError(t, err, tt.status, tt.want, tt.message)
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	var requests []string
+
+	var send, cancel map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+
+		switch r.URL.Path {
+		case "/v3/mail/batch":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, `{"batch_id":"batch-1"}`)
+		case "/v3/mail/send":
+			_ = json.NewDecoder(r.Body).Decode(&send)
+			w.WriteHeader(http.StatusAccepted)
+		case "/v3/user/scheduled_sends":
+			_ = json.NewDecoder(r.Body).Decode(&cancel)
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	adapter := &sendgrid.Adapter{APIKey: "api-key", BaseURL: server.URL}
+	at := time.Date(2026, time.March, 5, 9, 0, 0, 0, time.UTC)
+
+	id, err := adapter.Schedule(context.Background(), mailtest.Input(), at)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+
+	if id != "batch-1" {
+		t.Errorf("Schedule() = %q, want %q", id, "batch-1")
+	}
+
+	if send["batch_id"] != "batch-1" || send["send_at"] != float64(at.Unix()) {
+		t.Errorf("send batch_id = %v, send_at = %v", send["batch_id"], send["send_at"])
+	}
+
+	if err := adapter.CancelScheduled(context.Background(), id); err != nil {
+		t.Fatalf("CancelScheduled() error = %v", err)
+	}
+
+	if cancel["batch_id"] != "batch-1" || cancel["status"] != "cancel" {
+		t.Errorf("cancel = %v", cancel)
+	}
+
+	if want := []string{"/v3/mail/batch", "/v3/mail/send", "/v3/user/scheduled_sends"}; !slices.Equal(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+}
+
+func TestSendWithReceipt(t *testing.T) {
+	var body []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+
+		w.Header().Set("X-Message-Id", "sg-message-1")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	input := mailtest.Input()
+	input.MessageID = "<reply-1@example.com>"
+	input.Envelope = input.Envelope.Threaded("<order-1@example.com>", "<thread-1@example.com>")
+
+	receipt, err := (&sendgrid.Adapter{BaseURL: server.URL}).SendWithReceipt(context.Background(), input)
+	if err != nil {
+		t.Fatalf("SendWithReceipt() error = %v", err)
+	}
+
+	if receipt.ProviderID != "sg-message-1" || receipt.MessageID != "<reply-1@example.com>" {
+		t.Errorf("receipt = %+v, want the X-Message-Id and the Message-ID", receipt)
+	}
+
+	if len(receipt.Accepted) != 4 || receipt.SentAt.IsZero() {
+		t.Errorf("receipt = %+v, want every recipient accepted", receipt)
+	}
+
+	var payload struct {
+		Headers map[string]string `json:"headers"`
+	}
+
+	_ = json.Unmarshal(body, &payload)
+
+	want := map[string]string{
+		"X-Entity-Ref": "abc",
+		"Message-ID":   "<reply-1@example.com>",
+		"In-Reply-To":  "<order-1@example.com>",
+		"References":   "<thread-1@example.com> <order-1@example.com>",
+	}
+
+	if !maps.Equal(payload.Headers, want) {
+		t.Errorf("headers = %v, want %v", payload.Headers, want)
+	}
+}
@@ -0,0 +1,207 @@
+package sendgrid
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	netmail "net/mail"
+	"strings"
+	"time"
+
+	"github.com/gonstruct/providers/adapters/mail/internal/httpapi"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/mail"
+)
+
+type message struct {
+	Personalizations []personalization `json:"personalizations"`
+	From             address           `json:"from"`
+	ReplyTo          *address          `json:"reply_to,omitempty"`
+	Subject          string            `json:"subject"`
+	Content          []content         `json:"content"`
+	Attachments      []attachment      `json:"attachments,omitempty"`
+	Categories       []string          `json:"categories,omitempty"`
+	CustomArgs       map[string]string `json:"custom_args,omitempty"`
+	Headers          map[string]string `json:"headers,omitempty"`
+	SendAt           int64             `json:"send_at,omitempty"`
+	BatchID          string            `json:"batch_id,omitempty"`
+}
+
+type personalization struct {
+	To  []address `json:"to"`
+	Cc  []address `json:"cc,omitempty"`
+	Bcc []address `json:"bcc,omitempty"`
+}
+
+type address struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type content struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type attachment struct {
+	Content     string `json:"content"`
+	Type        string `json:"type"`
+	Filename    string `json:"filename"`
+	Disposition string `json:"disposition"`
+}
+
+// Send sends the message with the SendGrid v3 mail send API. Tags are sent as categories
+// and metadata as custom arguments.
+func (adapter *Adapter) Send(ctx context.Context, input entities.MailInput) error {
+	_, err := adapter.send(ctx, input, "", time.Time{})
+
+	return err
+}
+
+// SendWithReceipt sends the message and returns the X-Message-Id of the response as provider ID,
+// the ID SendGrid uses in its event webhooks.
+func (adapter *Adapter) SendWithReceipt(ctx context.Context, input entities.MailInput) (*entities.MailReceipt, error) {
+	id, err := adapter.send(ctx, input, "", time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	return mail.NewReceipt(input, id), nil
+}
+
+// Schedule creates a SendGrid batch for the message and sends it with a send_at time.
+// The batch ID is returned, as SendGrid cancels scheduled messages by batch.
+func (adapter *Adapter) Schedule(ctx context.Context, input entities.MailInput, at time.Time) (string, error) {
+	var batch struct {
+		BatchID string `json:"batch_id"`
+	}
+
+	if _, err := adapter.post(ctx, "/v3/mail/batch", nil, &batch); err != nil {
+		return "", mail.Err("schedule via SendGrid", err)
+	}
+
+	if _, err := adapter.send(ctx, input, batch.BatchID, at); err != nil {
+		return "", err
+	}
+
+	return batch.BatchID, nil
+}
+
+// CancelScheduled cancels the messages of a batch returned by Schedule.
+func (adapter *Adapter) CancelScheduled(ctx context.Context, id string) error {
+	body := map[string]string{"batch_id": id, "status": "cancel"}
+
+	if _, err := adapter.post(ctx, "/v3/user/scheduled_sends", body, nil); err != nil {
+		return mail.Err("cancel via SendGrid", err)
+	}
+
+	return nil
+}
+
+// MaxScheduleDelay returns 72 hours, how far ahead SendGrid accepts send_at times.
+func (adapter *Adapter) MaxScheduleDelay() time.Duration {
+	return 72 * time.Hour
+}
+
+func (adapter *Adapter) send(ctx context.Context, input entities.MailInput, batchID string, at time.Time) (string, error) {
+	if err := mail.Validate(input); err != nil {
+		return "", err
+	}
+
+	if len(input.Processors) > 0 {
+		return "", mail.Err("send via SendGrid", mail.ErrRawUnsupported)
+	}
+
+	attachments, err := httpapi.Attachments(ctx, input.Attachments)
+	if err != nil {
+		return "", err
+	}
+
+	body := message{
+		Personalizations: []personalization{{
+			To:  addresses(input.Envelope.To),
+			Cc:  addresses(input.Envelope.Cc),
+			Bcc: addresses(input.Envelope.Bcc),
+		}},
+		From:       address{Email: input.Envelope.From.Address, Name: input.Envelope.From.Name},
+		Subject:    input.Envelope.Subject,
+		Content:    []content{{Type: "text/html", Value: input.Html.String()}},
+		Categories: input.Envelope.Tags,
+		CustomArgs: input.Envelope.Metadata,
+		Headers:    mail.Headers(input),
+	}
+
+	if input.Envelope.ReplyTo != nil {
+		body.ReplyTo = &address{Email: input.Envelope.ReplyTo.Address, Name: input.Envelope.ReplyTo.Name}
+	}
+
+	for _, file := range attachments {
+		body.Attachments = append(body.Attachments, attachment{
+			Content:     file.Content,
+			Type:        file.Mime,
+			Filename:    file.Name,
+			Disposition: "attachment",
+		})
+	}
+
+	if !at.IsZero() {
+		body.SendAt = at.Unix()
+		body.BatchID = batchID
+	}
+
+	header, err := adapter.post(ctx, "/v3/mail/send", body, nil)
+	if err != nil {
+		return "", mail.Err("send via SendGrid", err)
+	}
+
+	return header.Get("X-Message-Id"), nil
+}
+
+func (adapter *Adapter) post(ctx context.Context, path string, body, result any) (http.Header, error) {
+	request, err := httpapi.JSON(ctx, httpapi.BaseURL(adapter.BaseURL, DefaultBaseURL)+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Authorization", "Bearer "+adapter.APIKey)
+
+	return httpapi.DoHeader(adapter.HTTPClient, "SendGrid", request, errorMessage, result)
+}
+
+func addresses(slice []*netmail.Address) []address {
+	if len(slice) == 0 {
+		return nil
+	}
+
+	converted := make([]address, len(slice))
+	for i, a := range slice {
+		converted[i] = address{Email: a.Address, Name: a.Name}
+	}
+
+	return converted
+}
+
+func errorMessage(body []byte) string {
+	var response struct {
+		Errors []struct {
+			Message string `json:"message"`
+			Field   string `json:"field"`
+		} `json:"errors"`
+	}
+
+	if json.Unmarshal(body, &response) != nil {
+		return ""
+	}
+
+	messages := make([]string, 0, len(response.Errors))
+
+	for _, e := range response.Errors {
+		if e.Field != "" {
+			messages = append(messages, e.Field+": "+e.Message)
+		} else {
+			messages = append(messages, e.Message)
+		}
+	}
+
+	return strings.Join(messages, "; ")
+}
@@ -1,8 +1,24 @@
 package smtp
 
+import "github.com/gonstruct/providers/mail"
+
+// DefaultMaxMessageSize is the size many receiving servers accept, 25 MB.
+const DefaultMaxMessageSize = 25 * mail.MB
+
 type Adapter struct {
 	Host     string
 	Port     int
 	Username string
 	Password string
+	// SizeLimit is the message size accepted by the server, DefaultMaxMessageSize when 0
+	SizeLimit int64
+}
+
+// MaxMessageSize returns the configured size limit of the server.
+func (adapter *Adapter) MaxMessageSize() int64 {
+	if adapter.SizeLimit == 0 {
+		return DefaultMaxMessageSize
+	}
+
+	return adapter.SizeLimit
 }
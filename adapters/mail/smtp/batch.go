@@ -0,0 +1,54 @@
+package smtp
+
+import (
+	"context"
+
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/mail"
+	"gopkg.in/gomail.v2"
+)
+
+// SendBatch sends every input over a single SMTP connection. When sending fails the
+// connection is closed and a new one is opened for the next message.
+func (adapter *Adapter) SendBatch(ctx context.Context, inputs []entities.MailInput) []error {
+	errs := make([]error, len(inputs))
+	dialer := adapter.dialer()
+
+	var sender gomail.SendCloser
+
+	defer func() {
+		if sender != nil {
+			_ = sender.Close()
+		}
+	}()
+
+	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+
+			continue
+		}
+
+		if err := mail.Validate(input); err != nil {
+			errs[i] = err
+
+			continue
+		}
+
+		if sender == nil {
+			var err error
+			if sender, err = dialer.Dial(); err != nil {
+				errs[i] = mail.Err("send via SMTP", err)
+
+				continue
+			}
+		}
+
+		if errs[i] = send(ctx, sender, input); errs[i] != nil {
+			_ = sender.Close()
+			sender = nil
+		}
+	}
+
+	return errs
+}
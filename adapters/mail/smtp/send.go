@@ -1,8 +1,8 @@
 package smtp
 
 import (
+	"bytes"
 	"context"
-	"io"
 
 	"github.com/gonstruct/providers/entities"
 	"github.com/gonstruct/providers/mail"
@@ -10,58 +10,46 @@ import (
 )
 
 func (adapter *Adapter) Send(ctx context.Context, input entities.MailInput) error {
-	_ = ctx // gomail doesn't support context, but we accept it for interface compatibility
-	message := gomail.NewMessage()
-
-	if input.Envelope.Subject != "" {
-		message.SetHeader("Subject", input.Envelope.Subject)
-	} else {
-		return mail.Err("validate", mail.ErrNoSubject)
-	}
-
-	if input.Envelope.From != nil {
-		message.SetHeader("From", input.Envelope.From.String())
-	} else {
-		return mail.Err("validate", mail.ErrNoSender)
+	if err := mail.Validate(input); err != nil {
+		return err
 	}
 
-	if len(input.Envelope.To) > 0 {
-		message.SetHeader("To", input.Envelope.To.String()...)
-	} else {
-		return mail.Err("validate", mail.ErrNoRecipients)
-	}
-
-	if input.Envelope.ReplyTo != nil {
-		message.SetHeader("Reply-To", input.Envelope.ReplyTo.String())
+	// gomail doesn't support context for the connection, it is only used to open attachments
+	sender, err := adapter.dialer().Dial()
+	if err != nil {
+		return mail.Err("send via SMTP", err)
 	}
+	defer sender.Close()
 
-	if len(input.Envelope.Cc) > 0 {
-		message.SetHeader("Cc", input.Envelope.Cc.String()...)
-	}
+	return send(ctx, sender, input)
+}
 
-	if len(input.Envelope.Bcc) > 0 {
-		message.SetHeader("Bcc", input.Envelope.Bcc.String()...)
-	}
+func (adapter *Adapter) dialer() *gomail.Dialer {
+	return gomail.NewDialer(adapter.Host, adapter.Port, adapter.Username, adapter.Password)
+}
 
-	if len(input.Attachments) > 0 {
-		for _, attachment := range input.Attachments {
-			message.Attach(attachment.Name,
-				gomail.SetHeader(map[string][]string{
-					"Content-Type": {attachment.Mime},
-				}),
-				gomail.SetCopyFunc(func(w io.Writer) error {
-					_, err := w.Write(attachment.Content())
+// send writes a single message to an open connection. Processed messages are built
+// as raw bytes, as the processors sign or encrypt the message as a whole.
+func send(ctx context.Context, sender gomail.Sender, input entities.MailInput) error {
+	if len(input.Processors) > 0 {
+		raw, err := mail.Raw(ctx, input)
+		if err != nil {
+			return err
+		}
 
-					return err
-				}),
-			)
+		if err := sender.Send(input.Envelope.From.Address, mail.Recipients(input), bytes.NewReader(raw)); err != nil {
+			return mail.Err("send via SMTP", err)
 		}
+
+		return nil
 	}
 
-	message.SetBody("text/html", input.Html.String())
+	message, err := mail.NewMessage(ctx, input)
+	if err != nil {
+		return err
+	}
 
-	dialer := gomail.NewDialer(adapter.Host, adapter.Port, adapter.Username, adapter.Password)
-	if err := dialer.DialAndSend(message); err != nil {
+	if err := gomail.Send(sender, message); err != nil {
 		return mail.Err("send via SMTP", err)
 	}
 
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DefaultTable is the table notifications are stored in when Table is empty.
+const DefaultTable = "notifications"
+
+// Adapter stores the database representation of notifications as rows, for display in the application.
+// The route of the notifiable on the "database" channel is stored as the notifiable, usually its ID.
+//
+// The table needs these columns, for example in PostgreSQL:
+//
+//	CREATE TABLE notifications (
+//	    id         VARCHAR(36) PRIMARY KEY,
+//	    type       VARCHAR(255) NOT NULL,
+//	    notifiable VARCHAR(255) NOT NULL,
+//	    data       TEXT NOT NULL,
+//	    created_at TIMESTAMP NOT NULL,
+//	    read_at    TIMESTAMP NULL
+//	);
+type Adapter struct {
+	DB    *sql.DB
+	Table string
+	// Placeholder formats the n-th query argument, starting at 1. Defaults to "?",
+	// use DollarPlaceholder for PostgreSQL
+	Placeholder func(n int) string
+	// Now returns the creation time of notifications, defaults to time.Now
+	Now func() time.Time
+}
+
+// NewAdapter creates a database notification adapter using the default table.
+func NewAdapter(db *sql.DB) *Adapter {
+	return &Adapter{DB: db, Table: DefaultTable}
+}
+
+// DollarPlaceholder formats query arguments as $1, $2, ... for PostgreSQL.
+func DollarPlaceholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+func (adapter *Adapter) table() string {
+	if adapter.Table == "" {
+		return DefaultTable
+	}
+
+	return adapter.Table
+}
+
+// placeholders returns the placeholders for count arguments, starting after offset.
+func (adapter *Adapter) placeholders(offset, count int) []any {
+	placeholders := make([]any, count)
+
+	for i := range count {
+		if adapter.Placeholder == nil {
+			placeholders[i] = "?"
+		} else {
+			placeholders[i] = adapter.Placeholder(offset + i + 1)
+		}
+	}
+
+	return placeholders
+}
+
+func (adapter *Adapter) now() time.Time {
+	if adapter.Now == nil {
+		return time.Now().UTC()
+	}
+
+	return adapter.Now()
+}
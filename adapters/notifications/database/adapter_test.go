@@ -0,0 +1,167 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gonstruct/providers/adapters/notifications/database"
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/notifications"
+)
+
+// recorder is a database/sql driver that records statements and returns its rows for queries.
+type recorder struct {
+	statements []statement
+	rows       [][]driver.Value
+}
+
+type statement struct {
+	query string
+	args  []driver.Value
+}
+
+func (r *recorder) Open(string) (driver.Conn, error) { return r, nil }
+
+func (r *recorder) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+
+func (r *recorder) Close() error { return nil }
+
+func (r *recorder) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func (r *recorder) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	r.record(query, args)
+
+	return driver.RowsAffected(1), nil
+}
+
+func (r *recorder) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	r.record(query, args)
+
+	return &rows{values: r.rows}, nil
+}
+
+func (r *recorder) record(query string, args []driver.NamedValue) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+
+	r.statements = append(r.statements, statement{query: query, args: values})
+}
+
+type rows struct {
+	values [][]driver.Value
+}
+
+func (r *rows) Columns() []string {
+	return []string{"id", "type", "notifiable", "data", "created_at", "read_at"}
+}
+
+func (r *rows) Close() error { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+
+	return nil
+}
+
+func open(t *testing.T, recorder *recorder) *sql.DB {
+	t.Helper()
+
+	db := sql.OpenDB(connector{recorder})
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+type connector struct {
+	recorder *recorder
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return c.recorder, nil }
+
+func (c connector) Driver() driver.Driver { return c.recorder }
+
+type invoicePaid struct{}
+
+func (invoicePaid) Via(contracts.Notifiable) []string {
+	return []string{notifications.ChannelDatabase}
+}
+
+func (invoicePaid) ToDatabase() map[string]any {
+	return map[string]any{"invoice": "2026-001"}
+}
+
+func TestSend(t *testing.T) {
+	recorder := &recorder{}
+	now := time.Date(2026, time.March, 5, 12, 0, 0, 0, time.UTC)
+
+	adapter := database.NewAdapter(open(t, recorder))
+	adapter.Placeholder = database.DollarPlaceholder
+	adapter.Now = func() time.Time { return now }
+
+	err := adapter.Send(context.Background(), entities.NotificationInput{
+		Channel:      notifications.ChannelDatabase,
+		Route:        "42",
+		Notification: invoicePaid{},
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if len(recorder.statements) != 1 {
+		t.Fatalf("statements = %d, want 1", len(recorder.statements))
+	}
+
+	insert := recorder.statements[0]
+
+	want := "INSERT INTO notifications (id, type, notifiable, data, created_at, read_at) VALUES ($1, $2, $3, $4, $5, $6)"
+	if insert.query != want {
+		t.Errorf("query = %q, want %q", insert.query, want)
+	}
+
+	if got := insert.args[1:]; !reflect.DeepEqual(got, []driver.Value{"database_test.invoicePaid", "42", `{"invoice":"2026-001"}`, now, nil}) {
+		t.Errorf("args = %v", got)
+	}
+}
+
+func TestUnread(t *testing.T) {
+	created := time.Date(2026, time.March, 5, 12, 0, 0, 0, time.UTC)
+	recorder := &recorder{rows: [][]driver.Value{
+		{"a1", "billing.InvoicePaid", "42", `{"invoice":"2026-001"}`, created, nil},
+	}}
+
+	adapter := database.NewAdapter(open(t, recorder))
+
+	unread, err := adapter.Unread(context.Background(), "42")
+	if err != nil {
+		t.Fatalf("Unread() error = %v", err)
+	}
+
+	want := []entities.DatabaseNotification{{
+		ID: "a1", Type: "billing.InvoicePaid", Notifiable: "42", Data: map[string]any{"invoice": "2026-001"}, CreatedAt: created,
+	}}
+	if !reflect.DeepEqual(unread, want) {
+		t.Errorf("Unread() = %+v, want %+v", unread, want)
+	}
+
+	if err := adapter.MarkAsRead(context.Background(), "a1"); err != nil {
+		t.Fatalf("MarkAsRead() error = %v", err)
+	}
+
+	if query := recorder.statements[1].query; query != "UPDATE notifications SET read_at = ? WHERE id = ? AND read_at IS NULL" {
+		t.Errorf("query = %q", query)
+	}
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gonstruct/providers/entities"
+)
+
+// Unread returns the unread notifications of the notifiable, newest first.
+func (adapter *Adapter) Unread(ctx context.Context, notifiable string) ([]entities.DatabaseNotification, error) {
+	query := fmt.Sprintf("SELECT id, type, notifiable, data, created_at, read_at FROM %s WHERE notifiable = %s AND read_at IS NULL ORDER BY created_at DESC",
+		append([]any{adapter.table()}, adapter.placeholders(0, 1)...)...)
+
+	rows, err := adapter.DB.QueryContext(ctx, query, notifiable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []entities.DatabaseNotification
+
+	for rows.Next() {
+		var (
+			notification entities.DatabaseNotification
+			data         string
+			readAt       sql.NullTime
+		)
+
+		if err := rows.Scan(&notification.ID, &notification.Type, &notification.Notifiable, &data, &notification.CreatedAt, &readAt); err != nil {
+			return nil, fmt.Errorf("failed to read notification: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(data), &notification.Data); err != nil {
+			return nil, fmt.Errorf("failed to decode notification %q: %w", notification.ID, err)
+		}
+
+		if readAt.Valid {
+			notification.ReadAt = &readAt.Time
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// MarkAsRead marks the notifications with the given IDs as read.
+func (adapter *Adapter) MarkAsRead(ctx context.Context, ids ...string) error {
+	query := fmt.Sprintf("UPDATE %s SET read_at = %s WHERE id = %s AND read_at IS NULL",
+		append([]any{adapter.table()}, adapter.placeholders(0, 2)...)...)
+
+	now := adapter.now()
+
+	for _, id := range ids {
+		if _, err := adapter.DB.ExecContext(ctx, query, now, id); err != nil {
+			return fmt.Errorf("failed to mark notification %q as read: %w", id, err)
+		}
+	}
+
+	return nil
+}
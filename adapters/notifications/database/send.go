@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/notifications"
+	"github.com/google/uuid"
+)
+
+func (adapter *Adapter) Send(ctx context.Context, input entities.NotificationInput) error {
+	notification, ok := input.Notification.(contracts.DatabaseNotification)
+	if !ok {
+		return notifications.ErrUnsupportedChannel
+	}
+
+	return adapter.Insert(ctx, entities.DatabaseNotification{
+		ID:         uuid.NewString(),
+		Type:       Type(notification),
+		Notifiable: input.Route,
+		Data:       notification.ToDatabase(),
+		CreatedAt:  adapter.now(),
+	})
+}
+
+// Insert stores the notification as a row.
+func (adapter *Adapter) Insert(ctx context.Context, notification entities.DatabaseNotification) error {
+	data, err := json.Marshal(notification.Data)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification data: %w", err)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (id, type, notifiable, data, created_at, read_at) VALUES (%s, %s, %s, %s, %s, %s)",
+		append([]any{adapter.table()}, adapter.placeholders(0, 6)...)...)
+
+	_, err = adapter.DB.ExecContext(ctx, query,
+		notification.ID, notification.Type, notification.Notifiable, string(data), notification.CreatedAt, notification.ReadAt)
+	if err != nil {
+		return fmt.Errorf("failed to store notification: %w", err)
+	}
+
+	return nil
+}
+
+// Type returns the type stored for the notification, its NotificationType when it
+// implements contracts.NamedNotification and its Go type name otherwise.
+func Type(notification contracts.Notification) string {
+	if named, ok := notification.(contracts.NamedNotification); ok {
+		return named.NotificationType()
+	}
+
+	return strings.TrimPrefix(fmt.Sprintf("%T", notification), "*")
+}
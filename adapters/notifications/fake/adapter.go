@@ -0,0 +1,69 @@
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gonstruct/providers/entities"
+)
+
+// Adapter is a fake notification channel for testing, it records the notifications of every channel.
+type Adapter struct {
+	mu sync.RWMutex
+
+	// Call tracking
+	Calls []SendCall
+
+	// Error injection
+	SendError error
+
+	// Custom send function
+	SendFunc func(ctx context.Context, input entities.NotificationInput) error
+}
+
+type SendCall struct {
+	Channel      string
+	Route        string
+	Notifiable   any
+	Notification any
+	// Representation is the result of ToMail, ToWebhook or ToDatabase for the built-in channels
+	Representation any
+	Input          entities.NotificationInput
+}
+
+// New creates a new fake notification channel.
+func New() *Adapter {
+	return &Adapter{}
+}
+
+// Reset clears all recorded calls.
+func (a *Adapter) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.Calls = nil
+	a.SendError = nil
+	a.SendFunc = nil
+}
+
+// --- Helper Methods ---
+
+// SentCount returns the number of notifications sent, counting every channel.
+func (a *Adapter) SentCount() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return len(a.Calls)
+}
+
+// LastCall returns the last send call, or nil if none.
+func (a *Adapter) LastCall() *SendCall {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if len(a.Calls) == 0 {
+		return nil
+	}
+
+	return &a.Calls[len(a.Calls)-1]
+}
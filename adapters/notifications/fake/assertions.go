@@ -0,0 +1,151 @@
+package fake
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// AssertSent asserts that at least one notification was sent.
+func (a *Adapter) AssertSent(t testing.TB) {
+	t.Helper()
+
+	if a.SentCount() == 0 {
+		t.Error("Expected at least one notification to be sent, but none were sent")
+	}
+}
+
+// AssertSentCount asserts the exact number of notifications sent, counting every channel.
+func (a *Adapter) AssertSentCount(t testing.TB, count int) {
+	t.Helper()
+
+	if sent := a.SentCount(); sent != count {
+		t.Errorf("Expected %d notifications to be sent, got %d", count, sent)
+	}
+}
+
+// AssertNothingSent asserts that no notifications were sent.
+func (a *Adapter) AssertNothingSent(t testing.TB) {
+	t.Helper()
+
+	if sent := a.SentCount(); sent > 0 {
+		t.Errorf("Expected no notifications to be sent, but %d were sent", sent)
+	}
+}
+
+// AssertSentTo asserts that a notification was sent to the notifiable.
+func (a *Adapter) AssertSentTo(t testing.TB, notifiable any) {
+	t.Helper()
+
+	if !a.sent(func(call SendCall) bool { return reflect.DeepEqual(call.Notifiable, notifiable) }) {
+		t.Errorf("Expected a notification to be sent to %v, but none was", notifiable)
+	}
+}
+
+// AssertNotSentTo asserts that no notification was sent to the notifiable.
+func (a *Adapter) AssertNotSentTo(t testing.TB, notifiable any) {
+	t.Helper()
+
+	if a.sent(func(call SendCall) bool { return reflect.DeepEqual(call.Notifiable, notifiable) }) {
+		t.Errorf("Expected no notification to be sent to %v, but one was", notifiable)
+	}
+}
+
+// AssertSentOn asserts that a notification was sent on the channel to the route,
+// like AssertSentOn(t, "mail", "user@example.com").
+func (a *Adapter) AssertSentOn(t testing.TB, channel, route string) {
+	t.Helper()
+
+	if !a.sent(func(call SendCall) bool { return call.Channel == channel && call.Route == route }) {
+		t.Errorf("Expected a notification to be sent on %q to %q, but none was", channel, route)
+	}
+}
+
+// AssertNotSentOn asserts that no notification was sent on the channel.
+func (a *Adapter) AssertNotSentOn(t testing.TB, channel string) {
+	t.Helper()
+
+	if a.sent(func(call SendCall) bool { return call.Channel == channel }) {
+		t.Errorf("Expected no notification to be sent on %q, but one was", channel)
+	}
+}
+
+// AssertSentMatching asserts that at least one notification matching the predicate was sent.
+func (a *Adapter) AssertSentMatching(t testing.TB, predicate func(call SendCall) bool) {
+	t.Helper()
+
+	if !a.sent(predicate) {
+		t.Error("Expected a notification matching the predicate to be sent, but none was")
+	}
+}
+
+// AssertNotSentMatching asserts that no notification matching the predicate was sent.
+func (a *Adapter) AssertNotSentMatching(t testing.TB, predicate func(call SendCall) bool) {
+	t.Helper()
+
+	if a.sent(predicate) {
+		t.Error("Expected no notification matching the predicate to be sent, but one was")
+	}
+}
+
+// AssertSentNotification asserts that a notification of type T was sent and,
+// when predicates are given, that one of them matches all predicates.
+//
+//	fake.AssertSentNotification(t, adapter, func(n InvoicePaid) bool { return n.Invoice.ID == 42 })
+func AssertSentNotification[T any](t testing.TB, a *Adapter, predicates ...func(notification T) bool) {
+	t.Helper()
+
+	for _, notification := range SentNotifications[T](a) {
+		if matchesAll(notification, predicates) {
+			return
+		}
+	}
+
+	var zero T
+
+	t.Errorf("Expected a %T notification to be sent, but none matched", zero)
+}
+
+// AssertNotSentNotification asserts that no notification of type T was sent.
+func AssertNotSentNotification[T any](t testing.TB, a *Adapter) {
+	t.Helper()
+
+	if sent := SentNotifications[T](a); len(sent) > 0 {
+		var zero T
+
+		t.Errorf("Expected no %T notification to be sent, but %d were sent", zero, len(sent))
+	}
+}
+
+// SentNotifications returns the sent notifications of type T, once per channel they were sent on.
+func SentNotifications[T any](a *Adapter) []T {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var notifications []T
+
+	for _, call := range a.Calls {
+		if notification, ok := call.Notification.(T); ok {
+			notifications = append(notifications, notification)
+		}
+	}
+
+	return notifications
+}
+
+func matchesAll[T any](value T, predicates []func(T) bool) bool {
+	for _, predicate := range predicates {
+		if !predicate(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a *Adapter) sent(predicate func(call SendCall) bool) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return slices.ContainsFunc(a.Calls, predicate)
+}
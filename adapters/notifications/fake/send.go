@@ -0,0 +1,67 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+)
+
+func (a *Adapter) Send(ctx context.Context, input entities.NotificationInput) error {
+	if a.SendFunc != nil {
+		return a.SendFunc(ctx, input)
+	}
+
+	if a.SendError != nil {
+		return a.SendError
+	}
+
+	// The representation is built like the real channel would, so a missing one fails the send
+	representation, ok := represent(input)
+	if !ok {
+		return fmt.Errorf("notification %T has no representation for the %s channel", input.Notification, input.Channel)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.Calls = append(a.Calls, SendCall{
+		Channel:        input.Channel,
+		Route:          input.Route,
+		Notifiable:     input.Notifiable,
+		Notification:   input.Notification,
+		Representation: representation,
+		Input:          input,
+	})
+
+	return nil
+}
+
+// represent returns the representation of the notification for the built-in channels.
+// Other channels are accepted without one.
+func represent(input entities.NotificationInput) (any, bool) {
+	switch input.Channel {
+	case "mail":
+		if notification, ok := input.Notification.(contracts.MailNotification); ok {
+			return notification.ToMail(), true
+		}
+	case "webhook":
+		if notification, ok := input.Notification.(contracts.WebhookNotification); ok {
+			return notification.ToWebhook(), true
+		}
+	case "database":
+		if notification, ok := input.Notification.(contracts.DatabaseNotification); ok {
+			return notification.ToDatabase(), true
+		}
+	default:
+		return nil, true
+	}
+
+	return nil, false
+}
+
+// Ensure Adapter implements the interface.
+var _ interface {
+	Send(ctx context.Context, input entities.NotificationInput) error
+} = (*Adapter)(nil)
@@ -0,0 +1,18 @@
+package webhook
+
+import (
+	"net/http"
+)
+
+// SignatureHeader carries the HMAC-SHA256 signature of the body as "sha256=<hex>" when a secret is set.
+const SignatureHeader = "X-Signature-256"
+
+// Adapter posts the webhook representation of notifications as JSON to the route of the notifiable,
+// like a Slack incoming webhook URL.
+type Adapter struct {
+	HTTPClient *http.Client
+	// Secret signs every request body in the SignatureHeader (optional)
+	Secret []byte
+	// Headers are added to every request, before the headers of the message
+	Headers map[string]string
+}
@@ -0,0 +1,83 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gonstruct/providers/adapters/notifications/webhook"
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/notifications"
+)
+
+type deployed struct{}
+
+func (deployed) Via(contracts.Notifiable) []string {
+	return []string{notifications.ChannelWebhook}
+}
+
+func (deployed) ToWebhook() entities.WebhookMessage {
+	return entities.WebhookMessage{
+		Payload: map[string]string{"text": "Deployed"},
+		Headers: map[string]string{"X-Event": "deployed"},
+	}
+}
+
+func TestSend(t *testing.T) {
+	var body []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+
+		if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+			t.Errorf("Content-Type = %q, want %q", contentType, "application/json")
+		}
+
+		if event := r.Header.Get("X-Event"); event != "deployed" {
+			t.Errorf("X-Event = %q, want %q", event, "deployed")
+		}
+
+		if signature := r.Header.Get(webhook.SignatureHeader); signature != "sha256="+webhook.Sign([]byte("secret"), body) {
+			t.Errorf("%s = %q, does not match the body", webhook.SignatureHeader, signature)
+		}
+	}))
+	defer server.Close()
+
+	adapter := &webhook.Adapter{Secret: []byte("secret")}
+
+	err := adapter.Send(context.Background(), entities.NotificationInput{
+		Channel:      notifications.ChannelWebhook,
+		Route:        server.URL,
+		Notification: deployed{},
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if string(body) != `{"text":"Deployed"}` {
+		t.Errorf("body = %s, want %s", body, `{"text":"Deployed"}`)
+	}
+}
+
+func TestSend_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	adapter := &webhook.Adapter{}
+
+	err := adapter.Send(context.Background(), entities.NotificationInput{Route: server.URL, Notification: deployed{}})
+	if !errors.Is(err, notifications.ErrDeliveryFailed) {
+		t.Errorf("Send() error = %v, want ErrDeliveryFailed", err)
+	}
+
+	err = adapter.Send(context.Background(), entities.NotificationInput{Route: server.URL, Notification: struct{ contracts.Notification }{}})
+	if !errors.Is(err, notifications.ErrUnsupportedChannel) {
+		t.Errorf("Send() error = %v, want ErrUnsupportedChannel", err)
+	}
+}
@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/notifications"
+)
+
+// maxErrorBody limits how much of an error response is read.
+const maxErrorBody = 4 << 10
+
+func (adapter *Adapter) Send(ctx context.Context, input entities.NotificationInput) error {
+	notification, ok := input.Notification.(contracts.WebhookNotification)
+	if !ok {
+		return notifications.ErrUnsupportedChannel
+	}
+
+	message := notification.ToWebhook()
+
+	url := message.URL
+	if url == "" {
+		url = input.Route
+	}
+
+	body, err := json.Marshal(message.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	for name, value := range adapter.Headers {
+		request.Header.Set(name, value)
+	}
+
+	for name, value := range message.Headers {
+		request.Header.Set(name, value)
+	}
+
+	if len(adapter.Secret) > 0 {
+		request.Header.Set(SignatureHeader, "sha256="+Sign(adapter.Secret, body))
+	}
+
+	client := adapter.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusMultipleChoices {
+		detail, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBody))
+
+		return fmt.Errorf("%w: webhook returned status %d: %s", notifications.ErrDeliveryFailed, response.StatusCode, bytes.TrimSpace(detail))
+	}
+
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of body, for receivers verifying the SignatureHeader.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -9,6 +9,17 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
+const (
+	// DefaultPartSize is the size of the parts of multipart uploads, bodies up to a part are sent with a single request.
+	DefaultPartSize = 16 << 20
+	// MinPartSize is the smallest part S3 accepts, except for the last part of an upload.
+	MinPartSize = 5 << 20
+	// MaxParts is the maximum number of parts of a multipart upload.
+	MaxParts = 10000
+	// DefaultUploadConcurrency is the number of parts uploaded at the same time.
+	DefaultUploadConcurrency = 4
+)
+
 type Adapter struct {
 	AccessKeyID     string
 	SecretAccessKey string
@@ -16,6 +27,12 @@ type Adapter struct {
 	Bucket          string
 	Endpoint        string
 	UsePathStyle    bool
+
+	// PartSize is the part size of multipart uploads, DefaultPartSize when 0 and at least MinPartSize.
+	// Every upload buffers at most UploadConcurrency+1 parts in memory.
+	PartSize int64
+	// UploadConcurrency is the number of parts uploaded at the same time, DefaultUploadConcurrency when 0
+	UploadConcurrency int
 }
 
 func (adapter Adapter) NewClient(ctx context.Context) (*s3.Client, error) {
@@ -0,0 +1,527 @@
+package amazon_s3_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gonstruct/providers/adapters/storage/amazon_s3"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/storage"
+)
+
+// stream hides the io.Seeker and io.ReaderAt of a reader, like a network body.
+type stream struct {
+	reader io.Reader
+	// afterRead is called after every read
+	afterRead func(read int)
+	read      int
+}
+
+func (s *stream) Read(p []byte) (int, error) {
+	n, err := s.reader.Read(p)
+
+	s.read += n
+	if s.afterRead != nil {
+		s.afterRead(s.read)
+	}
+
+	return n, err
+}
+
+func content(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	return data
+}
+
+func TestPutStream_Small(t *testing.T) {
+	server, adapter := newS3Server(t)
+
+	if err := adapter.PutStream(context.Background(), "reports/small.txt", &stream{reader: strings.NewReader("hello")}); err != nil {
+		t.Fatalf("PutStream() error = %v", err)
+	}
+
+	object := server.Object("reports/small.txt")
+	if object == nil || string(object.Content) != "hello" || object.ContentType != "text/plain" {
+		t.Fatalf("object = %+v, want hello as text/plain", object)
+	}
+
+	if len(server.Requests) != 1 || !strings.HasPrefix(server.Requests[0], "PUT reports/small.txt?") {
+		t.Errorf("requests = %v, want a single PutObject", server.Requests)
+	}
+}
+
+func TestPutStream_Multipart(t *testing.T) {
+	server, adapter := newS3Server(t)
+	adapter.PartSize = amazon_s3.MinPartSize
+	adapter.UploadConcurrency = 2
+
+	data := content(2*amazon_s3.MinPartSize + 1024)
+
+	if err := adapter.PutStream(context.Background(), "exports/video.mp4", &stream{reader: bytes.NewReader(data)}); err != nil {
+		t.Fatalf("PutStream() error = %v", err)
+	}
+
+	object := server.Object("exports/video.mp4")
+	if object == nil || !bytes.Equal(object.Content, data) || object.ContentType != "video/mp4" {
+		t.Fatalf("object = %d bytes, want the stream assembled from its parts as video/mp4", len(object.Content))
+	}
+
+	parts := 0
+
+	for _, request := range server.Requests {
+		if strings.HasPrefix(request, "PUT exports/video.mp4?") && strings.Contains(request, "partNumber=") {
+			parts++
+		}
+	}
+
+	if parts != 3 || server.PendingUploads() != 0 {
+		t.Errorf("uploaded %d parts with %d pending uploads, want 3 parts and a completed upload", parts, server.PendingUploads())
+	}
+}
+
+func TestPutStream_AbortsOnError(t *testing.T) {
+	server, adapter := newS3Server(t)
+	adapter.PartSize = amazon_s3.MinPartSize
+	server.FailPart = 2
+
+	err := adapter.PutStream(context.Background(), "exports/video.mp4", &stream{reader: bytes.NewReader(content(3 * amazon_s3.MinPartSize))})
+	if err == nil || !strings.Contains(err.Error(), "upload part 2") {
+		t.Fatalf("PutStream() error = %v, want the failed part", err)
+	}
+
+	if len(server.Aborted) != 1 || server.PendingUploads() != 0 || server.Object("exports/video.mp4") != nil {
+		t.Errorf("aborted = %v, pending = %d, want the upload aborted without an object", server.Aborted, server.PendingUploads())
+	}
+}
+
+func TestPutStream_AbortsOnCancel(t *testing.T) {
+	server, adapter := newS3Server(t)
+	adapter.PartSize = amazon_s3.MinPartSize
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	body := &stream{
+		reader: bytes.NewReader(content(3 * amazon_s3.MinPartSize)),
+		afterRead: func(read int) {
+			if read > amazon_s3.MinPartSize {
+				cancel()
+			}
+		},
+	}
+
+	err := adapter.PutStream(ctx, "exports/video.mp4", body)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("PutStream() error = %v, want context.Canceled", err)
+	}
+
+	if len(server.Aborted) != 1 || server.PendingUploads() != 0 {
+		t.Errorf("aborted = %v, pending = %d, want the upload aborted", server.Aborted, server.PendingUploads())
+	}
+}
+
+func TestFiles_Paginates(t *testing.T) {
+	server, adapter := newS3Server(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"reports/a.csv", "reports/b.csv", "reports/c.pdf", "reports/2026/d.csv", "reports/2027/e.csv", "other/f.csv"} {
+		if err := adapter.Put(ctx, key, []byte(key)); err != nil {
+			t.Fatalf("Put(%q) error = %v", key, err)
+		}
+	}
+
+	page, err := adapter.List(ctx, entities.StorageListInput{Directory: "reports", PageSize: 2})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if len(page.Entries) != 2 || page.Entries[0].Path != "reports/2026" || !page.Entries[0].IsDir || page.NextPageToken == "" {
+		t.Fatalf("List() = %+v, want the first two entries and a page token", page)
+	}
+
+	server.MaxKeys = 2
+
+	files, err := adapter.Files(ctx, "reports")
+	if err != nil {
+		t.Fatalf("Files() error = %v", err)
+	}
+
+	if want := []string{"reports/a.csv", "reports/b.csv", "reports/c.pdf"}; !slices.Equal(files, want) {
+		t.Errorf("Files() = %v, want %v", files, want)
+	}
+
+	page, err = adapter.List(ctx, entities.StorageListInput{Directory: "reports", Recursive: true, Pattern: "*.csv", PageSize: 1})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if len(page.Entries) != 1 || page.Entries[0].Path != "reports/2026/d.csv" || page.Entries[0].Size != 18 || page.Entries[0].LastModified.IsZero() {
+		t.Errorf("List() = %+v, want reports/2026/d.csv with its size and modification time", page)
+	}
+
+	listed := 0
+
+	for _, request := range server.Requests {
+		if strings.HasPrefix(request, "LIST ") {
+			listed++
+		}
+	}
+
+	if listed < 4 {
+		t.Errorf("listed %d pages, want Files to follow the continuation token", listed)
+	}
+}
+
+func TestStat(t *testing.T) {
+	_, adapter := newS3Server(t)
+	adapter.PartSize = amazon_s3.MinPartSize
+	ctx := context.Background()
+
+	options := entities.StorageWriteOptions{
+		CacheControl:       "max-age=3600",
+		ContentDisposition: `attachment; filename="report.pdf"`,
+		Metadata:           map[string]string{"owner": "finance"},
+	}
+
+	if err := adapter.Write(ctx, "reports/report.pdf", strings.NewReader("report"), options); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	metadata, err := adapter.Stat(ctx, "reports/report.pdf")
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+
+	if metadata.Size != 6 || metadata.ContentType != "application/pdf" || metadata.ETag != etag([]byte("report")) ||
+		metadata.Checksum != sha256Base64([]byte("report")) || metadata.LastModified.IsZero() {
+		t.Errorf("Stat() = %+v, want the size, content type, ETag, checksum and modification time", metadata)
+	}
+
+	if metadata.CacheControl != options.CacheControl || metadata.ContentDisposition != options.ContentDisposition ||
+		metadata.Metadata["owner"] != "finance" {
+		t.Errorf("Stat() = %+v, want the write options", metadata)
+	}
+
+	if metadata.Visibility != entities.VisibilityPrivate || metadata.StorageClass != "STANDARD" {
+		t.Errorf("Stat() visibility = %q, storage class = %q, want private STANDARD", metadata.Visibility, metadata.StorageClass)
+	}
+
+	// Multipart uploads are stored with the write options too
+	if err := adapter.Write(ctx, "exports/video.mp4", bytes.NewReader(content(amazon_s3.MinPartSize+1)), options); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	metadata, err = adapter.Stat(ctx, "exports/video.mp4")
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+
+	if metadata.CacheControl != options.CacheControl || metadata.Metadata["owner"] != "finance" || !strings.HasSuffix(metadata.Checksum, "-2") {
+		t.Errorf("Stat() = %+v, want the write options and the checksum of 2 parts", metadata)
+	}
+
+	if _, err := adapter.Stat(ctx, "reports/missing.pdf"); !errors.Is(err, storage.ErrFileNotFound) {
+		t.Errorf("Stat() error = %v, want storage.ErrFileNotFound", err)
+	}
+}
+
+func TestGetRange(t *testing.T) {
+	server, adapter := newS3Server(t)
+	ctx := context.Background()
+
+	if err := adapter.Put(ctx, "docs/alphabet.txt", []byte("abcdefghij")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	tests := []struct {
+		offset, length int64
+		want           string
+	}{
+		{offset: 2, length: 3, want: "cde"},
+		{offset: 7, length: -1, want: "hij"},
+		{offset: 8, length: 100, want: "ij"},
+		{offset: 0, length: -1, want: "abcdefghij"},
+		{offset: 10, length: -1, want: ""},
+		{offset: 4, length: 0, want: ""},
+	}
+
+	for _, test := range tests {
+		reader, err := adapter.GetRange(ctx, "docs/alphabet.txt", test.offset, test.length)
+		if err != nil {
+			t.Fatalf("GetRange(%d, %d) error = %v", test.offset, test.length, err)
+		}
+
+		got, _ := io.ReadAll(reader)
+		reader.Close()
+
+		if string(got) != test.want {
+			t.Errorf("GetRange(%d, %d) = %q, want %q", test.offset, test.length, got, test.want)
+		}
+	}
+
+	if want := []string{"bytes=2-4", "bytes=7-", "bytes=8-107", "", "bytes=10-"}; !slices.Equal(server.Ranges, want) {
+		t.Errorf("Range headers = %q, want %q", server.Ranges, want)
+	}
+
+	if _, err := adapter.GetRange(ctx, "docs/alphabet.txt", 11, -1); !errors.Is(err, storage.ErrInvalidRange) {
+		t.Errorf("GetRange() error = %v, want storage.ErrInvalidRange", err)
+	}
+
+	if _, err := adapter.GetRange(ctx, "docs/missing.txt", 0, 1); !errors.Is(err, storage.ErrFileNotFound) {
+		t.Errorf("GetRange() error = %v, want storage.ErrFileNotFound", err)
+	}
+}
+
+func TestOpen_ServeContent(t *testing.T) {
+	server, adapter := newS3Server(t)
+	ctx := context.Background()
+
+	if err := adapter.Put(ctx, "videos/intro.mp4", []byte("0123456789")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	file, err := adapter.Open(ctx, "videos/intro.mp4")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer file.Close()
+
+	request := httptest.NewRequest(http.MethodGet, "/intro.mp4", nil)
+	request.Header.Set("Range", "bytes=6-8")
+
+	recorder := httptest.NewRecorder()
+	http.ServeContent(recorder, request, "intro.mp4", time.Time{}, file)
+
+	if recorder.Code != http.StatusPartialContent || recorder.Body.String() != "678" {
+		t.Errorf("ServeContent() = %d %q, want 206 \"678\"", recorder.Code, recorder.Body.String())
+	}
+
+	// The object is only requested from the offset of the range
+	if want := []string{"bytes=6-"}; !slices.Equal(server.Ranges, want) {
+		t.Errorf("Range headers = %q, want %q", server.Ranges, want)
+	}
+
+	// Reads after the object was overwritten fail instead of mixing two versions
+	if err := adapter.Put(ctx, "videos/intro.mp4", []byte("abcdefghij")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("Seek() error = %v", err)
+	}
+
+	if _, err := io.ReadAll(file); !errors.Is(err, storage.ErrPreconditionFailed) {
+		t.Errorf("Read() error = %v, want storage.ErrPreconditionFailed", err)
+	}
+
+	if _, err := adapter.Open(ctx, "videos/missing.mp4"); !errors.Is(err, storage.ErrFileNotFound) {
+		t.Errorf("Open() error = %v, want storage.ErrFileNotFound", err)
+	}
+}
+
+func TestTemporaryUploadURL(t *testing.T) {
+	server, adapter := newS3Server(t)
+	ctx := context.Background()
+
+	upload, err := adapter.TemporaryUploadURL(ctx, entities.StorageUploadInput{
+		Path: "avatars/42.png", Expiration: time.Minute, ContentType: "image/png", ContentLength: 4, Visibility: entities.VisibilityPublic,
+	})
+	if err != nil {
+		t.Fatalf("TemporaryUploadURL() error = %v", err)
+	}
+
+	if !strings.Contains(upload.URL, "X-Amz-Signature=") || upload.Header["Content-Type"] != "image/png" || upload.Header["Content-Length"] != "4" {
+		t.Errorf("TemporaryUploadURL() = %+v, want a presigned URL with the signed headers", upload)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, upload.Method, upload.URL, strings.NewReader("icon"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, value := range upload.Header {
+		request.Header.Set(name, value)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("PUT error = %v", err)
+	}
+	response.Body.Close()
+
+	if object := server.Object("avatars/42.png"); object == nil || object.ContentType != "image/png" || object.ACL != "public-read" {
+		t.Errorf("object = %+v, want the upload with the signed headers", object)
+	}
+
+	_, err = adapter.TemporaryUploadURL(ctx, entities.StorageUploadInput{Path: "avatars/42.png", Expiration: time.Minute, MaxSize: 1024})
+	if !errors.Is(err, storage.ErrNotSupported) {
+		t.Errorf("TemporaryUploadURL() error = %v, want storage.ErrNotSupported for MaxSize", err)
+	}
+}
+
+func TestTemporaryUploadForm(t *testing.T) {
+	_, adapter := newS3Server(t)
+
+	upload, err := adapter.TemporaryUploadForm(context.Background(), entities.StorageUploadInput{
+		Path: "avatars/42.png", Expiration: time.Minute, ContentType: "image/png", MaxSize: 1024,
+	})
+	if err != nil {
+		t.Fatalf("TemporaryUploadForm() error = %v", err)
+	}
+
+	if upload.Method != http.MethodPost || !strings.HasSuffix(upload.URL, "/"+testBucket) ||
+		upload.Fields["key"] != "avatars/42.png" || upload.Fields["Content-Type"] != "image/png" {
+		t.Errorf("TemporaryUploadForm() = %+v, want a POST request with the key and content type fields", upload)
+	}
+
+	policy, err := base64.StdEncoding.DecodeString(upload.Fields["policy"])
+	if err != nil {
+		t.Fatalf("policy error = %v", err)
+	}
+
+	for _, condition := range []string{`{"Content-Type":"image/png"}`, `["content-length-range",0,1024]`} {
+		if !strings.Contains(string(policy), condition) {
+			t.Errorf("policy = %s, want the condition %s", policy, condition)
+		}
+	}
+}
+
+func TestWrite_Options(t *testing.T) {
+	server, adapter := newS3Server(t)
+	adapter.PartSize = amazon_s3.MinPartSize
+	ctx := context.Background()
+
+	options := entities.StorageWriteOptions{ContentType: "text/csv", Visibility: entities.VisibilityPublic, IfNoneMatch: "*"}
+
+	if err := adapter.Write(ctx, "exports/data", strings.NewReader("a,b"), options); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if object := server.Object("exports/data"); object.ContentType != "text/csv" {
+		t.Errorf("content type = %q, want text/csv", object.ContentType)
+	}
+
+	if visibility, err := adapter.GetVisibility(ctx, "exports/data"); err != nil || visibility != entities.VisibilityPublic {
+		t.Errorf("GetVisibility() = %q, %v, want public", visibility, err)
+	}
+
+	// Conditional writes
+	err := adapter.Write(ctx, "exports/data", strings.NewReader("c,d"), options)
+	if !errors.Is(err, storage.ErrAlreadyExists) {
+		t.Errorf("Write() error = %v, want storage.ErrAlreadyExists", err)
+	}
+
+	err = adapter.Write(ctx, "exports/data", strings.NewReader("c,d"), entities.StorageWriteOptions{IfMatch: `"stale"`})
+	if !errors.Is(err, storage.ErrPreconditionFailed) {
+		t.Errorf("Write() error = %v, want storage.ErrPreconditionFailed", err)
+	}
+
+	err = adapter.Write(ctx, "exports/data", strings.NewReader("c,d"), entities.StorageWriteOptions{IfMatch: etag([]byte("a,b"))})
+	if err != nil || string(server.Object("exports/data").Content) != "c,d" {
+		t.Errorf("Write() error = %v, want the file overwritten when the ETag matches", err)
+	}
+
+	// Multipart uploads are checked when they are completed
+	data := bytes.NewReader(content(amazon_s3.MinPartSize + 1))
+
+	err = adapter.Write(ctx, "exports/data", data, entities.StorageWriteOptions{IfNoneMatch: "*"})
+	if !errors.Is(err, storage.ErrAlreadyExists) || len(server.Aborted) != 1 {
+		t.Errorf("Write() error = %v, aborted = %v, want storage.ErrAlreadyExists and the upload aborted", err, server.Aborted)
+	}
+}
+
+func TestNormalizesKeys(t *testing.T) {
+	server, adapter := newS3Server(t)
+	ctx := context.Background()
+
+	if err := adapter.Put(ctx, "./reports//q1.csv", []byte("q1")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	if server.Object("reports/q1.csv") == nil {
+		t.Errorf("objects = %v, want the normalized key reports/q1.csv", server.Requests)
+	}
+
+	requests := len(server.Requests)
+
+	if err := adapter.Put(ctx, "../q1.csv", []byte("q1")); !errors.Is(err, storage.ErrInvalidPath) {
+		t.Errorf("Put() error = %v, want storage.ErrInvalidPath", err)
+	}
+
+	if _, err := adapter.Get(ctx, "/reports/q1.csv"); !errors.Is(err, storage.ErrInvalidPath) {
+		t.Errorf("Get() error = %v, want storage.ErrInvalidPath", err)
+	}
+
+	if len(server.Requests) != requests {
+		t.Errorf("requests = %v, want invalid paths rejected before any request", server.Requests[requests:])
+	}
+}
+
+func TestTusServer_Multipart(t *testing.T) {
+	server, adapter := newS3Server(t)
+	data := content(2*amazon_s3.MinPartSize + 1024)
+
+	tus := storage.NewTusServer("videos", storage.WithAdapter(adapter), storage.WithUniqueIDGenerator(func() string { return "abc" }))
+	tus.PartSize = amazon_s3.MinPartSize
+
+	request := func(method, target string, body []byte, header map[string]string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, target, bytes.NewReader(body))
+		r.Header.Set("Tus-Resumable", storage.TusVersion)
+
+		for name, value := range header {
+			r.Header.Set(name, value)
+		}
+
+		recorder := httptest.NewRecorder()
+		tus.ServeHTTP(recorder, r)
+
+		return recorder
+	}
+
+	created := request(http.MethodPost, "/", nil, map[string]string{
+		"Upload-Length":   strconv.Itoa(len(data)),
+		"Upload-Metadata": "filename " + base64.StdEncoding.EncodeToString([]byte("clip.mp4")),
+	})
+	if created.Code != http.StatusCreated {
+		t.Fatalf("POST = %d, want 201", created.Code)
+	}
+
+	// Chunks smaller than the minimum part size are buffered until they fill a part
+	offset := 0
+	for _, size := range []int{1 << 20, amazon_s3.MinPartSize, amazon_s3.MinPartSize} {
+		end := min(offset+size, len(data))
+
+		response := request(http.MethodPatch, "/abc", data[offset:end], map[string]string{
+			"Content-Type":  "application/offset+octet-stream",
+			"Upload-Offset": strconv.Itoa(offset),
+		})
+		if response.Code != http.StatusNoContent || response.Header().Get("Upload-Offset") != strconv.Itoa(end) {
+			t.Fatalf("PATCH at %d = %d %v, want 204 at %d", offset, response.Code, response.Header(), end)
+		}
+
+		offset = end
+	}
+
+	object := server.Object("videos/abc.mp4")
+	if object == nil || !bytes.Equal(object.Content, data) || object.ContentType != "video/mp4" {
+		t.Fatalf("object = %+v, want the upload assembled from its parts", object)
+	}
+
+	if server.PendingUploads() != 0 || server.Object(".tus/abc/pending") != nil {
+		t.Errorf("%d pending uploads, want a completed upload without the pending part", server.PendingUploads())
+	}
+}
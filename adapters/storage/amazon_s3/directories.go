@@ -6,35 +6,22 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/gonstruct/providers/entities"
 	"github.com/gonstruct/providers/storage"
 )
 
 // Files returns a list of files in the given directory (non-recursive).
 func (adapter Adapter) Files(ctx context.Context, directory string) ([]string, error) {
-	client, err := adapter.NewClient(ctx)
-	if err != nil {
-		return nil, storage.Err("create S3 client", err)
-	}
-
-	prefix := directory
-	if prefix != "" && !strings.HasSuffix(prefix, "/") {
-		prefix += "/"
-	}
-
-	result, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
-		Bucket:    aws.String(adapter.Bucket),
-		Prefix:    aws.String(prefix),
-		Delimiter: aws.String("/"),
-	})
+	entries, err := adapter.listAll(ctx, entities.StorageListInput{Directory: directory})
 	if err != nil {
-		return nil, storage.PathErr("list files", directory, err)
+		return nil, err
 	}
 
 	var files []string
 
-	for _, obj := range result.Contents {
-		if obj.Key != nil && *obj.Key != prefix {
-			files = append(files, *obj.Key)
+	for _, entry := range entries {
+		if !entry.IsDir {
+			files = append(files, entry.Path)
 		}
 	}
 
@@ -43,6 +30,11 @@ func (adapter Adapter) Files(ctx context.Context, directory string) ([]string, e
 
 // AllFiles returns a list of all files in the directory and subdirectories.
 func (adapter Adapter) AllFiles(ctx context.Context, directory string) ([]string, error) {
+	directory, err := storage.NormalizePath(directory)
+	if err != nil {
+		return nil, err
+	}
+
 	client, err := adapter.NewClient(ctx)
 	if err != nil {
 		return nil, storage.Err("create S3 client", err)
@@ -78,32 +70,16 @@ func (adapter Adapter) AllFiles(ctx context.Context, directory string) ([]string
 
 // Directories returns a list of directories in the given directory (non-recursive).
 func (adapter Adapter) Directories(ctx context.Context, directory string) ([]string, error) {
-	client, err := adapter.NewClient(ctx)
-	if err != nil {
-		return nil, storage.Err("create S3 client", err)
-	}
-
-	prefix := directory
-	if prefix != "" && !strings.HasSuffix(prefix, "/") {
-		prefix += "/"
-	}
-
-	result, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
-		Bucket:    aws.String(adapter.Bucket),
-		Prefix:    aws.String(prefix),
-		Delimiter: aws.String("/"),
-	})
+	entries, err := adapter.listAll(ctx, entities.StorageListInput{Directory: directory})
 	if err != nil {
-		return nil, storage.PathErr("list directories", directory, err)
+		return nil, err
 	}
 
 	var dirs []string
 
-	for _, prefix := range result.CommonPrefixes {
-		if prefix.Prefix != nil {
-			// Remove trailing slash for consistency
-			dir := strings.TrimSuffix(*prefix.Prefix, "/")
-			dirs = append(dirs, dir)
+	for _, entry := range entries {
+		if entry.IsDir {
+			dirs = append(dirs, entry.Path)
 		}
 	}
 
@@ -112,6 +88,11 @@ func (adapter Adapter) Directories(ctx context.Context, directory string) ([]str
 
 // AllDirectories returns a list of all directories in the directory and subdirectories.
 func (adapter Adapter) AllDirectories(ctx context.Context, directory string) ([]string, error) {
+	directory, err := storage.NormalizePath(directory)
+	if err != nil {
+		return nil, err
+	}
+
 	client, err := adapter.NewClient(ctx)
 	if err != nil {
 		return nil, storage.Err("create S3 client", err)
@@ -158,6 +139,11 @@ func (adapter Adapter) AllDirectories(ctx context.Context, directory string) ([]
 
 // MakeDirectory creates a directory (S3 doesn't have real directories, creates a placeholder).
 func (adapter Adapter) MakeDirectory(ctx context.Context, path string) error {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return err
+	}
+
 	client, err := adapter.NewClient(ctx)
 	if err != nil {
 		return storage.Err("create S3 client", err)
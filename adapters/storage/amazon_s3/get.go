@@ -8,12 +8,17 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
-	gomime "github.com/cubewise-code/go-mime"
+	"github.com/gonstruct/providers/entities"
 	"github.com/gonstruct/providers/storage"
 )
 
 // Get retrieves the contents of a file.
 func (adapter Adapter) Get(ctx context.Context, path string) ([]byte, error) {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+
 	client, err := adapter.NewClient(ctx)
 	if err != nil {
 		return nil, storage.Err("create S3 client", err)
@@ -33,6 +38,11 @@ func (adapter Adapter) Get(ctx context.Context, path string) ([]byte, error) {
 
 // GetStream returns a reader for the file contents.
 func (adapter Adapter) GetStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+
 	client, err := adapter.NewClient(ctx)
 	if err != nil {
 		return nil, storage.Err("create S3 client", err)
@@ -51,6 +61,11 @@ func (adapter Adapter) GetStream(ctx context.Context, path string) (io.ReadClose
 
 // Exists checks if a file exists.
 func (adapter Adapter) Exists(ctx context.Context, path string) (bool, error) {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return false, err
+	}
+
 	client, err := adapter.NewClient(ctx)
 	if err != nil {
 		return false, storage.Err("create S3 client", err)
@@ -77,6 +92,11 @@ func (adapter Adapter) Missing(ctx context.Context, path string) (bool, error) {
 
 // Size returns the size of a file in bytes.
 func (adapter Adapter) Size(ctx context.Context, path string) (int64, error) {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return 0, err
+	}
+
 	client, err := adapter.NewClient(ctx)
 	if err != nil {
 		return 0, storage.Err("create S3 client", err)
@@ -99,6 +119,11 @@ func (adapter Adapter) Size(ctx context.Context, path string) (int64, error) {
 
 // LastModified returns the last modification time of a file.
 func (adapter Adapter) LastModified(ctx context.Context, path string) (time.Time, error) {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
 	client, err := adapter.NewClient(ctx)
 	if err != nil {
 		return time.Time{}, storage.Err("create S3 client", err)
@@ -121,6 +146,11 @@ func (adapter Adapter) LastModified(ctx context.Context, path string) (time.Time
 
 // MimeType returns the MIME type of a file.
 func (adapter Adapter) MimeType(ctx context.Context, path string) (string, error) {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return "", err
+	}
+
 	client, err := adapter.NewClient(ctx)
 	if err != nil {
 		return "", storage.Err("create S3 client", err)
@@ -139,59 +169,46 @@ func (adapter Adapter) MimeType(ctx context.Context, path string) (string, error
 	}
 
 	// Fallback to extension-based detection
-	return gomime.TypeByExtension(path), nil
+	return mimeType(path), nil
 }
 
 // Put stores raw bytes at the given path.
 func (adapter Adapter) Put(ctx context.Context, path string, contents []byte) error {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return err
+	}
+
 	client, err := adapter.NewClient(ctx)
 	if err != nil {
 		return storage.Err("create S3 client", err)
 	}
 
-	mimetype := gomime.TypeByExtension(path)
-	if mimetype == "" {
-		mimetype = "application/octet-stream"
-	}
+	mimetype := mimeType(path)
 
-	_, err = client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(adapter.Bucket),
-		Key:         aws.String(path),
-		Body:        bytes.NewReader(contents),
-		ContentType: aws.String(mimetype),
-	})
-	if err != nil {
+	if err := adapter.upload(ctx, client, path, mimetype, bytes.NewReader(contents), entities.StorageWriteOptions{}); err != nil {
 		return storage.PathErr("put", path, err)
 	}
 
 	return nil
 }
 
-// PutStream stores content from a reader at the given path.
+// PutStream stores content from a reader at the given path. Streams larger than PartSize
+// are sent with a multipart upload, see PartSize and UploadConcurrency.
 func (adapter Adapter) PutStream(ctx context.Context, path string, stream io.Reader) error {
-	client, err := adapter.NewClient(ctx)
+	path, err := storage.NormalizePath(path)
 	if err != nil {
-		return storage.Err("create S3 client", err)
-	}
-
-	mimetype := gomime.TypeByExtension(path)
-	if mimetype == "" {
-		mimetype = "application/octet-stream"
+		return err
 	}
 
-	// Read stream into bytes (S3 SDK requires seekable body for retries)
-	content, err := io.ReadAll(stream)
+	client, err := adapter.NewClient(ctx)
 	if err != nil {
-		return storage.Err("read stream", err)
+		return storage.Err("create S3 client", err)
 	}
 
-	_, err = client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(adapter.Bucket),
-		Key:         aws.String(path),
-		Body:        bytes.NewReader(content),
-		ContentType: aws.String(mimetype),
-	})
-	if err != nil {
+	mimetype := mimeType(path)
+
+	if err := adapter.upload(ctx, client, path, mimetype, stream, entities.StorageWriteOptions{}); err != nil {
 		return storage.PathErr("put stream", path, err)
 	}
 
@@ -0,0 +1,103 @@
+package amazon_s3
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/storage"
+)
+
+// List returns a page of the directory with ListObjectsV2, the page token is the S3 continuation token.
+// The prefix is filtered by S3, the pattern after fetching the page, so pages may have fewer entries than PageSize.
+func (adapter Adapter) List(ctx context.Context, input entities.StorageListInput) (*entities.StoragePage, error) {
+	directory, err := storage.NormalizePath(input.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Directory = directory
+
+	client, err := adapter.NewClient(ctx)
+	if err != nil {
+		return nil, storage.Err("create S3 client", err)
+	}
+
+	request := &s3.ListObjectsV2Input{
+		Bucket: aws.String(adapter.Bucket),
+		Prefix: aws.String(entities.DirectoryPrefix(input.Directory) + input.Prefix),
+	}
+
+	if !input.Recursive {
+		request.Delimiter = aws.String("/")
+	}
+
+	if input.PageSize > 0 {
+		request.MaxKeys = aws.Int32(int32(min(input.PageSize, storage.DefaultPageSize)))
+	}
+
+	if input.PageToken != "" {
+		request.ContinuationToken = aws.String(input.PageToken)
+	}
+
+	result, err := client.ListObjectsV2(ctx, request)
+	if err != nil {
+		return nil, storage.PathErr("list", input.Directory, err)
+	}
+
+	page := &entities.StoragePage{}
+
+	if aws.ToBool(result.IsTruncated) {
+		page.NextPageToken = aws.ToString(result.NextContinuationToken)
+	}
+
+	for _, prefix := range result.CommonPrefixes {
+		if dir := strings.TrimSuffix(aws.ToString(prefix.Prefix), "/"); input.Matches(dir) {
+			page.Entries = append(page.Entries, entities.StorageEntry{Path: dir, IsDir: true})
+		}
+	}
+
+	for _, object := range result.Contents {
+		key := aws.ToString(object.Key)
+
+		// Keys ending in a slash are the directory placeholders of MakeDirectory
+		if strings.HasSuffix(key, "/") || !input.Matches(key) {
+			continue
+		}
+
+		page.Entries = append(page.Entries, entities.StorageEntry{
+			Path:         key,
+			Size:         aws.ToInt64(object.Size),
+			LastModified: aws.ToTime(object.LastModified),
+			ETag:         aws.ToString(object.ETag),
+		})
+	}
+
+	// S3 returns the directories and files of a page separately
+	slices.SortFunc(page.Entries, func(a, b entities.StorageEntry) int { return strings.Compare(a.Path, b.Path) })
+
+	return page, nil
+}
+
+// listAll returns the entries of every page of the listing.
+func (adapter Adapter) listAll(ctx context.Context, input entities.StorageListInput) ([]entities.StorageEntry, error) {
+	var entries []entities.StorageEntry
+
+	for {
+		page, err := adapter.List(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, page.Entries...)
+
+		if page.NextPageToken == "" {
+			return entries, nil
+		}
+
+		input.PageToken = page.NextPageToken
+	}
+}
@@ -0,0 +1,70 @@
+package amazon_s3
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/storage"
+)
+
+// Stat returns the metadata of an object with a HeadObject request, and its visibility with a GetObjectAcl request.
+func (adapter Adapter) Stat(ctx context.Context, path string) (*entities.StorageMetadata, error) {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := adapter.NewClient(ctx)
+	if err != nil {
+		return nil, storage.Err("create S3 client", err)
+	}
+
+	result, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(adapter.Bucket),
+		Key:          aws.String(path),
+		ChecksumMode: types.ChecksumModeEnabled,
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return nil, storage.PathErr("stat", path, storage.ErrFileNotFound)
+		}
+
+		return nil, storage.PathErr("stat", path, err)
+	}
+
+	visibility, err := adapter.GetVisibility(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &entities.StorageMetadata{
+		Path:               path,
+		Size:               aws.ToInt64(result.ContentLength),
+		LastModified:       aws.ToTime(result.LastModified),
+		ContentType:        aws.ToString(result.ContentType),
+		ETag:               aws.ToString(result.ETag),
+		Checksum:           aws.ToString(result.ChecksumSHA256),
+		Visibility:         visibility,
+		StorageClass:       string(result.StorageClass),
+		CacheControl:       aws.ToString(result.CacheControl),
+		ContentDisposition: aws.ToString(result.ContentDisposition),
+		ContentEncoding:    aws.ToString(result.ContentEncoding),
+		Metadata:           result.Metadata,
+	}
+
+	// S3 omits the storage class of STANDARD objects
+	if metadata.StorageClass == "" {
+		metadata.StorageClass = string(types.StorageClassStandard)
+	}
+
+	if metadata.ContentType == "" {
+		metadata.ContentType = mimeType(path)
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,134 @@
+package amazon_s3
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/storage"
+)
+
+// MinimumPartSize returns MinPartSize, the smallest part S3 accepts except for the last one.
+func (adapter Adapter) MinimumPartSize() int64 {
+	return MinPartSize
+}
+
+// CreateMultipartUpload starts a multipart upload of an object with the write options and returns its ID.
+// Uploads that are not completed are billed until they are aborted, see AbortMultipartUpload.
+func (adapter Adapter) CreateMultipartUpload(ctx context.Context, path string, options entities.StorageWriteOptions) (string, error) {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := adapter.NewClient(ctx)
+	if err != nil {
+		return "", storage.Err("create S3 client", err)
+	}
+
+	created, err := adapter.createMultipartUpload(ctx, client, path, cmp.Or(options.ContentType, mimeType(path)), options)
+	if err != nil {
+		return "", storage.PathErr("create multipart upload", path, err)
+	}
+
+	return aws.ToString(created.UploadId), nil
+}
+
+// UploadPart uploads a part of a multipart upload with its SHA-256 checksum.
+func (adapter Adapter) UploadPart(ctx context.Context, path, uploadID string, number int, part []byte) (*entities.StoragePart, error) {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if number < 1 || number > MaxParts {
+		return nil, storage.PathErr("upload part", path, fmt.Errorf("part number %d is not between 1 and %d", number, MaxParts))
+	}
+
+	client, err := adapter.NewClient(ctx)
+	if err != nil {
+		return nil, storage.Err("create S3 client", err)
+	}
+
+	sum := checksum(part)
+
+	result, err := client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:         aws.String(adapter.Bucket),
+		Key:            aws.String(path),
+		UploadId:       aws.String(uploadID),
+		PartNumber:     aws.Int32(int32(number)),
+		Body:           bytes.NewReader(part),
+		ChecksumSHA256: aws.String(sum),
+	})
+	if err != nil {
+		return nil, storage.PathErr(fmt.Sprintf("upload part %d", number), path, err)
+	}
+
+	return &entities.StoragePart{
+		Number:   number,
+		ETag:     aws.ToString(result.ETag),
+		Checksum: sum,
+		Size:     int64(len(part)),
+	}, nil
+}
+
+// CompleteMultipartUpload assembles the object from the parts, in the order of their numbers.
+func (adapter Adapter) CompleteMultipartUpload(ctx context.Context, path, uploadID string, parts []entities.StoragePart) error {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return err
+	}
+
+	client, err := adapter.NewClient(ctx)
+	if err != nil {
+		return storage.Err("create S3 client", err)
+	}
+
+	completed := make([]types.CompletedPart, len(parts))
+	for i, part := range parts {
+		completed[i] = types.CompletedPart{
+			PartNumber:     aws.Int32(int32(part.Number)),
+			ETag:           aws.String(part.ETag),
+			ChecksumSHA256: optional(part.Checksum),
+		}
+	}
+
+	if _, err := client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(adapter.Bucket),
+		Key:             aws.String(path),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	}); err != nil {
+		return storage.PathErr("complete multipart upload", path, err)
+	}
+
+	return nil
+}
+
+// AbortMultipartUpload discards a multipart upload and its parts.
+func (adapter Adapter) AbortMultipartUpload(ctx context.Context, path, uploadID string) error {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return err
+	}
+
+	client, err := adapter.NewClient(ctx)
+	if err != nil {
+		return storage.Err("create S3 client", err)
+	}
+
+	if _, err := client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(adapter.Bucket),
+		Key:      aws.String(path),
+		UploadId: aws.String(uploadID),
+	}); err != nil {
+		return storage.PathErr("abort multipart upload", path, err)
+	}
+
+	return nil
+}
@@ -13,6 +13,16 @@ import (
 )
 
 func (adapter Adapter) Copy(ctx context.Context, from, to string) (*entities.StorageObject, error) {
+	from, err := storage.NormalizePath(from)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err = storage.NormalizePath(to)
+	if err != nil {
+		return nil, err
+	}
+
 	name := path.Base(to)
 	mimetype := gomime.TypeByExtension(path.Ext(to))
 
@@ -63,8 +73,13 @@ func (adapter Adapter) Delete(ctx context.Context, paths ...string) error {
 
 	objects := make([]types.ObjectIdentifier, len(paths))
 	for i, p := range paths {
+		key, err := storage.NormalizePath(p)
+		if err != nil {
+			return err
+		}
+
 		objects[i] = types.ObjectIdentifier{
-			Key: aws.String(p),
+			Key: aws.String(key),
 		}
 	}
 
@@ -1,32 +1,31 @@
 package amazon_s3
 
 import (
+	"cmp"
 	"context"
+	"io"
 	"path"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	gomime "github.com/cubewise-code/go-mime"
 	"github.com/gonstruct/providers/entities"
 	"github.com/gonstruct/providers/storage"
 )
 
+// PutFile stores the file, large files are sent with a multipart upload like PutStream.
 func (adapter Adapter) PutFile(ctx context.Context, input entities.StorageInput) (*entities.StorageObject, error) {
 	extension := input.File.Extension()
-	mimetype := gomime.TypeByExtension(extension)
-	key := path.Join(input.Path, input.ID+extension)
+	mimetype := cmp.Or(input.Options.ContentType, gomime.TypeByExtension(extension))
+	key, err := storage.NormalizePath(path.Join(input.Path, input.ID+extension))
+	if err != nil {
+		return nil, err
+	}
 
 	client, err := adapter.NewClient(ctx)
 	if err != nil {
 		return nil, storage.Err("create S3 client", err)
 	}
 
-	if _, err := client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(adapter.Bucket),
-		Key:         aws.String(key),
-		ContentType: aws.String(mimetype),
-		Body:        input.File.Body,
-	}); err != nil {
+	if err := adapter.upload(ctx, client, key, mimetype, input.File.Body, input.Options); err != nil {
 		return nil, storage.PathErr("put file", key, err)
 	}
 
@@ -36,3 +35,33 @@ func (adapter Adapter) PutFile(ctx context.Context, input entities.StorageInput)
 		MimeType: mimetype,
 	}, nil
 }
+
+// Write stores the stream like PutStream, with the content type, ACL, headers and user defined
+// metadata of the options. IfNoneMatch and IfMatch are sent as conditional write headers, which
+// S3 checks atomically when the object is stored.
+func (adapter Adapter) Write(ctx context.Context, key string, body io.Reader, options entities.StorageWriteOptions) error {
+	key, err := storage.NormalizePath(key)
+	if err != nil {
+		return err
+	}
+
+	client, err := adapter.NewClient(ctx)
+	if err != nil {
+		return storage.Err("create S3 client", err)
+	}
+
+	if err := adapter.upload(ctx, client, key, mimeType(key), body, options); err != nil {
+		return storage.PathErr("write", key, err)
+	}
+
+	return nil
+}
+
+// mimeType returns the MIME type for the extension of key, application/octet-stream when it is unknown.
+func mimeType(key string) string {
+	if mimetype := gomime.TypeByExtension(path.Ext(key)); mimetype != "" {
+		return mimetype
+	}
+
+	return "application/octet-stream"
+}
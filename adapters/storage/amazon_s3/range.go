@@ -0,0 +1,224 @@
+package amazon_s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/storage"
+)
+
+// GetRange returns a reader for length bytes of the object from offset with a ranged GetObject
+// request, or for the rest of the object when length is negative.
+func (adapter Adapter) GetRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error) {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset < 0 {
+		return nil, storage.PathErr("get range", path, storage.ErrInvalidRange)
+	}
+
+	client, err := adapter.NewClient(ctx)
+	if err != nil {
+		return nil, storage.Err("create S3 client", err)
+	}
+
+	// S3 has no empty ranges, it returns the whole object for them
+	if length == 0 {
+		return adapter.emptyRange(ctx, client, path, offset)
+	}
+
+	result, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(adapter.Bucket),
+		Key:    aws.String(path),
+		Range:  byteRange(offset, length),
+	})
+	if err != nil {
+		err = readErr(err)
+
+		// S3 also rejects ranges that start at the end of the object, which are empty
+		if errors.Is(err, storage.ErrInvalidRange) {
+			return adapter.emptyRange(ctx, client, path, offset)
+		}
+
+		return nil, storage.PathErr("get range", path, err)
+	}
+
+	return result.Body, nil
+}
+
+// emptyRange returns an empty reader when offset is within the object, with a HeadObject request.
+func (adapter Adapter) emptyRange(ctx context.Context, client *s3.Client, path string, offset int64) (io.ReadCloser, error) {
+	result, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(adapter.Bucket),
+		Key:    aws.String(path),
+	})
+	if err != nil {
+		return nil, storage.PathErr("get range", path, readErr(err))
+	}
+
+	if _, err := entities.StorageRangeLength(offset, 0, aws.ToInt64(result.ContentLength)); err != nil {
+		return nil, storage.PathErr("get range", path, err)
+	}
+
+	return http.NoBody, nil
+}
+
+// Open returns a seekable reader for the object. It reads the size and ETag of the object with a
+// HeadObject request when it is opened, and the object with a GetObject request from the offset
+// of the first read after every seek, so only the parts that are read are downloaded. The requests
+// are conditional on the ETag, reads fail with storage.ErrPreconditionFailed when the object was
+// overwritten since it was opened.
+func (adapter Adapter) Open(ctx context.Context, path string) (io.ReadSeekCloser, error) {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := adapter.NewClient(ctx)
+	if err != nil {
+		return nil, storage.Err("create S3 client", err)
+	}
+
+	result, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(adapter.Bucket),
+		Key:    aws.String(path),
+	})
+	if err != nil {
+		return nil, storage.PathErr("open", path, readErr(err))
+	}
+
+	return &objectReader{
+		ctx:    ctx,
+		client: client,
+		bucket: adapter.Bucket,
+		key:    path,
+		etag:   aws.ToString(result.ETag),
+		size:   aws.ToInt64(result.ContentLength),
+	}, nil
+}
+
+// objectReader reads an object with ranged GetObject requests, see Open.
+type objectReader struct {
+	ctx    context.Context
+	client *s3.Client
+	bucket string
+	key    string
+	etag   string
+	size   int64
+
+	offset int64
+	// body is the response to the request from offset, nil until the first read after a seek
+	body io.ReadCloser
+}
+
+func (reader *objectReader) Read(p []byte) (int, error) {
+	if reader.offset >= reader.size {
+		return 0, io.EOF
+	}
+
+	if reader.body == nil {
+		result, err := reader.client.GetObject(reader.ctx, &s3.GetObjectInput{
+			Bucket:  aws.String(reader.bucket),
+			Key:     aws.String(reader.key),
+			Range:   byteRange(reader.offset, -1),
+			IfMatch: optional(reader.etag),
+		})
+		if err != nil {
+			return 0, storage.PathErr("read", reader.key, readErr(err))
+		}
+
+		reader.body = result.Body
+	}
+
+	n, err := reader.body.Read(p)
+	reader.offset += int64(n)
+
+	if errors.Is(err, io.EOF) && reader.offset < reader.size {
+		return n, storage.PathErr("read", reader.key, io.ErrUnexpectedEOF)
+	}
+
+	return n, err
+}
+
+func (reader *objectReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += reader.offset
+	case io.SeekEnd:
+		offset += reader.size
+	default:
+		return 0, storage.PathErr("seek", reader.key, fmt.Errorf("invalid whence %d", whence))
+	}
+
+	if offset < 0 {
+		return 0, storage.PathErr("seek", reader.key, storage.ErrInvalidRange)
+	}
+
+	if offset != reader.offset {
+		// The next read requests the object from the new offset
+		if err := reader.Close(); err != nil {
+			return 0, err
+		}
+
+		reader.offset = offset
+	}
+
+	return offset, nil
+}
+
+func (reader *objectReader) Close() error {
+	if reader.body == nil {
+		return nil
+	}
+
+	body := reader.body
+	reader.body = nil
+
+	if err := body.Close(); err != nil {
+		return storage.PathErr("close", reader.key, err)
+	}
+
+	return nil
+}
+
+// byteRange returns the Range header of a read of length bytes from offset, nil for the whole
+// object, which unlike "bytes=0-" is also valid for empty objects.
+func byteRange(offset, length int64) *string {
+	switch {
+	case offset == 0 && length < 0:
+		return nil
+	case length < 0:
+		return aws.String(fmt.Sprintf("bytes=%d-", offset))
+	default:
+		return aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	}
+}
+
+// readErr wraps the errors of GetObject and HeadObject requests with the storage errors.
+func readErr(err error) error {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	switch apiErr.ErrorCode() {
+	case "NoSuchKey", "NotFound":
+		return fmt.Errorf("%w: %w", storage.ErrFileNotFound, err)
+	case "InvalidRange":
+		return fmt.Errorf("%w: %w", storage.ErrInvalidRange, err)
+	case "PreconditionFailed":
+		return fmt.Errorf("%w: %w", storage.ErrPreconditionFailed, err)
+	default:
+		return err
+	}
+}
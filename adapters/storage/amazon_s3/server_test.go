@@ -0,0 +1,578 @@
+package amazon_s3_test
+
+import (
+	"cmp"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gonstruct/providers/adapters/storage/amazon_s3"
+)
+
+const testBucket = "test-bucket"
+
+// s3Server is an in-process stand-in for the parts of the S3 API the adapter uses,
+// with path style addressing.
+type s3Server struct {
+	mu      sync.Mutex
+	objects map[string]*s3Object
+	uploads map[string]*s3Upload
+	nextID  int
+
+	// Aborted are the IDs of aborted multipart uploads
+	Aborted []string
+	// Requests are the received requests as "METHOD key?query"
+	Requests []string
+	// FailPart makes uploading the part with this number fail
+	FailPart int
+	// MaxKeys caps the keys of a listing page, like S3 caps them at 1000
+	MaxKeys int
+	// Ranges are the Range headers of the GetObject requests, empty for requests without one
+	Ranges []string
+}
+
+type s3Object struct {
+	Content      []byte
+	ContentType  string
+	LastModified time.Time
+	// Header are the stored Cache-Control, Content-Disposition, Content-Encoding and x-amz-meta-* headers
+	Header http.Header
+	// Checksum is the SHA-256 checksum the object was uploaded with
+	Checksum string
+	// ACL is the canned ACL the object was uploaded with
+	ACL string
+}
+
+type s3Upload struct {
+	key         string
+	contentType string
+	header      http.Header
+	acl         string
+	parts       map[int][]byte
+}
+
+// storedHeaders are the request headers S3 stores with an object and returns for it.
+var storedHeaders = []string{"Cache-Control", "Content-Disposition", "Content-Encoding"}
+
+func objectHeader(r *http.Request) http.Header {
+	header := make(http.Header)
+
+	for name, values := range r.Header {
+		if slices.Contains(storedHeaders, name) || strings.HasPrefix(strings.ToLower(name), "x-amz-meta-") {
+			header[name] = values
+		}
+	}
+
+	return header
+}
+
+func newS3Server(t *testing.T) (*s3Server, amazon_s3.Adapter) {
+	t.Helper()
+
+	t.Setenv("AWS_CONFIG_FILE", "/dev/null")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/dev/null")
+
+	server := &s3Server{objects: make(map[string]*s3Object), uploads: make(map[string]*s3Upload)}
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	return server, amazon_s3.Adapter{
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+		Region:          "us-east-1",
+		Bucket:          testBucket,
+		Endpoint:        httpServer.URL,
+		UsePathStyle:    true,
+	}
+}
+
+// Object returns the stored object with the given key.
+func (server *s3Server) Object(key string) *s3Object {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return server.objects[key]
+}
+
+// PendingUploads returns the number of multipart uploads that were not completed or aborted.
+func (server *s3Server) PendingUploads() int {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return len(server.uploads)
+}
+
+func (server *s3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/"+testBucket && r.URL.Query().Get("list-type") == "2" {
+		server.list(w, r)
+
+		return
+	}
+
+	if r.URL.Path == "/"+testBucket && r.Method == http.MethodPost && r.URL.Query().Has("delete") {
+		server.deleteObjects(w, r)
+
+		return
+	}
+
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+testBucket+"/")
+	if !ok {
+		s3Error(w, http.StatusNotFound, "NoSuchBucket")
+
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s3Error(w, http.StatusBadRequest, "IncompleteBody")
+
+		return
+	}
+
+	query := r.URL.Query()
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.Requests = append(server.Requests, r.Method+" "+key+"?"+r.URL.RawQuery)
+
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		server.nextID++
+		id := "upload-" + strconv.Itoa(server.nextID)
+		server.uploads[id] = &s3Upload{
+			key: key, contentType: r.Header.Get("Content-Type"), header: objectHeader(r), acl: r.Header.Get("X-Amz-Acl"), parts: make(map[int][]byte),
+		}
+
+		writeXML(w, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			Bucket   string
+			Key      string
+			UploadId string
+		}{Bucket: testBucket, Key: key, UploadId: id})
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		server.uploadPart(w, r, body)
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		if server.preconditionFailed(w, r, key) {
+			return
+		}
+
+		server.completeUpload(w, query.Get("uploadId"), key, body)
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		delete(server.uploads, query.Get("uploadId"))
+		server.Aborted = append(server.Aborted, query.Get("uploadId"))
+
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut && query.Has("acl"):
+		object, ok := server.objects[key]
+		if !ok {
+			s3Error(w, http.StatusNotFound, "NoSuchKey")
+
+			return
+		}
+
+		object.ACL = r.Header.Get("X-Amz-Acl")
+	case r.Method == http.MethodPut:
+		if server.preconditionFailed(w, r, key) {
+			return
+		}
+
+		if !validChecksum(r, body) {
+			s3Error(w, http.StatusBadRequest, "BadDigest")
+
+			return
+		}
+
+		server.objects[key] = &s3Object{
+			Content:      body,
+			ContentType:  r.Header.Get("Content-Type"),
+			LastModified: time.Now().UTC(),
+			Header:       objectHeader(r),
+			Checksum:     r.Header.Get("X-Amz-Checksum-Sha256"),
+			ACL:          r.Header.Get("X-Amz-Acl"),
+		}
+
+		w.Header().Set("ETag", etag(body))
+	case r.Method == http.MethodGet && query.Has("acl"):
+		object, ok := server.objects[key]
+		if !ok {
+			s3Error(w, http.StatusNotFound, "NoSuchKey")
+
+			return
+		}
+
+		type grant struct {
+			Grantee struct {
+				XMLName xml.Name `xml:"Grantee"`
+				Type    string   `xml:"http://www.w3.org/2001/XMLSchema-instance type,attr"`
+				URI     string
+			}
+			Permission string
+		}
+
+		var policy struct {
+			XMLName           xml.Name `xml:"AccessControlPolicy"`
+			AccessControlList struct {
+				Grant []grant
+			}
+		}
+
+		if object.ACL == "public-read" {
+			public := grant{Permission: "READ"}
+			public.Grantee.Type, public.Grantee.URI = "Group", "http://acs.amazonaws.com/groups/global/AllUsers"
+			policy.AccessControlList.Grant = append(policy.AccessControlList.Grant, public)
+		}
+
+		writeXML(w, policy)
+	case r.Method == http.MethodGet, r.Method == http.MethodHead:
+		object, ok := server.objects[key]
+		if !ok {
+			s3Error(w, http.StatusNotFound, "NoSuchKey")
+
+			return
+		}
+
+		for name, values := range object.Header {
+			w.Header()[name] = values
+		}
+
+		if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && ifMatch != etag(object.Content) {
+			s3Error(w, http.StatusPreconditionFailed, "PreconditionFailed")
+
+			return
+		}
+
+		content, status := object.Content, http.StatusOK
+
+		if r.Method == http.MethodGet {
+			server.Ranges = append(server.Ranges, r.Header.Get("Range"))
+
+			if header := r.Header.Get("Range"); header != "" {
+				start, end, ok := parseRange(header, len(object.Content))
+				if !ok {
+					s3Error(w, http.StatusRequestedRangeNotSatisfiable, "InvalidRange")
+
+					return
+				}
+
+				content, status = object.Content[start:end+1], http.StatusPartialContent
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(object.Content)))
+			}
+		}
+
+		w.Header().Set("Content-Type", object.ContentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Header().Set("ETag", etag(object.Content))
+		w.Header().Set("Last-Modified", object.LastModified.Format(http.TimeFormat))
+
+		if r.Header.Get("X-Amz-Checksum-Mode") == "ENABLED" && object.Checksum != "" {
+			w.Header().Set("X-Amz-Checksum-Sha256", object.Checksum)
+		}
+
+		w.WriteHeader(status)
+
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(content)
+		}
+	default:
+		s3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+// list implements ListObjectsV2, the continuation token is the last key or common prefix of the previous page.
+func (server *s3Server) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	prefix, delimiter, token := query.Get("prefix"), query.Get("delimiter"), query.Get("continuation-token")
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	maxKeys := cmp.Or(server.MaxKeys, 1000)
+	if value, err := strconv.Atoi(query.Get("max-keys")); err == nil {
+		maxKeys = min(value, maxKeys)
+	}
+
+	server.Requests = append(server.Requests, "LIST "+r.URL.RawQuery)
+
+	keys := make([]string, 0, len(server.objects))
+	for key := range server.objects {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	type content struct {
+		Key          string
+		LastModified string
+		ETag         string
+		Size         int
+	}
+
+	type commonPrefix struct {
+		Prefix string
+	}
+
+	var result struct {
+		XMLName               xml.Name `xml:"ListBucketResult"`
+		Name                  string
+		Prefix                string
+		IsTruncated           bool
+		NextContinuationToken string `xml:",omitempty"`
+		Contents              []content
+		CommonPrefixes        []commonPrefix
+	}
+
+	result.Name, result.Prefix = testBucket, prefix
+
+	var last string
+
+	for _, key := range keys {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+
+		entry := key
+		if i := strings.Index(rest, delimiter); delimiter != "" && i >= 0 {
+			entry = prefix + rest[:i+len(delimiter)]
+		}
+
+		if entry <= token || entry == last {
+			continue
+		}
+
+		if len(result.Contents)+len(result.CommonPrefixes) == maxKeys {
+			result.IsTruncated = true
+			result.NextContinuationToken = last
+
+			break
+		}
+
+		last = entry
+
+		if entry != key {
+			result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{Prefix: entry})
+
+			continue
+		}
+
+		object := server.objects[key]
+		result.Contents = append(result.Contents, content{
+			Key:          key,
+			LastModified: object.LastModified.Format(time.RFC3339),
+			ETag:         etag(object.Content),
+			Size:         len(object.Content),
+		})
+	}
+
+	writeXML(w, result)
+}
+
+func (server *s3Server) uploadPart(w http.ResponseWriter, r *http.Request, body []byte) {
+	upload, ok := server.uploads[r.URL.Query().Get("uploadId")]
+	if !ok {
+		s3Error(w, http.StatusNotFound, "NoSuchUpload")
+
+		return
+	}
+
+	number, _ := strconv.Atoi(r.URL.Query().Get("partNumber"))
+	if number == server.FailPart {
+		s3Error(w, http.StatusForbidden, "AccessDenied")
+
+		return
+	}
+
+	if !validChecksum(r, body) || r.Header.Get("X-Amz-Checksum-Sha256") == "" {
+		s3Error(w, http.StatusBadRequest, "BadDigest")
+
+		return
+	}
+
+	upload.parts[number] = body
+
+	w.Header().Set("ETag", etag(body))
+}
+
+func (server *s3Server) deleteObjects(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Objects []struct {
+			Key string
+		} `xml:"Object"`
+	}
+
+	if err := xml.NewDecoder(r.Body).Decode(&request); err != nil {
+		s3Error(w, http.StatusBadRequest, "MalformedXML")
+
+		return
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	for _, object := range request.Objects {
+		server.Requests = append(server.Requests, "DELETE "+object.Key+"?")
+		delete(server.objects, object.Key)
+	}
+
+	writeXML(w, struct {
+		XMLName xml.Name `xml:"DeleteResult"`
+	}{})
+}
+
+func (server *s3Server) completeUpload(w http.ResponseWriter, id, key string, body []byte) {
+	upload, ok := server.uploads[id]
+	if !ok {
+		s3Error(w, http.StatusNotFound, "NoSuchUpload")
+
+		return
+	}
+
+	var request struct {
+		Parts []struct {
+			PartNumber     int
+			ETag           string
+			ChecksumSHA256 string
+		} `xml:"Part"`
+	}
+
+	if err := xml.Unmarshal(body, &request); err != nil {
+		s3Error(w, http.StatusBadRequest, "MalformedXML")
+
+		return
+	}
+
+	var (
+		content []byte
+		sums    []byte
+	)
+
+	for i, part := range request.Parts {
+		data, ok := upload.parts[part.PartNumber]
+		if !ok || part.PartNumber != i+1 || part.ETag != etag(data) || part.ChecksumSHA256 != sha256Base64(data) {
+			s3Error(w, http.StatusBadRequest, "InvalidPart")
+
+			return
+		}
+
+		// Like S3, all parts but the last one need at least the minimum part size
+		if i < len(request.Parts)-1 && len(data) < amazon_s3.MinPartSize {
+			s3Error(w, http.StatusBadRequest, "EntityTooSmall")
+
+			return
+		}
+
+		content = append(content, data...)
+		sum := sha256.Sum256(data)
+		sums = append(sums, sum[:]...)
+	}
+
+	delete(server.uploads, id)
+	server.objects[key] = &s3Object{
+		Content:      content,
+		ContentType:  upload.contentType,
+		LastModified: time.Now().UTC(),
+		Header:       upload.header,
+		Checksum:     fmt.Sprintf("%s-%d", sha256Base64(sums), len(request.Parts)),
+		ACL:          upload.acl,
+	}
+
+	writeXML(w, struct {
+		XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+		Bucket  string
+		Key     string
+		ETag    string
+	}{Bucket: testBucket, Key: key, ETag: fmt.Sprintf(`"multipart-%d"`, len(request.Parts))})
+}
+
+// preconditionFailed responds like S3 when the If-None-Match or If-Match header of a write does not hold.
+func (server *s3Server) preconditionFailed(w http.ResponseWriter, r *http.Request, key string) bool {
+	object, exists := server.objects[key]
+
+	switch ifMatch := r.Header.Get("If-Match"); {
+	case r.Header.Get("If-None-Match") == "*" && exists:
+		s3Error(w, http.StatusPreconditionFailed, "PreconditionFailed")
+	case ifMatch != "" && !exists:
+		s3Error(w, http.StatusNotFound, "NoSuchKey")
+	case ifMatch != "" && ifMatch != etag(object.Content):
+		s3Error(w, http.StatusPreconditionFailed, "PreconditionFailed")
+	default:
+		return false
+	}
+
+	return true
+}
+
+// parseRange returns the inclusive bounds of a "bytes=start-end" or "bytes=start-" header, which
+// S3 rejects when start is not within the object.
+func parseRange(header string, size int) (start, end int, ok bool) {
+	bounds, ok := strings.CutPrefix(header, "bytes=")
+	if !ok {
+		return 0, 0, false
+	}
+
+	first, last, _ := strings.Cut(bounds, "-")
+
+	start, err := strconv.Atoi(first)
+	if err != nil || start >= size {
+		return 0, 0, false
+	}
+
+	end = size - 1
+	if last != "" {
+		if end, err = strconv.Atoi(last); err != nil || end < start {
+			return 0, 0, false
+		}
+	}
+
+	return start, min(end, size-1), true
+}
+
+func validChecksum(r *http.Request, body []byte) bool {
+	sum := r.Header.Get("X-Amz-Checksum-Sha256")
+
+	return sum == "" || sum == sha256Base64(body)
+}
+
+func sha256Base64(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func etag(content []byte) string {
+	sum := md5.Sum(content) //nolint:gosec
+
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func writeXML(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/xml")
+
+	_ = xml.NewEncoder(w).Encode(value)
+}
+
+func s3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+
+	_ = xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string
+		Message string
+	}{Code: code, Message: code})
+}
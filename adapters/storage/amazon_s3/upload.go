@@ -0,0 +1,255 @@
+package amazon_s3
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/storage"
+)
+
+// abortTimeout limits how long aborting a failed multipart upload may take after ctx is done.
+const abortTimeout = 30 * time.Second
+
+// upload stores body at key. Bodies up to a part are stored with a single PutObject, larger
+// bodies are streamed with a multipart upload, so bodies of any size and readers that cannot
+// seek are never read into memory as a whole. Every part is sent with its SHA-256 checksum.
+func (adapter Adapter) upload(
+	ctx context.Context, client *s3.Client, key, mimetype string, body io.Reader, options entities.StorageWriteOptions,
+) error {
+	partSize := adapter.partSize()
+	mimetype = cmp.Or(options.ContentType, mimetype)
+
+	part, err := readPart(body, partSize)
+	if err != nil {
+		return err
+	}
+
+	if int64(len(part)) < partSize {
+		_, err := client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:             aws.String(adapter.Bucket),
+			Key:                aws.String(key),
+			ContentType:        aws.String(mimetype),
+			Body:               bytes.NewReader(part),
+			ChecksumSHA256:     aws.String(checksum(part)),
+			ACL:                objectACL(options.Visibility),
+			CacheControl:       optional(options.CacheControl),
+			ContentDisposition: optional(options.ContentDisposition),
+			ContentEncoding:    optional(options.ContentEncoding),
+			Metadata:           options.Metadata,
+			IfNoneMatch:        optional(options.IfNoneMatch),
+			IfMatch:            optional(options.IfMatch),
+		})
+
+		return preconditionErr(err, options)
+	}
+
+	created, err := adapter.createMultipartUpload(ctx, client, key, mimetype, options)
+	if err != nil {
+		return err
+	}
+
+	parts, err := adapter.uploadParts(ctx, client, key, created.UploadId, part, body)
+	if err == nil {
+		_, err = client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(adapter.Bucket),
+			Key:             aws.String(key),
+			UploadId:        created.UploadId,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+			// The preconditions are checked when the upload is completed
+			IfNoneMatch: optional(options.IfNoneMatch),
+			IfMatch:     optional(options.IfMatch),
+		})
+		err = preconditionErr(err, options)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	// S3 keeps, and bills, the parts of an upload until it is completed or aborted,
+	// so the upload is aborted even when ctx was cancelled
+	abortCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), abortTimeout)
+	defer cancel()
+
+	if _, abortErr := client.AbortMultipartUpload(abortCtx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(adapter.Bucket),
+		Key:      aws.String(key),
+		UploadId: created.UploadId,
+	}); abortErr != nil {
+		return errors.Join(err, fmt.Errorf("abort multipart upload: %w", abortErr))
+	}
+
+	return err
+}
+
+// createMultipartUpload starts a multipart upload of an object with the write options, its parts are
+// sent with SHA-256 checksums.
+func (adapter Adapter) createMultipartUpload(
+	ctx context.Context, client *s3.Client, key, mimetype string, options entities.StorageWriteOptions,
+) (*s3.CreateMultipartUploadOutput, error) {
+	created, err := client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:             aws.String(adapter.Bucket),
+		Key:                aws.String(key),
+		ContentType:        aws.String(mimetype),
+		ChecksumAlgorithm:  types.ChecksumAlgorithmSha256,
+		ACL:                objectACL(options.Visibility),
+		CacheControl:       optional(options.CacheControl),
+		ContentDisposition: optional(options.ContentDisposition),
+		ContentEncoding:    optional(options.ContentEncoding),
+		Metadata:           options.Metadata,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create multipart upload: %w", err)
+	}
+
+	return created, nil
+}
+
+// uploadParts uploads the first part and the rest of body in parts, with at most
+// UploadConcurrency parts in flight, and returns the completed parts in order.
+func (adapter Adapter) uploadParts(
+	ctx context.Context, client *s3.Client, key string, uploadID *string, part []byte, body io.Reader,
+) ([]types.CompletedPart, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	partSize := adapter.partSize()
+	semaphore := make(chan struct{}, adapter.uploadConcurrency())
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		parts []types.CompletedPart
+	)
+
+	for number := int32(1); len(part) > 0 && ctx.Err() == nil; number++ {
+		if number > MaxParts {
+			cancel(fmt.Errorf("body is larger than %d parts of %d bytes, increase PartSize", MaxParts, partSize))
+
+			break
+		}
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(number int32, part []byte) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			sum := checksum(part)
+
+			result, err := client.UploadPart(ctx, &s3.UploadPartInput{
+				Bucket:         aws.String(adapter.Bucket),
+				Key:            aws.String(key),
+				UploadId:       uploadID,
+				PartNumber:     aws.Int32(number),
+				Body:           bytes.NewReader(part),
+				ChecksumSHA256: aws.String(sum),
+			})
+			if err != nil {
+				cancel(fmt.Errorf("upload part %d: %w", number, err))
+
+				return
+			}
+
+			mu.Lock()
+			parts = append(parts, types.CompletedPart{ETag: result.ETag, PartNumber: aws.Int32(number), ChecksumSHA256: aws.String(sum)})
+			mu.Unlock()
+		}(number, part)
+
+		var err error
+		if part, err = readPart(body, partSize); err != nil {
+			cancel(err)
+		}
+	}
+
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(parts, func(i, j int) bool { return *parts[i].PartNumber < *parts[j].PartNumber })
+
+	return parts, nil
+}
+
+func (adapter Adapter) partSize() int64 {
+	if adapter.PartSize == 0 {
+		return DefaultPartSize
+	}
+
+	return max(adapter.PartSize, MinPartSize)
+}
+
+func (adapter Adapter) uploadConcurrency() int {
+	if adapter.UploadConcurrency <= 0 {
+		return DefaultUploadConcurrency
+	}
+
+	return adapter.UploadConcurrency
+}
+
+// readPart reads up to size bytes of body, an empty part means body is exhausted.
+func readPart(body io.Reader, size int64) ([]byte, error) {
+	part, err := io.ReadAll(io.LimitReader(body, size))
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+
+	return part, nil
+}
+
+// preconditionErr matches the errors of failed conditional writes with storage.ErrAlreadyExists
+// and storage.ErrPreconditionFailed.
+func preconditionErr(err error, options entities.StorageWriteOptions) error {
+	var apiErr smithy.APIError
+	if err == nil || !errors.As(err, &apiErr) {
+		return err
+	}
+
+	switch code := apiErr.ErrorCode(); {
+	case code == "PreconditionFailed" && options.IfNoneMatch != "":
+		return fmt.Errorf("%w: %w", storage.ErrAlreadyExists, err)
+	case code == "PreconditionFailed", code == "ConditionalRequestConflict",
+		code == "NoSuchKey" && options.IfMatch != "":
+		return fmt.Errorf("%w: %w", storage.ErrPreconditionFailed, err)
+	default:
+		return err
+	}
+}
+
+// optional returns nil for empty values, which the SDK does not send.
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return aws.String(value)
+}
+
+// checksum returns the base64 encoded SHA-256 checksum S3 verifies the content with.
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
@@ -3,15 +3,23 @@ package amazon_s3
 import (
 	"context"
 	"fmt"
+	"maps"
+	"net/http"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/gonstruct/providers/entities"
 	"github.com/gonstruct/providers/storage"
 )
 
-// URL returns the public URL for a file.
+// URL returns the public URL for a file, empty for paths storage.NormalizePath rejects.
 func (adapter Adapter) URL(path string) string {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return ""
+	}
+
 	if adapter.Endpoint != "" {
 		return fmt.Sprintf("%s/%s/%s", adapter.Endpoint, adapter.Bucket, path)
 	}
@@ -21,6 +29,11 @@ func (adapter Adapter) URL(path string) string {
 
 // TemporaryURL generates a presigned URL with an expiration time.
 func (adapter Adapter) TemporaryURL(ctx context.Context, path string, expiration time.Duration) (string, error) {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return "", err
+	}
+
 	client, err := adapter.NewClient(ctx)
 	if err != nil {
 		return "", storage.Err("create S3 client", err)
@@ -38,3 +51,109 @@ func (adapter Adapter) TemporaryURL(ctx context.Context, path string, expiration
 
 	return result.URL, nil
 }
+
+// TemporaryUploadURL returns a presigned PutObject request. The Header of the upload holds the
+// signed headers the client must send, the content type and the size when they are constrained.
+// S3 cannot limit the size of PUT uploads to MaxSize, use TemporaryUploadForm or ContentLength.
+func (adapter Adapter) TemporaryUploadURL(ctx context.Context, input entities.StorageUploadInput) (*entities.StorageUpload, error) {
+	path, err := storage.NormalizePath(input.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.MaxSize > 0 && input.ContentLength == 0 {
+		return nil, storage.PathErr("generate presigned upload url", path, fmt.Errorf("%w: MaxSize of PUT uploads", storage.ErrNotSupported))
+	}
+
+	client, err := adapter.NewClient(ctx)
+	if err != nil {
+		return nil, storage.Err("create S3 client", err)
+	}
+
+	params := &s3.PutObjectInput{
+		Bucket:      aws.String(adapter.Bucket),
+		Key:         aws.String(path),
+		ContentType: optional(input.ContentType),
+		ACL:         objectACL(input.Visibility),
+	}
+
+	if input.ContentLength > 0 {
+		params.ContentLength = aws.Int64(input.ContentLength)
+	}
+
+	result, err := s3.NewPresignClient(client).PresignPutObject(ctx, params, s3.WithPresignExpires(input.Expiration))
+	if err != nil {
+		return nil, storage.PathErr("generate presigned upload url", path, err)
+	}
+
+	header := make(map[string]string, len(result.SignedHeader))
+
+	for name := range result.SignedHeader {
+		// Clients send the Host header of the URL
+		if name != "Host" {
+			header[name] = result.SignedHeader.Get(name)
+		}
+	}
+
+	return &entities.StorageUpload{
+		Method:    result.Method,
+		URL:       result.URL,
+		Header:    header,
+		ExpiresAt: time.Now().Add(input.Expiration),
+	}, nil
+}
+
+// TemporaryUploadForm returns a presigned POST request with a policy that enforces the content
+// type, size and visibility of the upload.
+func (adapter Adapter) TemporaryUploadForm(ctx context.Context, input entities.StorageUploadInput) (*entities.StorageUpload, error) {
+	path, err := storage.NormalizePath(input.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := adapter.NewClient(ctx)
+	if err != nil {
+		return nil, storage.Err("create S3 client", err)
+	}
+
+	fields := map[string]string{}
+
+	var conditions []any
+
+	if input.ContentType != "" {
+		fields["Content-Type"] = input.ContentType
+		conditions = append(conditions, map[string]string{"Content-Type": input.ContentType})
+	}
+
+	if input.Visibility != "" {
+		fields["acl"] = string(cannedACL(input.Visibility))
+		conditions = append(conditions, map[string]string{"acl": fields["acl"]})
+	}
+
+	switch {
+	case input.ContentLength > 0:
+		conditions = append(conditions, []any{"content-length-range", input.ContentLength, input.ContentLength})
+	case input.MaxSize > 0:
+		conditions = append(conditions, []any{"content-length-range", 0, input.MaxSize})
+	}
+
+	result, err := s3.NewPresignClient(client).PresignPostObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(adapter.Bucket),
+		Key:    aws.String(path),
+	}, func(options *s3.PresignPostOptions) {
+		options.Expires = input.Expiration
+		options.Conditions = conditions
+	})
+	if err != nil {
+		return nil, storage.PathErr("generate presigned upload form", path, err)
+	}
+
+	maps.Copy(fields, result.Values)
+
+	return &entities.StorageUpload{
+		Method:    http.MethodPost,
+		URL:       result.URL,
+		Fields:    fields,
+		ExpiresAt: time.Now().Add(input.Expiration),
+	}, nil
+}
@@ -12,6 +12,11 @@ import (
 
 // GetVisibility returns the visibility of a file.
 func (adapter Adapter) GetVisibility(ctx context.Context, path string) (entities.Visibility, error) {
+	path, err := storage.NormalizePath(path)
+	if err != nil {
+		return "", err
+	}
+
 	client, err := adapter.NewClient(ctx)
 	if err != nil {
 		return "", storage.Err("create S3 client", err)
@@ -39,26 +44,20 @@ func (adapter Adapter) GetVisibility(ctx context.Context, path string) (entities
 
 // SetVisibility changes the visibility of a file.
 func (adapter Adapter) SetVisibility(ctx context.Context, path string, visibility entities.Visibility) error {
-	client, err := adapter.NewClient(ctx)
+	path, err := storage.NormalizePath(path)
 	if err != nil {
-		return storage.Err("create S3 client", err)
+		return err
 	}
 
-	var acl types.ObjectCannedACL
-
-	switch visibility {
-	case entities.VisibilityPublic:
-		acl = types.ObjectCannedACLPublicRead
-	case entities.VisibilityPrivate:
-		acl = types.ObjectCannedACLPrivate
-	default:
-		acl = types.ObjectCannedACLPrivate
+	client, err := adapter.NewClient(ctx)
+	if err != nil {
+		return storage.Err("create S3 client", err)
 	}
 
 	_, err = client.PutObjectAcl(ctx, &s3.PutObjectAclInput{
 		Bucket: aws.String(adapter.Bucket),
 		Key:    aws.String(path),
-		ACL:    acl,
+		ACL:    cannedACL(visibility),
 	})
 	if err != nil {
 		return storage.PathErr("set visibility", path, err)
@@ -66,3 +65,24 @@ func (adapter Adapter) SetVisibility(ctx context.Context, path string, visibilit
 
 	return nil
 }
+
+func cannedACL(visibility entities.Visibility) types.ObjectCannedACL {
+	switch visibility {
+	case entities.VisibilityPublic:
+		return types.ObjectCannedACLPublicRead
+	case entities.VisibilityPrivate:
+		return types.ObjectCannedACLPrivate
+	default:
+		return types.ObjectCannedACLPrivate
+	}
+}
+
+// objectACL returns the ACL objects are uploaded with, none when no visibility is given, so
+// uploads to buckets with ACLs disabled only fail when a visibility is requested.
+func objectACL(visibility entities.Visibility) types.ObjectCannedACL {
+	if visibility == "" {
+		return ""
+	}
+
+	return cannedACL(visibility)
+}
@@ -1,15 +1,15 @@
 package fake
 
 import (
-	"errors"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/gonstruct/providers/entities"
 )
 
-// ErrFileNotFound is returned when a file does not exist.
-var ErrFileNotFound = errors.New("file not found")
+// ErrFileNotFound is returned when a file does not exist, it is storage.ErrFileNotFound.
+var ErrFileNotFound = entities.ErrStorageFileNotFound
 
 // Adapter is an in-memory storage adapter for testing.
 type Adapter struct {
@@ -23,6 +23,7 @@ type Adapter struct {
 	DeleteCalls  [][]string
 	CopyCalls    []CopyCall
 	MoveCalls    []MoveCall
+	UploadCalls  []entities.StorageUploadInput
 
 	// Error injection
 	PutFileError       error
@@ -30,9 +31,12 @@ type Adapter struct {
 	PutStreamError     error
 	GetError           error
 	GetStreamError     error
+	GetRangeError      error
+	OpenError          error
 	ExistsError        error
 	SizeError          error
 	LastModifiedError  error
+	StatError          error
 	MimeTypeError      error
 	CopyError          error
 	MoveError          error
@@ -44,6 +48,7 @@ type Adapter struct {
 	MakeDirectoryError error
 	DeleteDirError     error
 	TemporaryURLError  error
+	UploadURLError     error
 
 	// BaseURL for URL generation
 	BaseURL string
@@ -54,6 +59,7 @@ type fakeFile struct {
 	MimeType     string
 	Visibility   entities.Visibility
 	LastModified time.Time
+	Options      entities.StorageWriteOptions
 }
 
 type PutFileCall struct {
@@ -96,6 +102,7 @@ func (a *Adapter) Reset() {
 	a.DeleteCalls = nil
 	a.CopyCalls = nil
 	a.MoveCalls = nil
+	a.UploadCalls = nil
 }
 
 // --- Helper Methods ---
@@ -143,3 +150,13 @@ func (a *Adapter) StoredPaths() []string {
 
 	return paths
 }
+
+// normalize returns the normalized form of path like the other adapters, see entities.NormalizeStoragePath.
+func normalize(path string) (string, error) {
+	normalized, err := entities.NormalizeStoragePath(path)
+	if err != nil {
+		return "", fmt.Errorf("storage: normalize %q: %w", path, err)
+	}
+
+	return normalized, nil
+}
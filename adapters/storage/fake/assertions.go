@@ -48,6 +48,25 @@ func (a *Adapter) AssertStoredContent(t testing.TB, path string, expected []byte
 	}
 }
 
+// AssertStoredWithMetadata asserts that a file was stored with the user defined metadata key set to value.
+func (a *Adapter) AssertStoredWithMetadata(t testing.TB, path, key, value string) {
+	t.Helper()
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	file, ok := a.files[path]
+	if !ok {
+		t.Errorf("Expected file to be stored at %q, but it was not", path)
+
+		return
+	}
+
+	if got, ok := file.Options.Metadata[key]; !ok || got != value {
+		t.Errorf("Expected file at %q to be stored with metadata %s=%q, got %v", path, key, value, file.Options.Metadata)
+	}
+}
+
 // AssertDeleted asserts that files were deleted.
 func (a *Adapter) AssertDeleted(t testing.TB, paths ...string) {
 	t.Helper()
@@ -89,3 +108,19 @@ func (a *Adapter) AssertNothingStored(t testing.TB) {
 		t.Errorf("Expected no files to be stored, but %d put operations occurred", len(a.PutFileCalls)+len(a.PutCalls))
 	}
 }
+
+// AssertUploadPresigned asserts that an upload to the given path was presigned.
+func (a *Adapter) AssertUploadPresigned(t testing.TB, path string) {
+	t.Helper()
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	for _, upload := range a.UploadCalls {
+		if upload.Path == path {
+			return
+		}
+	}
+
+	t.Errorf("Expected an upload to %q to be presigned, but it was not", path)
+}
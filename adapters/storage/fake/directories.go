@@ -2,6 +2,11 @@ package fake
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"strings"
+
+	"github.com/gonstruct/providers/entities"
 )
 
 func (a *Adapter) Files(ctx context.Context, directory string) ([]string, error) {
@@ -9,6 +14,11 @@ func (a *Adapter) Files(ctx context.Context, directory string) ([]string, error)
 		return nil, a.FilesError
 	}
 
+	directory, err := normalize(directory)
+	if err != nil {
+		return nil, err
+	}
+
 	return a.listFiles(directory, false), nil
 }
 
@@ -17,6 +27,11 @@ func (a *Adapter) AllFiles(ctx context.Context, directory string) ([]string, err
 		return nil, a.FilesError
 	}
 
+	directory, err := normalize(directory)
+	if err != nil {
+		return nil, err
+	}
+
 	return a.listFiles(directory, true), nil
 }
 
@@ -62,6 +77,11 @@ func (a *Adapter) Directories(ctx context.Context, directory string) ([]string,
 		return nil, a.DirectoriesError
 	}
 
+	directory, err := normalize(directory)
+	if err != nil {
+		return nil, err
+	}
+
 	return a.listDirectories(directory, false), nil
 }
 
@@ -70,6 +90,11 @@ func (a *Adapter) AllDirectories(ctx context.Context, directory string) ([]strin
 		return nil, a.DirectoriesError
 	}
 
+	directory, err := normalize(directory)
+	if err != nil {
+		return nil, err
+	}
+
 	return a.listDirectories(directory, true), nil
 }
 
@@ -125,6 +150,11 @@ func (a *Adapter) MakeDirectory(ctx context.Context, path string) error {
 		return a.MakeDirectoryError
 	}
 
+	path, err := normalize(path)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -133,6 +163,11 @@ func (a *Adapter) DeleteDirectory(ctx context.Context, directory string) error {
 		return a.DeleteDirError
 	}
 
+	directory, err := normalize(directory)
+	if err != nil {
+		return err
+	}
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -149,3 +184,56 @@ func (a *Adapter) DeleteDirectory(ctx context.Context, directory string) error {
 
 	return nil
 }
+
+// List returns a page of the stored files and the directories they imply, sorted by path.
+func (a *Adapter) List(ctx context.Context, input entities.StorageListInput) (*entities.StoragePage, error) {
+	if a.FilesError != nil {
+		return nil, a.FilesError
+	}
+
+	directory, err := normalize(input.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	prefix := entities.DirectoryPrefix(directory)
+	dirs := make(map[string]bool)
+
+	var entries []entities.StorageEntry
+
+	for path, file := range a.files {
+		rest, ok := strings.CutPrefix(path, prefix)
+		if !ok {
+			continue
+		}
+
+		if slash := strings.IndexByte(rest, '/'); slash >= 0 && !input.Recursive {
+			dirs[prefix+rest[:slash]] = true
+
+			continue
+		}
+
+		entries = append(entries, entities.StorageEntry{
+			Path:         path,
+			Size:         int64(len(file.Content)),
+			LastModified: file.LastModified,
+			ETag:         etag(file.Content),
+		})
+	}
+
+	for dir := range dirs {
+		entries = append(entries, entities.StorageEntry{Path: dir, IsDir: true})
+	}
+
+	return input.Paginate(entries), nil
+}
+
+// etag returns the MD5 ETag S3 returns for objects uploaded with a single request.
+func etag(content []byte) string {
+	sum := md5.Sum(content) //nolint:gosec
+
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
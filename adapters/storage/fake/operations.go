@@ -2,8 +2,13 @@ package fake
 
 import (
 	"bytes"
+	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
 	"io"
+	"maps"
 	"time"
 
 	"github.com/gonstruct/providers/entities"
@@ -24,23 +29,27 @@ func (a *Adapter) PutFile(ctx context.Context, input entities.StorageInput) (*en
 		seeker.Seek(0, io.SeekStart)
 	}
 
-	path := input.Path + "/" + input.ID + input.File.Extension()
-	now := time.Now()
+	path, err := normalize(entities.DirectoryPrefix(input.Path) + input.ID + input.File.Extension())
+	if err != nil {
+		return nil, err
+	}
+
+	file := newFile(content, input.Options)
 
 	a.mu.Lock()
-	a.PutFileCalls = append(a.PutFileCalls, PutFileCall{Path: path, Content: content})
-	a.files[path] = &fakeFile{
-		Content:      content,
-		MimeType:     "application/octet-stream",
-		Visibility:   entities.VisibilityPrivate,
-		LastModified: now,
+	defer a.mu.Unlock()
+
+	if err := input.Options.CheckPreconditions(a.etag(path)); err != nil {
+		return nil, err
 	}
-	a.mu.Unlock()
+
+	a.PutFileCalls = append(a.PutFileCalls, PutFileCall{Path: path, Content: content})
+	a.files[path] = file
 
 	return &entities.StorageObject{
 		Name:     input.Name(),
 		Path:     path,
-		MimeType: "application/octet-stream",
+		MimeType: file.MimeType,
 	}, nil
 }
 
@@ -49,6 +58,11 @@ func (a *Adapter) Put(ctx context.Context, path string, contents []byte) error {
 		return a.PutError
 	}
 
+	path, err := normalize(path)
+	if err != nil {
+		return err
+	}
+
 	a.mu.Lock()
 	a.PutCalls = append(a.PutCalls, PutCall{Path: path, Content: contents})
 	a.files[path] = &fakeFile{
@@ -67,6 +81,11 @@ func (a *Adapter) PutStream(ctx context.Context, path string, stream io.Reader)
 		return a.PutStreamError
 	}
 
+	path, err := normalize(path)
+	if err != nil {
+		return err
+	}
+
 	content, err := io.ReadAll(stream)
 	if err != nil {
 		return err
@@ -84,11 +103,70 @@ func (a *Adapter) PutStream(ctx context.Context, path string, stream io.Reader)
 	return nil
 }
 
+// Write stores the stream like PutStream, with the write options. The preconditions are checked
+// against the MD5 ETag of the stored content.
+func (a *Adapter) Write(ctx context.Context, path string, body io.Reader, options entities.StorageWriteOptions) error {
+	if a.PutStreamError != nil {
+		return a.PutStreamError
+	}
+
+	path, err := normalize(path)
+	if err != nil {
+		return err
+	}
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := options.CheckPreconditions(a.etag(path)); err != nil {
+		return err
+	}
+
+	a.files[path] = newFile(content, options)
+
+	return nil
+}
+
+// etag returns the ETag of the file at path, empty when it does not exist. The caller holds the lock.
+func (a *Adapter) etag(path string) string {
+	if f, ok := a.files[path]; ok {
+		return etag(f.Content)
+	}
+
+	return ""
+}
+
+// newFile returns a file with the content type and visibility of the options, or the defaults.
+func newFile(content []byte, options entities.StorageWriteOptions) *fakeFile {
+	file := &fakeFile{
+		Content:      content,
+		MimeType:     cmp.Or(options.ContentType, "application/octet-stream"),
+		Visibility:   cmp.Or(options.Visibility, entities.VisibilityPrivate),
+		LastModified: time.Now(),
+		Options:      options,
+	}
+
+	// Preconditions only apply to the write
+	file.Options.IfNoneMatch, file.Options.IfMatch = "", ""
+
+	return file
+}
+
 func (a *Adapter) Get(ctx context.Context, path string) ([]byte, error) {
 	if a.GetError != nil {
 		return nil, a.GetError
 	}
 
+	path, err := normalize(path)
+	if err != nil {
+		return nil, err
+	}
+
 	a.mu.Lock()
 	a.GetCalls = append(a.GetCalls, path)
 	a.mu.Unlock()
@@ -109,6 +187,11 @@ func (a *Adapter) GetStream(ctx context.Context, path string) (io.ReadCloser, er
 		return nil, a.GetStreamError
 	}
 
+	path, err := normalize(path)
+	if err != nil {
+		return nil, err
+	}
+
 	a.mu.RLock()
 	f, ok := a.files[path]
 	a.mu.RUnlock()
@@ -120,11 +203,73 @@ func (a *Adapter) GetStream(ctx context.Context, path string) (io.ReadCloser, er
 	return io.NopCloser(bytes.NewReader(f.Content)), nil
 }
 
+// GetRange returns a reader for a slice of the content, see contracts.StorageRangeReader.
+func (a *Adapter) GetRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error) {
+	if a.GetRangeError != nil {
+		return nil, a.GetRangeError
+	}
+
+	path, err := normalize(path)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.RLock()
+	f, ok := a.files[path]
+	a.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrFileNotFound
+	}
+
+	length, err = entities.StorageRangeLength(offset, length, int64(len(f.Content)))
+	if err != nil {
+		return nil, fmt.Errorf("storage: get range %q: %w", path, err)
+	}
+
+	return io.NopCloser(bytes.NewReader(f.Content[offset : offset+length])), nil
+}
+
+// Open returns a seekable reader for the content, see contracts.StorageRangeReader.
+func (a *Adapter) Open(ctx context.Context, path string) (io.ReadSeekCloser, error) {
+	if a.OpenError != nil {
+		return nil, a.OpenError
+	}
+
+	path, err := normalize(path)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.RLock()
+	f, ok := a.files[path]
+	a.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrFileNotFound
+	}
+
+	return readSeekNopCloser{bytes.NewReader(f.Content)}, nil
+}
+
+type readSeekNopCloser struct {
+	*bytes.Reader
+}
+
+func (readSeekNopCloser) Close() error {
+	return nil
+}
+
 func (a *Adapter) Exists(ctx context.Context, path string) (bool, error) {
 	if a.ExistsError != nil {
 		return false, a.ExistsError
 	}
 
+	path, err := normalize(path)
+	if err != nil {
+		return false, err
+	}
+
 	a.mu.RLock()
 	_, ok := a.files[path]
 	a.mu.RUnlock()
@@ -143,6 +288,11 @@ func (a *Adapter) Size(ctx context.Context, path string) (int64, error) {
 		return 0, a.SizeError
 	}
 
+	path, err := normalize(path)
+	if err != nil {
+		return 0, err
+	}
+
 	a.mu.RLock()
 	f, ok := a.files[path]
 	a.mu.RUnlock()
@@ -159,6 +309,11 @@ func (a *Adapter) LastModified(ctx context.Context, path string) (time.Time, err
 		return time.Time{}, a.LastModifiedError
 	}
 
+	path, err := normalize(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
 	a.mu.RLock()
 	f, ok := a.files[path]
 	a.mu.RUnlock()
@@ -175,6 +330,11 @@ func (a *Adapter) MimeType(ctx context.Context, path string) (string, error) {
 		return "", a.MimeTypeError
 	}
 
+	path, err := normalize(path)
+	if err != nil {
+		return "", err
+	}
+
 	a.mu.RLock()
 	f, ok := a.files[path]
 	a.mu.RUnlock()
@@ -186,11 +346,57 @@ func (a *Adapter) MimeType(ctx context.Context, path string) (string, error) {
 	return f.MimeType, nil
 }
 
+// Stat returns the metadata of a file, the checksum is the SHA-256 checksum of its content.
+func (a *Adapter) Stat(ctx context.Context, path string) (*entities.StorageMetadata, error) {
+	if a.StatError != nil {
+		return nil, a.StatError
+	}
+
+	path, err := normalize(path)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.RLock()
+	f, ok := a.files[path]
+	a.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrFileNotFound
+	}
+
+	checksum := sha256.Sum256(f.Content)
+
+	return &entities.StorageMetadata{
+		Path:               path,
+		Size:               int64(len(f.Content)),
+		LastModified:       f.LastModified,
+		ContentType:        f.MimeType,
+		ETag:               etag(f.Content),
+		Checksum:           base64.StdEncoding.EncodeToString(checksum[:]),
+		Visibility:         f.Visibility,
+		CacheControl:       f.Options.CacheControl,
+		ContentDisposition: f.Options.ContentDisposition,
+		ContentEncoding:    f.Options.ContentEncoding,
+		Metadata:           maps.Clone(f.Options.Metadata),
+	}, nil
+}
+
 func (a *Adapter) Copy(ctx context.Context, from, to string) (*entities.StorageObject, error) {
 	if a.CopyError != nil {
 		return nil, a.CopyError
 	}
 
+	from, err := normalize(from)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err = normalize(to)
+	if err != nil {
+		return nil, err
+	}
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -207,6 +413,7 @@ func (a *Adapter) Copy(ctx context.Context, from, to string) (*entities.StorageO
 		MimeType:     f.MimeType,
 		Visibility:   f.Visibility,
 		LastModified: now,
+		Options:      f.Options,
 	}
 
 	return &entities.StorageObject{
@@ -221,6 +428,16 @@ func (a *Adapter) Move(ctx context.Context, from, to string) (*entities.StorageO
 		return nil, a.MoveError
 	}
 
+	from, err := normalize(from)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err = normalize(to)
+	if err != nil {
+		return nil, err
+	}
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -246,11 +463,22 @@ func (a *Adapter) Delete(ctx context.Context, paths ...string) error {
 		return a.DeleteError
 	}
 
+	keys := make([]string, len(paths))
+
+	for i, path := range paths {
+		key, err := normalize(path)
+		if err != nil {
+			return err
+		}
+
+		keys[i] = key
+	}
+
 	a.mu.Lock()
 
 	a.DeleteCalls = append(a.DeleteCalls, paths)
-	for _, path := range paths {
-		delete(a.files, path)
+	for _, key := range keys {
+		delete(a.files, key)
 	}
 
 	a.mu.Unlock()
@@ -2,10 +2,19 @@ package fake
 
 import (
 	"context"
+	"net/http"
 	"time"
+
+	"github.com/gonstruct/providers/entities"
 )
 
+// URL returns the URL of the file below BaseURL, empty for paths the other adapters reject.
 func (a *Adapter) URL(path string) string {
+	path, err := normalize(path)
+	if err != nil {
+		return ""
+	}
+
 	if a.BaseURL == "" {
 		return path
 	}
@@ -18,5 +27,45 @@ func (a *Adapter) TemporaryURL(ctx context.Context, path string, expiration time
 		return "", a.TemporaryURLError
 	}
 
+	path, err := normalize(path)
+	if err != nil {
+		return "", err
+	}
+
 	return a.URL(path) + "?expires=" + time.Now().Add(expiration).Format(time.RFC3339), nil
 }
+
+// TemporaryUploadURL records the upload and returns a PUT request to the URL of the file.
+func (a *Adapter) TemporaryUploadURL(ctx context.Context, input entities.StorageUploadInput) (*entities.StorageUpload, error) {
+	return a.temporaryUpload(http.MethodPut, input)
+}
+
+// TemporaryUploadForm records the upload and returns a POST request to the URL of the file.
+func (a *Adapter) TemporaryUploadForm(ctx context.Context, input entities.StorageUploadInput) (*entities.StorageUpload, error) {
+	return a.temporaryUpload(http.MethodPost, input)
+}
+
+func (a *Adapter) temporaryUpload(method string, input entities.StorageUploadInput) (*entities.StorageUpload, error) {
+	if a.UploadURLError != nil {
+		return nil, a.UploadURLError
+	}
+
+	path, err := normalize(input.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Path = path
+
+	a.mu.Lock()
+	a.UploadCalls = append(a.UploadCalls, input)
+	a.mu.Unlock()
+
+	expiresAt := time.Now().Add(input.Expiration)
+
+	return &entities.StorageUpload{
+		Method:    method,
+		URL:       a.URL(path) + "?expires=" + expiresAt.Format(time.RFC3339),
+		ExpiresAt: expiresAt,
+	}, nil
+}
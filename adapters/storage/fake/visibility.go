@@ -11,6 +11,11 @@ func (a *Adapter) GetVisibility(ctx context.Context, path string) (entities.Visi
 		return "", a.GetVisibilityError
 	}
 
+	path, err := normalize(path)
+	if err != nil {
+		return "", err
+	}
+
 	a.mu.RLock()
 	f, ok := a.files[path]
 	a.mu.RUnlock()
@@ -27,6 +32,11 @@ func (a *Adapter) SetVisibility(ctx context.Context, path string, visibility ent
 		return a.SetVisibilityError
 	}
 
+	path, err := normalize(path)
+	if err != nil {
+		return err
+	}
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
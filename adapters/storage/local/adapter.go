@@ -1,7 +1,14 @@
 package local
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
 	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/storage"
 )
 
 const (
@@ -18,9 +25,27 @@ type Adapter struct {
 	// BaseURL is the public URL prefix for generating URLs (optional)
 	BaseURL string
 
+	// Signer signs the URLs of TemporaryURL, which storage.Handler serves when it is mounted at
+	// BaseURL with the same signer (optional)
+	Signer *storage.URLSigner
+
 	// Permissions for files and directories
 	FilePermission      int
 	DirectoryPermission int
+
+	// SyncDirectories syncs the directory after a file was renamed into place, so the write
+	// survives a power loss, not only a crash of the application. Files are always synced.
+	SyncDirectories bool
+
+	// ConfineSymlinks rejects paths that resolve to a location outside of Root through symbolic
+	// links, like os.Root does. It resolves the links of every path, which costs a few system calls
+	// per operation, so it is off for roots that only the application writes to.
+	ConfineSymlinks bool
+
+	// MetadataRoot is the directory the write options of files are stored in, as JSON sidecar files
+	// mirroring the paths below Root. It defaults to Root with a ".metadata" suffix, so the sidecars
+	// are never listed or served as files.
+	MetadataRoot string
 }
 
 // NewAdapter creates a new local storage adapter with sensible defaults.
@@ -39,6 +64,13 @@ func (a *Adapter) WithBaseURL(url string) *Adapter {
 	return a
 }
 
+// WithSigner sets the signer of temporary URLs.
+func (a *Adapter) WithSigner(signer *storage.URLSigner) *Adapter {
+	a.Signer = signer
+
+	return a
+}
+
 // WithPermissions sets custom file and directory permissions.
 func (a *Adapter) WithPermissions(file, directory int) *Adapter {
 	a.FilePermission = file
@@ -47,6 +79,58 @@ func (a *Adapter) WithPermissions(file, directory int) *Adapter {
 	return a
 }
 
+// fullPath returns the location of path below Root. Paths are normalized with storage.NormalizePath,
+// so they cannot escape Root with "..", and confined to Root with ConfineSymlinks.
+func (a *Adapter) fullPath(path string) (string, error) {
+	normalized, err := storage.NormalizePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	fullPath := filepath.Join(a.Root, filepath.FromSlash(normalized))
+
+	if a.ConfineSymlinks {
+		if err := a.confine(fullPath); err != nil {
+			return "", storage.PathErr("resolve", path, err)
+		}
+	}
+
+	return fullPath, nil
+}
+
+// confine checks that the deepest existing ancestor of fullPath resolves to a location below Root.
+// The missing rest of the path cannot contain links yet.
+func (a *Adapter) confine(fullPath string) error {
+	root, err := filepath.EvalSymlinks(a.Root)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	existing := fullPath
+
+	for {
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			relative, err := filepath.Rel(root, resolved)
+			if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+				return fmt.Errorf("%w: links outside of the root", storage.ErrInvalidPath)
+			}
+
+			return nil
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		existing = filepath.Dir(existing)
+	}
+}
+
 // getVisibilityPermission returns the file permission for a visibility level.
 func (a *Adapter) getVisibilityPermission(visibility entities.Visibility) int {
 	switch visibility {
@@ -3,15 +3,21 @@ package local_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gonstruct/providers/adapters/storage/local"
 	"github.com/gonstruct/providers/entities"
 	"github.com/gonstruct/providers/entities/file"
+	"github.com/gonstruct/providers/storage"
 )
 
 func setupAdapter(t *testing.T) (*local.Adapter, string) {
@@ -123,6 +129,224 @@ func TestAdapter_PutFile(t *testing.T) {
 	}
 }
 
+func TestAdapter_Write_Options(t *testing.T) {
+	adapter, _ := setupAdapter(t)
+	ctx := context.Background()
+
+	options := entities.StorageWriteOptions{ContentType: "text/csv", Visibility: entities.VisibilityPrivate, IfNoneMatch: "*"}
+
+	if err := adapter.Write(ctx, "exports/data", bytes.NewReader([]byte("a,b")), options); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if mimeType, _ := adapter.MimeType(ctx, "exports/data"); mimeType != "text/csv" {
+		t.Errorf("MimeType() = %q, want text/csv", mimeType)
+	}
+
+	if visibility, _ := adapter.GetVisibility(ctx, "exports/data"); visibility != entities.VisibilityPrivate {
+		t.Errorf("GetVisibility() = %q, want private", visibility)
+	}
+
+	err := adapter.Write(ctx, "exports/data", bytes.NewReader([]byte("c,d")), options)
+	if !errors.Is(err, storage.ErrAlreadyExists) {
+		t.Errorf("Write() error = %v, want storage.ErrAlreadyExists", err)
+	}
+
+	err = adapter.Write(ctx, "exports/data", bytes.NewReader([]byte("c,d")), entities.StorageWriteOptions{IfMatch: `"stale"`})
+	if !errors.Is(err, storage.ErrPreconditionFailed) {
+		t.Errorf("Write() error = %v, want storage.ErrPreconditionFailed", err)
+	}
+
+	metadata, err := adapter.Stat(ctx, "exports/data")
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+
+	if err := adapter.Write(ctx, "exports/data", bytes.NewReader([]byte("c,d")), entities.StorageWriteOptions{IfMatch: metadata.ETag}); err != nil {
+		t.Errorf("Write() error = %v, want the file overwritten when the ETag matches", err)
+	}
+
+	if content, _ := adapter.Get(ctx, "exports/data"); string(content) != "c,d" {
+		t.Errorf("Get() = %q, want c,d", content)
+	}
+}
+
+// failingReader returns an error after the first read, like a client that disconnects mid-upload.
+type failingReader struct {
+	read bool
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.read {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	r.read = true
+
+	return copy(p, "partial"), nil
+}
+
+func TestAdapter_PutStream_Atomic(t *testing.T) {
+	adapter, root := setupAdapter(t)
+	adapter.SyncDirectories = true
+	ctx := context.Background()
+
+	if err := adapter.Put(ctx, "docs/report.txt", []byte("original")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	if err := adapter.PutStream(ctx, "docs/report.txt", &failingReader{}); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("PutStream() error = %v, want io.ErrUnexpectedEOF", err)
+	}
+
+	if content, _ := adapter.Get(ctx, "docs/report.txt"); string(content) != "original" {
+		t.Errorf("Get() = %q, want the original content", content)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, "docs"))
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("docs has %d entries, want the temporary file removed", len(entries))
+	}
+}
+
+func TestAdapter_RemoveStaleTempFiles(t *testing.T) {
+	adapter, root := setupAdapter(t)
+	ctx := context.Background()
+
+	if err := os.MkdirAll(filepath.Join(root, "docs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	stale := filepath.Join(root, "docs", local.TempFilePrefix+"stale.txt-1")
+	fresh := filepath.Join(root, "docs", local.TempFilePrefix+"fresh.txt-2")
+
+	for _, path := range []string{stale, fresh} {
+		if err := os.WriteFile(path, []byte("partial"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if files, _ := adapter.AllFiles(ctx, ""); len(files) != 0 {
+		t.Errorf("AllFiles() = %v, want temporary files skipped", files)
+	}
+
+	if err := adapter.RemoveStaleTempFiles(ctx, time.Hour); err != nil {
+		t.Fatalf("RemoveStaleTempFiles() error = %v", err)
+	}
+
+	if _, err := os.Stat(stale); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("stale temporary file still exists, error = %v", err)
+	}
+
+	if _, err := os.Stat(fresh); err != nil {
+		t.Errorf("fresh temporary file removed, error = %v", err)
+	}
+}
+
+func TestAdapter_PathTraversal(t *testing.T) {
+	adapter, root := setupAdapter(t)
+	ctx := context.Background()
+
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	relative, err := filepath.Rel(root, filepath.Join(outside, "secret.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := adapter.Get(ctx, filepath.ToSlash(relative)); !errors.Is(err, storage.ErrInvalidPath) {
+		t.Errorf("Get() error = %v, want storage.ErrInvalidPath", err)
+	}
+
+	if err := adapter.Put(ctx, "../escaped.txt", []byte("escaped")); !errors.Is(err, storage.ErrInvalidPath) {
+		t.Errorf("Put() error = %v, want storage.ErrInvalidPath", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(filepath.Dir(root), "escaped.txt")); !os.IsNotExist(err) {
+		t.Errorf("Put() wrote outside of the root")
+	}
+
+	// Symbolic links out of the root are followed unless ConfineSymlinks is set
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	if content, err := adapter.Get(ctx, "link/secret.txt"); err != nil || string(content) != "secret" {
+		t.Errorf("Get() = %q, %v, want the linked file", content, err)
+	}
+
+	adapter.ConfineSymlinks = true
+
+	if _, err := adapter.Get(ctx, "link/secret.txt"); !errors.Is(err, storage.ErrInvalidPath) {
+		t.Errorf("Get() error = %v, want storage.ErrInvalidPath for a link out of the root", err)
+	}
+
+	if err := adapter.Put(ctx, "link/new/file.txt", []byte("new")); !errors.Is(err, storage.ErrInvalidPath) {
+		t.Errorf("Put() error = %v, want storage.ErrInvalidPath for a link out of the root", err)
+	}
+
+	if err := adapter.Put(ctx, "inside/file.txt", []byte("inside")); err != nil {
+		t.Errorf("Put() error = %v, want paths inside the root to work", err)
+	}
+}
+
+func TestAdapter_GetRange_Open(t *testing.T) {
+	adapter, _ := setupAdapter(t)
+	ctx := context.Background()
+
+	if err := adapter.Put(ctx, "videos/intro.mp4", []byte("0123456789")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	reader, err := adapter.GetRange(ctx, "videos/intro.mp4", 3, 4)
+	if err != nil {
+		t.Fatalf("GetRange() error = %v", err)
+	}
+
+	got, _ := io.ReadAll(reader)
+	reader.Close()
+
+	if string(got) != "3456" {
+		t.Errorf("GetRange() = %q, want 3456", got)
+	}
+
+	if _, err := adapter.GetRange(ctx, "videos/intro.mp4", 11, -1); !errors.Is(err, storage.ErrInvalidRange) {
+		t.Errorf("GetRange() error = %v, want storage.ErrInvalidRange", err)
+	}
+
+	file, err := adapter.Open(ctx, "videos/intro.mp4")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer file.Close()
+
+	request := httptest.NewRequest(http.MethodGet, "/intro.mp4", nil)
+	request.Header.Set("Range", "bytes=-2")
+
+	recorder := httptest.NewRecorder()
+	http.ServeContent(recorder, request, "intro.mp4", time.Time{}, file)
+
+	if recorder.Code != http.StatusPartialContent || recorder.Body.String() != "89" {
+		t.Errorf("ServeContent() = %d %q, want 206 \"89\"", recorder.Code, recorder.Body.String())
+	}
+
+	if _, err := adapter.Open(ctx, "videos/missing.mp4"); !errors.Is(err, storage.ErrFileNotFound) {
+		t.Errorf("Open() error = %v, want storage.ErrFileNotFound", err)
+	}
+}
+
 func TestAdapter_Exists_Missing(t *testing.T) {
 	adapter, _ := setupAdapter(t)
 	ctx := context.Background()
@@ -194,6 +418,65 @@ func TestAdapter_Size(t *testing.T) {
 	}
 }
 
+func TestAdapter_Stat(t *testing.T) {
+	adapter, _ := setupAdapter(t)
+	ctx := context.Background()
+
+	options := entities.StorageWriteOptions{
+		CacheControl:    "max-age=3600",
+		ContentEncoding: "gzip",
+		Metadata:        map[string]string{"owner": "finance"},
+	}
+
+	if err := adapter.Write(ctx, "reports/data.json", bytes.NewReader([]byte("{}")), options); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	metadata, err := adapter.Stat(ctx, "reports/data.json")
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+
+	if metadata.Size != 2 || metadata.ContentType != "application/json" || metadata.ETag == "" || metadata.LastModified.IsZero() {
+		t.Errorf("Stat() = %+v, want the size, content type, ETag and modification time", metadata)
+	}
+
+	if metadata.CacheControl != "max-age=3600" || metadata.ContentEncoding != "gzip" || metadata.Metadata["owner"] != "finance" {
+		t.Errorf("Stat() = %+v, want the write options", metadata)
+	}
+
+	// Copies keep the write options, overwritten files lose them
+	if _, err := adapter.Copy(ctx, "reports/data.json", "archive/data.json"); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+
+	if err := adapter.Put(ctx, "reports/data.json", []byte("[]")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	if metadata, _ := adapter.Stat(ctx, "archive/data.json"); metadata.Metadata["owner"] != "finance" {
+		t.Errorf("Stat() of the copy = %+v, want the write options", metadata)
+	}
+
+	if metadata, _ := adapter.Stat(ctx, "reports/data.json"); metadata.CacheControl != "" || metadata.Metadata != nil {
+		t.Errorf("Stat() of the overwritten file = %+v, want no write options", metadata)
+	}
+
+	// Sidecar files are stored outside of the root
+	files, err := adapter.AllFiles(ctx, "")
+	if err != nil {
+		t.Fatalf("AllFiles() error = %v", err)
+	}
+
+	if len(files) != 2 {
+		t.Errorf("AllFiles() = %v, want the 2 files without sidecars", files)
+	}
+
+	if _, err := adapter.Stat(ctx, "reports/missing.json"); !errors.Is(err, storage.ErrFileNotFound) {
+		t.Errorf("Stat() error = %v, want storage.ErrFileNotFound", err)
+	}
+}
+
 func TestAdapter_LastModified(t *testing.T) {
 	adapter, _ := setupAdapter(t)
 	ctx := context.Background()
@@ -464,6 +747,76 @@ func TestAdapter_Directories(t *testing.T) {
 	}
 }
 
+func TestAdapter_List(t *testing.T) {
+	adapter, _ := setupAdapter(t)
+	ctx := context.Background()
+
+	files := []string{
+		"dir/a.csv",
+		"dir/b.txt",
+		"dir/sub/c.csv",
+		"dir/sub/deep/d.csv",
+		"dir/z.csv",
+	}
+	for _, f := range files {
+		if err := adapter.Put(ctx, f, []byte("content")); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	page, err := adapter.List(ctx, entities.StorageListInput{Directory: "dir"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if len(page.Entries) != 4 || !page.Entries[2].IsDir || page.Entries[2].Path != "dir/sub" || page.NextPageToken != "" {
+		t.Errorf("List() = %+v, want the files and the sub directory", page.Entries)
+	}
+
+	if entry := page.Entries[0]; entry.Size != 7 || entry.LastModified.IsZero() || entry.ETag == "" {
+		t.Errorf("List() entry = %+v, want its size, modification time and ETag", entry)
+	}
+
+	// Recursive listings are paged across nested directories
+	var listed []string
+
+	input := entities.StorageListInput{Directory: "dir", Recursive: true, Pattern: "*.csv", PageSize: 2}
+	for {
+		page, err := adapter.List(ctx, input)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+
+		for _, entry := range page.Entries {
+			listed = append(listed, entry.Path)
+		}
+
+		if page.NextPageToken == "" {
+			break
+		}
+
+		input.PageToken = page.NextPageToken
+	}
+
+	if want := []string{"dir/a.csv", "dir/sub/c.csv", "dir/sub/deep/d.csv", "dir/z.csv"}; !slices.Equal(listed, want) {
+		t.Errorf("List() = %v, want %v", listed, want)
+	}
+
+	page, err = adapter.List(ctx, entities.StorageListInput{Directory: "dir", Recursive: true, Prefix: "sub/deep"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if len(page.Entries) != 1 || page.Entries[0].Path != "dir/sub/deep/d.csv" {
+		t.Errorf("List() = %+v, want the files under the prefix", page.Entries)
+	}
+
+	page, err = adapter.List(ctx, entities.StorageListInput{Directory: "missing"})
+	if err != nil || len(page.Entries) != 0 {
+		t.Errorf("List() = %+v, %v, want an empty page for a missing directory", page, err)
+	}
+}
+
 func TestAdapter_URL(t *testing.T) {
 	adapter, _ := setupAdapter(t)
 	adapter.BaseURL = "https://example.com/storage"
@@ -479,9 +832,10 @@ func TestAdapter_URL(t *testing.T) {
 func TestAdapter_TemporaryURL(t *testing.T) {
 	adapter, _ := setupAdapter(t)
 	adapter.BaseURL = "https://example.com/storage"
+	adapter.Signer = storage.NewURLSigner([]byte("0123456789abcdef0123456789abcdef"))
 	ctx := context.Background()
 
-	path := "test/temp.txt"
+	path := "test/temp file.txt"
 	if err := adapter.Put(ctx, path, []byte("content")); err != nil {
 		t.Fatalf("Put() error = %v", err)
 	}
@@ -491,8 +845,99 @@ func TestAdapter_TemporaryURL(t *testing.T) {
 		t.Fatalf("TemporaryURL() error = %v", err)
 	}
 
-	if url == "" {
-		t.Error("TemporaryURL() returned empty string")
+	if !strings.HasPrefix(url, "https://example.com/storage/test/temp%20file.txt?") {
+		t.Errorf("TemporaryURL() = %q, want a URL below BaseURL", url)
+	}
+
+	handler := http.StripPrefix("/storage", storage.Handler(storage.WithAdapter(adapter), storage.WithSigner(adapter.Signer)))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, url, nil))
+
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "content" {
+		t.Errorf("Handler() = %d %q, want the file served", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "https://example.com/storage/test/temp%20file.txt", nil))
+
+	if recorder.Code != http.StatusForbidden {
+		t.Errorf("Handler() = %d, want 403 for the unsigned URL", recorder.Code)
+	}
+}
+
+func TestAdapter_TemporaryURL_NoSigner(t *testing.T) {
+	adapter, _ := setupAdapter(t)
+	adapter.BaseURL = "https://example.com/storage"
+	ctx := context.Background()
+
+	if err := adapter.Put(ctx, "test/temp.txt", []byte("content")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	if _, err := adapter.TemporaryURL(ctx, "test/temp.txt", time.Hour); err == nil {
+		t.Error("TemporaryURL() without Signer should return error")
+	}
+}
+
+func TestAdapter_TemporaryUploadURL(t *testing.T) {
+	adapter, root := setupAdapter(t)
+	adapter.BaseURL = "https://example.com/storage"
+	adapter.Signer = storage.NewURLSigner([]byte("0123456789abcdef0123456789abcdef"))
+	ctx := context.Background()
+
+	input := entities.StorageUploadInput{Path: "avatars/42.png", Expiration: time.Hour, ContentType: "image/png", MaxSize: 4}
+
+	upload, err := adapter.TemporaryUploadURL(ctx, input)
+	if err != nil {
+		t.Fatalf("TemporaryUploadURL() error = %v", err)
+	}
+
+	if upload.Method != http.MethodPut || upload.Header["Content-Type"] != "image/png" || !strings.HasPrefix(upload.URL, "https://example.com/storage/avatars/42.png?") {
+		t.Errorf("TemporaryUploadURL() = %+v, want a PUT request below BaseURL", upload)
+	}
+
+	handler := http.StripPrefix("/storage", storage.UploadHandler(storage.WithAdapter(adapter), storage.WithSigner(adapter.Signer)))
+
+	send := func(body string) int {
+		request := httptest.NewRequest(upload.Method, upload.URL, strings.NewReader(body))
+		for name, value := range upload.Header {
+			request.Header.Set(name, value)
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		return recorder.Code
+	}
+
+	if code := send("large"); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("PUT too large = %d, want 413", code)
+	}
+
+	if exists, _ := adapter.Exists(ctx, "avatars/42.png"); exists {
+		t.Error("Exists() = true, want the rejected upload discarded")
+	}
+
+	if files, _ := filepath.Glob(filepath.Join(root, "avatars", "*")); len(files) != 0 {
+		t.Errorf("avatars = %v, want no temporary files", files)
+	}
+
+	if code := send("icon"); code != http.StatusNoContent {
+		t.Errorf("PUT = %d, want 204", code)
+	}
+
+	if mimeType, _ := adapter.MimeType(ctx, "avatars/42.png"); mimeType != "image/png" {
+		t.Errorf("MimeType() = %q, want image/png", mimeType)
+	}
+
+	form, err := adapter.TemporaryUploadForm(ctx, input)
+	if err != nil {
+		t.Fatalf("TemporaryUploadForm() error = %v", err)
+	}
+
+	if form.Method != http.MethodPost || form.Fields["Content-Type"] != "image/png" {
+		t.Errorf("TemporaryUploadForm() = %+v, want a POST request with the content type field", form)
 	}
 }
 
@@ -0,0 +1,141 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gonstruct/providers/storage"
+)
+
+// TempFilePrefix starts the names of the temporary files writes go to before they are renamed
+// into place. Listings skip them, see RemoveStaleTempFiles for the ones a crash leaves behind.
+const TempFilePrefix = ".storage-tmp-"
+
+// writeAtomic writes body to a temporary file next to fullPath, syncs it and renames it into
+// place, so readers and crashes see the old or the new content, never a partial file. With
+// exclusive the file is linked into place instead, which fails when it exists.
+func (a *Adapter) writeAtomic(path, fullPath string, body io.Reader, perm os.FileMode, exclusive bool) (err error) {
+	dir := filepath.Dir(fullPath)
+
+	if err := os.MkdirAll(dir, os.FileMode(a.DirectoryPermission)); err != nil {
+		return storage.PathErr("create directory", filepath.Dir(path), err)
+	}
+
+	temp, err := os.CreateTemp(dir, TempFilePrefix+filepath.Base(fullPath)+"-*")
+	if err != nil {
+		return storage.PathErr("create file", path, err)
+	}
+
+	defer func() {
+		// The temporary file is gone after a successful rename, and removed after a failure
+		if err != nil {
+			temp.Close()
+			os.Remove(temp.Name())
+		}
+	}()
+
+	if _, err := io.Copy(temp, body); err != nil {
+		return storage.PathErr("write stream", path, err)
+	}
+
+	if err := temp.Chmod(perm); err != nil {
+		return storage.PathErr("set permissions", path, err)
+	}
+
+	if err := temp.Sync(); err != nil {
+		return storage.PathErr("sync file", path, err)
+	}
+
+	if err := temp.Close(); err != nil {
+		return storage.PathErr("close file", path, err)
+	}
+
+	if exclusive {
+		if err := os.Link(temp.Name(), fullPath); err != nil {
+			if errors.Is(err, fs.ErrExist) {
+				return storage.PathErr("create file", path, storage.ErrAlreadyExists)
+			}
+
+			return storage.PathErr("create file", path, err)
+		}
+
+		os.Remove(temp.Name())
+	} else if err := os.Rename(temp.Name(), fullPath); err != nil {
+		return storage.PathErr("rename file", path, err)
+	}
+
+	if a.SyncDirectories {
+		return syncDirectory(path, dir)
+	}
+
+	return nil
+}
+
+// syncDirectory makes the rename of a file in dir durable.
+func syncDirectory(path, dir string) error {
+	directory, err := os.Open(dir)
+	if err != nil {
+		return storage.PathErr("sync directory", filepath.Dir(path), err)
+	}
+	defer directory.Close()
+
+	if err := directory.Sync(); err != nil {
+		return storage.PathErr("sync directory", filepath.Dir(path), err)
+	}
+
+	return nil
+}
+
+// RemoveStaleTempFiles removes the temporary files of writes that were interrupted by a crash
+// and are older than maxAge, which should exceed the duration of the longest write. Call it
+// when the application starts, or periodically.
+func (a *Adapter) RemoveStaleTempFiles(ctx context.Context, maxAge time.Duration) error {
+	cutoff := time.Now().Add(-maxAge)
+
+	err := filepath.WalkDir(a.Root, func(fullPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if entry.IsDir() || !isTempFile(entry.Name()) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			// Renamed into place or removed since the directory was read
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if info.ModTime().Before(cutoff) {
+			if err := os.Remove(fullPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return storage.Err("remove stale temporary files", err)
+	}
+
+	return nil
+}
+
+func isTempFile(name string) bool {
+	return strings.HasPrefix(name, TempFilePrefix)
+}
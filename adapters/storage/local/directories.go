@@ -5,12 +5,16 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/gonstruct/providers/entities"
 	"github.com/gonstruct/providers/storage"
 )
 
 // Files returns a list of files in the given directory (non-recursive).
 func (a *Adapter) Files(ctx context.Context, directory string) ([]string, error) {
-	fullPath := filepath.Join(a.Root, directory)
+	fullPath, err := a.fullPath(directory)
+	if err != nil {
+		return nil, err
+	}
 
 	entries, err := os.ReadDir(fullPath)
 	if err != nil {
@@ -24,7 +28,7 @@ func (a *Adapter) Files(ctx context.Context, directory string) ([]string, error)
 	var files []string
 
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		if !entry.IsDir() && !isTempFile(entry.Name()) {
 			files = append(files, filepath.Join(directory, entry.Name()))
 		}
 	}
@@ -34,16 +38,19 @@ func (a *Adapter) Files(ctx context.Context, directory string) ([]string, error)
 
 // AllFiles returns a list of all files in the directory and subdirectories.
 func (a *Adapter) AllFiles(ctx context.Context, directory string) ([]string, error) {
-	fullPath := filepath.Join(a.Root, directory)
+	fullPath, err := a.fullPath(directory)
+	if err != nil {
+		return nil, err
+	}
 
 	var files []string
 
-	err := filepath.WalkDir(fullPath, func(path string, d os.DirEntry, err error) error {
+	err = filepath.WalkDir(fullPath, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if !d.IsDir() {
+		if !d.IsDir() && !isTempFile(d.Name()) {
 			relPath, _ := filepath.Rel(a.Root, path)
 			files = append(files, relPath)
 		}
@@ -63,7 +70,10 @@ func (a *Adapter) AllFiles(ctx context.Context, directory string) ([]string, err
 
 // Directories returns a list of directories in the given directory (non-recursive).
 func (a *Adapter) Directories(ctx context.Context, directory string) ([]string, error) {
-	fullPath := filepath.Join(a.Root, directory)
+	fullPath, err := a.fullPath(directory)
+	if err != nil {
+		return nil, err
+	}
 
 	entries, err := os.ReadDir(fullPath)
 	if err != nil {
@@ -87,11 +97,14 @@ func (a *Adapter) Directories(ctx context.Context, directory string) ([]string,
 
 // AllDirectories returns a list of all directories in the directory and subdirectories.
 func (a *Adapter) AllDirectories(ctx context.Context, directory string) ([]string, error) {
-	fullPath := filepath.Join(a.Root, directory)
+	fullPath, err := a.fullPath(directory)
+	if err != nil {
+		return nil, err
+	}
 
 	var dirs []string
 
-	err := filepath.WalkDir(fullPath, func(path string, d os.DirEntry, err error) error {
+	err = filepath.WalkDir(fullPath, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -116,7 +129,10 @@ func (a *Adapter) AllDirectories(ctx context.Context, directory string) ([]strin
 
 // MakeDirectory creates a directory.
 func (a *Adapter) MakeDirectory(ctx context.Context, path string) error {
-	fullPath := filepath.Join(a.Root, path)
+	fullPath, err := a.fullPath(path)
+	if err != nil {
+		return err
+	}
 
 	if err := os.MkdirAll(fullPath, os.FileMode(a.DirectoryPermission)); err != nil {
 		return storage.PathErr("create directory", path, err)
@@ -127,11 +143,21 @@ func (a *Adapter) MakeDirectory(ctx context.Context, path string) error {
 
 // DeleteDirectory removes a directory and all its contents.
 func (a *Adapter) DeleteDirectory(ctx context.Context, directory string) error {
-	fullPath := filepath.Join(a.Root, directory)
+	fullPath, err := a.fullPath(directory)
+	if err != nil {
+		return err
+	}
 
 	if err := os.RemoveAll(fullPath); err != nil {
 		return storage.PathErr("delete directory", directory, err)
 	}
 
+	// The sidecars of the files in the directory
+	normalized, _ := entities.NormalizeStoragePath(directory)
+
+	if err := os.RemoveAll(filepath.Join(a.metadataRoot(), filepath.FromSlash(normalized))); err != nil {
+		return storage.PathErr("delete directory metadata", directory, err)
+	}
+
 	return nil
 }
@@ -1,6 +1,7 @@
 package local
 
 import (
+	"cmp"
 	"context"
 	"io"
 	"os"
@@ -8,12 +9,16 @@ import (
 	"time"
 
 	gomime "github.com/cubewise-code/go-mime"
+	"github.com/gonstruct/providers/entities"
 	"github.com/gonstruct/providers/storage"
 )
 
 // Get retrieves the contents of a file.
 func (a *Adapter) Get(ctx context.Context, path string) ([]byte, error) {
-	fullPath := filepath.Join(a.Root, path)
+	fullPath, err := a.fullPath(path)
+	if err != nil {
+		return nil, err
+	}
 
 	content, err := os.ReadFile(fullPath)
 	if err != nil {
@@ -29,7 +34,10 @@ func (a *Adapter) Get(ctx context.Context, path string) ([]byte, error) {
 
 // GetStream returns a reader for the file contents.
 func (a *Adapter) GetStream(ctx context.Context, path string) (io.ReadCloser, error) {
-	fullPath := filepath.Join(a.Root, path)
+	fullPath, err := a.fullPath(path)
+	if err != nil {
+		return nil, err
+	}
 
 	file, err := os.Open(fullPath)
 	if err != nil {
@@ -43,11 +51,78 @@ func (a *Adapter) GetStream(ctx context.Context, path string) (io.ReadCloser, er
 	return file, nil
 }
 
+// GetRange returns a reader for length bytes of the file from offset, or for the rest of the file
+// when length is negative.
+func (a *Adapter) GetRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error) {
+	file, err := a.openFile("get range", path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+
+		return nil, storage.PathErr("get range", path, err)
+	}
+
+	length, err = entities.StorageRangeLength(offset, length, info.Size())
+	if err != nil {
+		file.Close()
+
+		return nil, storage.PathErr("get range", path, err)
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+
+		return nil, storage.PathErr("get range", path, err)
+	}
+
+	return rangeReader{io.LimitReader(file, length), file}, nil
+}
+
+// Open returns the file, which seeks without reading the skipped bytes.
+func (a *Adapter) Open(ctx context.Context, path string) (io.ReadSeekCloser, error) {
+	file, err := a.openFile("open", path)
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func (a *Adapter) openFile(op, path string) (*os.File, error) {
+	fullPath, err := a.fullPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, storage.PathErr(op, path, storage.ErrFileNotFound)
+		}
+
+		return nil, storage.PathErr(op, path, err)
+	}
+
+	return file, nil
+}
+
+type rangeReader struct {
+	io.Reader
+	io.Closer
+}
+
 // Exists checks if a file exists.
 func (a *Adapter) Exists(ctx context.Context, path string) (bool, error) {
-	fullPath := filepath.Join(a.Root, path)
+	fullPath, err := a.fullPath(path)
+	if err != nil {
+		return false, err
+	}
 
-	_, err := os.Stat(fullPath)
+	_, err = os.Stat(fullPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return false, nil
@@ -68,7 +143,10 @@ func (a *Adapter) Missing(ctx context.Context, path string) (bool, error) {
 
 // Size returns the size of a file in bytes.
 func (a *Adapter) Size(ctx context.Context, path string) (int64, error) {
-	fullPath := filepath.Join(a.Root, path)
+	fullPath, err := a.fullPath(path)
+	if err != nil {
+		return 0, err
+	}
 
 	info, err := os.Stat(fullPath)
 	if err != nil {
@@ -84,7 +162,10 @@ func (a *Adapter) Size(ctx context.Context, path string) (int64, error) {
 
 // LastModified returns the last modification time of a file.
 func (a *Adapter) LastModified(ctx context.Context, path string) (time.Time, error) {
-	fullPath := filepath.Join(a.Root, path)
+	fullPath, err := a.fullPath(path)
+	if err != nil {
+		return time.Time{}, err
+	}
 
 	info, err := os.Stat(fullPath)
 	if err != nil {
@@ -110,12 +191,19 @@ func (a *Adapter) MimeType(ctx context.Context, path string) (string, error) {
 		return "", storage.PathErr("mime type", path, storage.ErrFileNotFound)
 	}
 
-	ext := filepath.Ext(path)
+	sidecar, err := a.readSidecar(path)
+	if err != nil {
+		return "", err
+	}
+
+	return cmp.Or(sidecar.ContentType, mimeType(path)), nil
+}
 
-	mimeType := gomime.TypeByExtension(ext)
-	if mimeType == "" {
-		mimeType = "application/octet-stream"
+// mimeType returns the MIME type for the extension of path, application/octet-stream when it is unknown.
+func mimeType(path string) string {
+	if mimetype := gomime.TypeByExtension(filepath.Ext(path)); mimetype != "" {
+		return mimetype
 	}
 
-	return mimeType, nil
+	return "application/octet-stream"
 }
@@ -0,0 +1,122 @@
+package local
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/storage"
+)
+
+// List returns a page of the directory in the order filepath.WalkDir visits the entries. The page
+// token is the path of the last entry of the previous page, the walk skips the directories
+// listed before it, so every page only reads the part of the tree it returns.
+func (a *Adapter) List(ctx context.Context, input entities.StorageListInput) (*entities.StoragePage, error) {
+	directory, err := storage.NormalizePath(input.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := a.fullPath(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Directory = directory
+	page := &entities.StoragePage{}
+
+	err = filepath.WalkDir(root, func(fullPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if fullPath == root || (!entry.IsDir() && isTempFile(entry.Name())) {
+			return nil
+		}
+
+		relPath, _ := filepath.Rel(a.Root, fullPath)
+		entryPath := filepath.ToSlash(relPath)
+
+		if input.PageToken != "" && walkCompare(entryPath, input.PageToken) <= 0 {
+			// Directories before the token that do not contain it were listed completely
+			if entry.IsDir() && !strings.HasPrefix(input.PageToken, entryPath+"/") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if entry.IsDir() && input.Recursive {
+			relative := strings.TrimPrefix(entryPath, entities.DirectoryPrefix(input.Directory)) + "/"
+			if !strings.HasPrefix(relative, input.Prefix) && !strings.HasPrefix(input.Prefix, relative) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !input.Matches(entryPath) {
+			return skip(entry)
+		}
+
+		if input.PageSize > 0 && len(page.Entries) == input.PageSize {
+			page.NextPageToken = page.Entries[len(page.Entries)-1].Path
+
+			return filepath.SkipAll
+		}
+
+		listed := entities.StorageEntry{Path: entryPath, IsDir: entry.IsDir()}
+
+		if !entry.IsDir() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+
+			listed.Size = info.Size()
+			listed.LastModified = info.ModTime()
+			listed.ETag = etag(info)
+		}
+
+		page.Entries = append(page.Entries, listed)
+
+		return skip(entry)
+	})
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, storage.PathErr("list", input.Directory, err)
+	}
+
+	return page, nil
+}
+
+// skip does not descend into directories, which are only walked for recursive listings.
+func skip(entry fs.DirEntry) error {
+	if entry.IsDir() {
+		return filepath.SkipDir
+	}
+
+	return nil
+}
+
+// walkCompare compares slash separated paths in the order filepath.WalkDir visits them,
+// which sorts the names within every directory.
+func walkCompare(a, b string) int {
+	aParts, bParts := strings.Split(a, "/"), strings.Split(b, "/")
+
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		if c := strings.Compare(aParts[i], bParts[i]); c != 0 {
+			return c
+		}
+	}
+
+	return cmp.Compare(len(aParts), len(bParts))
+}
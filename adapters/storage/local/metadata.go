@@ -0,0 +1,162 @@
+package local
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/storage"
+)
+
+// Stat returns the metadata of a file. Local files have no checksum or storage class.
+func (a *Adapter) Stat(ctx context.Context, path string) (*entities.StorageMetadata, error) {
+	fullPath, err := a.fullPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, storage.PathErr("stat", path, storage.ErrFileNotFound)
+		}
+
+		return nil, storage.PathErr("stat", path, err)
+	}
+
+	sidecar, err := a.readSidecar(path)
+	if err != nil {
+		return nil, err
+	}
+
+	visibility := entities.VisibilityPrivate
+	if info.Mode().Perm()&0o004 != 0 {
+		visibility = entities.VisibilityPublic
+	}
+
+	return &entities.StorageMetadata{
+		Path:               path,
+		Size:               info.Size(),
+		LastModified:       info.ModTime(),
+		ContentType:        cmp.Or(sidecar.ContentType, mimeType(path)),
+		ETag:               etag(info),
+		Visibility:         visibility,
+		CacheControl:       sidecar.CacheControl,
+		ContentDisposition: sidecar.ContentDisposition,
+		ContentEncoding:    sidecar.ContentEncoding,
+		Metadata:           sidecar.Metadata,
+	}, nil
+}
+
+// etag identifies the content of a file by its modification time and size, without reading it.
+func etag(info fs.FileInfo) string {
+	return fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size())
+}
+
+func (a *Adapter) metadataRoot() string {
+	if a.MetadataRoot != "" {
+		return a.MetadataRoot
+	}
+
+	return filepath.Clean(a.Root) + ".metadata"
+}
+
+// sidecarPath returns the location of the sidecar of the file at path. Callers resolve path with
+// fullPath first, which rejects the paths NormalizeStoragePath cannot normalize.
+func (a *Adapter) sidecarPath(path string) string {
+	normalized, _ := entities.NormalizeStoragePath(path)
+
+	return filepath.Join(a.metadataRoot(), filepath.FromSlash(normalized)+".json")
+}
+
+// sidecar holds the write options stored with a file.
+type sidecar struct {
+	ContentType        string            `json:"contentType,omitempty"`
+	CacheControl       string            `json:"cacheControl,omitempty"`
+	ContentDisposition string            `json:"contentDisposition,omitempty"`
+	ContentEncoding    string            `json:"contentEncoding,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+}
+
+func newSidecar(options entities.StorageWriteOptions) sidecar {
+	return sidecar{
+		ContentType:        options.ContentType,
+		CacheControl:       options.CacheControl,
+		ContentDisposition: options.ContentDisposition,
+		ContentEncoding:    options.ContentEncoding,
+		Metadata:           options.Metadata,
+	}
+}
+
+func (s sidecar) isZero() bool {
+	return s.ContentType == "" && s.CacheControl == "" && s.ContentDisposition == "" &&
+		s.ContentEncoding == "" && len(s.Metadata) == 0
+}
+
+// writeSidecar stores the sidecar of the file at path, or removes it when it is empty.
+func (a *Adapter) writeSidecar(path string, sidecar sidecar) error {
+	if sidecar.isZero() {
+		return a.removeSidecar(path)
+	}
+
+	content, err := json.Marshal(sidecar)
+	if err != nil {
+		return storage.PathErr("write metadata", path, err)
+	}
+
+	sidecarPath := a.sidecarPath(path)
+
+	if err := os.MkdirAll(filepath.Dir(sidecarPath), os.FileMode(a.DirectoryPermission)); err != nil {
+		return storage.PathErr("write metadata", path, err)
+	}
+
+	if err := os.WriteFile(sidecarPath, content, os.FileMode(a.FilePermission)); err != nil {
+		return storage.PathErr("write metadata", path, err)
+	}
+
+	return nil
+}
+
+// readSidecar returns the sidecar of the file at path, an empty one for files written without write options.
+func (a *Adapter) readSidecar(path string) (sidecar, error) {
+	var sidecar sidecar
+
+	content, err := os.ReadFile(a.sidecarPath(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return sidecar, nil
+	}
+
+	if err != nil {
+		return sidecar, storage.PathErr("read metadata", path, err)
+	}
+
+	if err := json.Unmarshal(content, &sidecar); err != nil {
+		return sidecar, storage.PathErr("read metadata", path, err)
+	}
+
+	return sidecar, nil
+}
+
+func (a *Adapter) removeSidecar(path string) error {
+	if err := os.Remove(a.sidecarPath(path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return storage.PathErr("remove metadata", path, err)
+	}
+
+	return nil
+}
+
+// copySidecar gives the file at to the write options of the file at from.
+func (a *Adapter) copySidecar(from, to string) error {
+	sidecar, err := a.readSidecar(from)
+	if err != nil {
+		return err
+	}
+
+	return a.writeSidecar(to, sidecar)
+}
@@ -2,7 +2,6 @@ package local
 
 import (
 	"context"
-	"io"
 	"os"
 	"path/filepath"
 
@@ -12,8 +11,15 @@ import (
 
 // Copy copies a file from one location to another.
 func (a *Adapter) Copy(ctx context.Context, from, to string) (*entities.StorageObject, error) {
-	srcPath := filepath.Join(a.Root, from)
-	dstPath := filepath.Join(a.Root, to)
+	srcPath, err := a.fullPath(from)
+	if err != nil {
+		return nil, err
+	}
+
+	dstPath, err := a.fullPath(to)
+	if err != nil {
+		return nil, err
+	}
 
 	// Open source file
 	srcFile, err := os.Open(srcPath)
@@ -32,21 +38,13 @@ func (a *Adapter) Copy(ctx context.Context, from, to string) (*entities.StorageO
 		return nil, storage.PathErr("copy stat source", from, err)
 	}
 
-	// Create destination directory if needed
-	if err := os.MkdirAll(filepath.Dir(dstPath), os.FileMode(a.DirectoryPermission)); err != nil {
-		return nil, storage.PathErr("copy create directory", filepath.Dir(to), err)
-	}
-
-	// Create destination file
-	dstFile, err := os.OpenFile(dstPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, srcInfo.Mode())
-	if err != nil {
-		return nil, storage.PathErr("copy create destination", to, err)
+	// Copy contents
+	if err := a.writeAtomic(to, dstPath, srcFile, srcInfo.Mode().Perm(), false); err != nil {
+		return nil, err
 	}
-	defer dstFile.Close()
 
-	// Copy contents
-	if _, err := io.Copy(dstFile, srcFile); err != nil {
-		return nil, storage.Err("copy contents", err)
+	if err := a.copySidecar(from, to); err != nil {
+		return nil, err
 	}
 
 	mimeType, _ := a.MimeType(ctx, to)
@@ -60,8 +58,15 @@ func (a *Adapter) Copy(ctx context.Context, from, to string) (*entities.StorageO
 
 // Move moves a file from one location to another.
 func (a *Adapter) Move(ctx context.Context, from, to string) (*entities.StorageObject, error) {
-	srcPath := filepath.Join(a.Root, from)
-	dstPath := filepath.Join(a.Root, to)
+	srcPath, err := a.fullPath(from)
+	if err != nil {
+		return nil, err
+	}
+
+	dstPath, err := a.fullPath(to)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create destination directory if needed
 	if err := os.MkdirAll(filepath.Dir(dstPath), os.FileMode(a.DirectoryPermission)); err != nil {
@@ -80,7 +85,15 @@ func (a *Adapter) Move(ctx context.Context, from, to string) (*entities.StorageO
 			return nil, storage.PathErr("move remove source", from, err)
 		}
 
-		return obj, nil
+		return obj, a.removeSidecar(from)
+	}
+
+	if err := a.copySidecar(from, to); err != nil {
+		return nil, err
+	}
+
+	if err := a.removeSidecar(from); err != nil {
+		return nil, err
 	}
 
 	mimeType, _ := a.MimeType(ctx, to)
@@ -95,7 +108,11 @@ func (a *Adapter) Move(ctx context.Context, from, to string) (*entities.StorageO
 // Delete removes one or more files.
 func (a *Adapter) Delete(ctx context.Context, paths ...string) error {
 	for _, path := range paths {
-		fullPath := filepath.Join(a.Root, path)
+		fullPath, err := a.fullPath(path)
+		if err != nil {
+			return err
+		}
+
 		if err := os.Remove(fullPath); err != nil {
 			if os.IsNotExist(err) {
 				continue // Ignore non-existent files (idempotent delete)
@@ -103,6 +120,10 @@ func (a *Adapter) Delete(ctx context.Context, paths ...string) error {
 
 			return storage.PathErr("delete", path, err)
 		}
+
+		if err := a.removeSidecar(path); err != nil {
+			return err
+		}
 	}
 
 	return nil
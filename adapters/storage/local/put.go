@@ -1,8 +1,12 @@
 package local
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"errors"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -14,25 +18,11 @@ import (
 // PutFile stores a file with a unique ID.
 func (a *Adapter) PutFile(ctx context.Context, input entities.StorageInput) (*entities.StorageObject, error) {
 	extension := input.File.Extension()
-	mimetype := gomime.TypeByExtension(extension)
+	mimetype := cmp.Or(input.Options.ContentType, gomime.TypeByExtension(extension))
 	key := filepath.Join(input.Path, input.ID+extension)
 
-	fullPath := filepath.Join(a.Root, key)
-
-	// Create directory if it doesn't exist
-	if err := os.MkdirAll(filepath.Dir(fullPath), os.FileMode(a.DirectoryPermission)); err != nil {
-		return nil, storage.PathErr("create directory", filepath.Dir(key), err)
-	}
-
-	// Read all content from the file body
-	content, err := io.ReadAll(input.File.Body)
-	if err != nil {
-		return nil, storage.Err("read file body", err)
-	}
-
-	// Write file
-	if err := os.WriteFile(fullPath, content, os.FileMode(a.FilePermission)); err != nil {
-		return nil, storage.PathErr("write file", key, err)
+	if err := a.Write(ctx, key, input.File.Body, input.Options); err != nil {
+		return nil, err
 	}
 
 	return &entities.StorageObject{
@@ -44,38 +34,57 @@ func (a *Adapter) PutFile(ctx context.Context, input entities.StorageInput) (*en
 
 // Put stores raw bytes at the given path.
 func (a *Adapter) Put(ctx context.Context, path string, contents []byte) error {
-	fullPath := filepath.Join(a.Root, path)
-
-	// Create directory if it doesn't exist
-	if err := os.MkdirAll(filepath.Dir(fullPath), os.FileMode(a.DirectoryPermission)); err != nil {
-		return storage.PathErr("create directory", filepath.Dir(path), err)
+	fullPath, err := a.fullPath(path)
+	if err != nil {
+		return err
 	}
 
-	if err := os.WriteFile(fullPath, contents, os.FileMode(a.FilePermission)); err != nil {
-		return storage.PathErr("write file", path, err)
+	if err := a.writeAtomic(path, fullPath, bytes.NewReader(contents), os.FileMode(a.FilePermission), false); err != nil {
+		return err
 	}
 
-	return nil
+	// Like on S3, the file replaces the write options of the file it overwrites
+	return a.removeSidecar(path)
 }
 
 // PutStream stores content from a reader at the given path.
 func (a *Adapter) PutStream(ctx context.Context, path string, stream io.Reader) error {
-	fullPath := filepath.Join(a.Root, path)
+	return a.Write(ctx, path, stream, entities.StorageWriteOptions{})
+}
 
-	// Create directory if it doesn't exist
-	if err := os.MkdirAll(filepath.Dir(fullPath), os.FileMode(a.DirectoryPermission)); err != nil {
-		return storage.PathErr("create directory", filepath.Dir(path), err)
+// Write stores the stream with the write options, atomically, see writeAtomic. The content type,
+// headers and metadata are stored in a sidecar file below MetadataRoot. IfNoneMatch creates the
+// file exclusively, IfMatch is compared with the ETag of the file before it is written.
+func (a *Adapter) Write(ctx context.Context, path string, body io.Reader, options entities.StorageWriteOptions) error {
+	fullPath, err := a.fullPath(path)
+	if err != nil {
+		return err
 	}
 
-	file, err := os.OpenFile(fullPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(a.FilePermission))
-	if err != nil {
-		return storage.PathErr("create file", path, err)
+	if options.IfMatch != "" {
+		var current string
+
+		info, err := os.Stat(fullPath)
+		if err == nil {
+			current = etag(info)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return storage.PathErr("stat", path, err)
+		}
+
+		if err := options.CheckPreconditions(current); err != nil {
+			return storage.PathErr("write", path, err)
+		}
+	}
+
+	perm := os.FileMode(a.FilePermission)
+	if options.Visibility != "" {
+		perm = os.FileMode(a.getVisibilityPermission(options.Visibility))
 	}
-	defer file.Close()
 
-	if _, err := io.Copy(file, stream); err != nil {
-		return storage.PathErr("write stream", path, err)
+	if err := a.writeAtomic(path, fullPath, body, perm, options.IfNoneMatch == "*"); err != nil {
+		return err
 	}
 
-	return nil
+	// Like on S3, the file replaces the write options of the file it overwrites
+	return a.writeSidecar(path, newSidecar(options))
 }
@@ -3,27 +3,29 @@ package local
 import (
 	"context"
 	"errors"
-	"path"
+	"net/http"
+	"net/url"
 	"time"
 
+	"github.com/gonstruct/providers/entities"
 	"github.com/gonstruct/providers/storage"
 )
 
 // URL returns the public URL for a file
 // For local storage, this requires a BaseURL to be configured.
+// Paths storage.NormalizePath rejects have no URL.
 func (a *Adapter) URL(filePath string) string {
-	if a.BaseURL == "" {
+	filePath, err := storage.NormalizePath(filePath)
+	if a.BaseURL == "" || err != nil {
 		return ""
 	}
 
 	return a.BaseURL + "/" + filePath
 }
 
-// TemporaryURL generates a temporary URL for the file
-// Local storage doesn't natively support signed URLs, so this returns an error
-// You can implement signed URL support using your application's routing.
+// TemporaryURL returns a URL of the file below BaseURL that expires after expiration, signed by
+// Signer. Serve it with storage.Handler and the same signer.
 func (a *Adapter) TemporaryURL(ctx context.Context, filePath string, expiration time.Duration) (string, error) {
-	// Check if file exists first
 	exists, err := a.Exists(ctx, filePath)
 	if err != nil {
 		return "", err
@@ -33,15 +35,74 @@ func (a *Adapter) TemporaryURL(ctx context.Context, filePath string, expiration
 		return "", storage.PathErr("temporary url", filePath, storage.ErrFileNotFound)
 	}
 
-	// Local storage doesn't support temporary URLs natively
-	// Applications can implement this via their routing layer
-	if a.BaseURL == "" {
-		return "", storage.Err(
-			"temporary url",
-			errors.New("BaseURL not configured; implement signed URL logic in your application"),
-		)
+	if a.BaseURL == "" || a.Signer == nil {
+		return "", storage.PathErr("temporary url", filePath, errors.New("BaseURL and Signer are required for temporary URLs"))
 	}
 
-	// Return the regular URL - implement signing in your application
-	return path.Join(a.BaseURL, filePath), nil
+	filePath, err = storage.NormalizePath(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	query, err := a.Signer.Sign(filePath, nil, expiration)
+	if err != nil {
+		return "", err
+	}
+
+	return a.BaseURL + "/" + (&url.URL{Path: filePath}).EscapedPath() + "?" + query.Encode(), nil
+}
+
+// TemporaryUploadURL returns a PUT request that uploads a file below BaseURL until it expires,
+// signed by Signer. Serve it with storage.UploadHandler and the same signer.
+func (a *Adapter) TemporaryUploadURL(ctx context.Context, input entities.StorageUploadInput) (*entities.StorageUpload, error) {
+	upload, err := a.temporaryUpload(http.MethodPut, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.ContentType != "" {
+		upload.Header = map[string]string{"Content-Type": input.ContentType}
+	}
+
+	return upload, nil
+}
+
+// TemporaryUploadForm returns a POST request for an HTML form that uploads a file below BaseURL,
+// see TemporaryUploadURL.
+func (a *Adapter) TemporaryUploadForm(ctx context.Context, input entities.StorageUploadInput) (*entities.StorageUpload, error) {
+	upload, err := a.temporaryUpload(http.MethodPost, input)
+	if err != nil {
+		return nil, err
+	}
+
+	upload.Fields = map[string]string{}
+	if input.ContentType != "" {
+		upload.Fields["Content-Type"] = input.ContentType
+	}
+
+	return upload, nil
+}
+
+func (a *Adapter) temporaryUpload(method string, input entities.StorageUploadInput) (*entities.StorageUpload, error) {
+	if a.BaseURL == "" || a.Signer == nil {
+		return nil, storage.PathErr("temporary upload url", input.Path, errors.New("BaseURL and Signer are required for temporary upload URLs"))
+	}
+
+	path, err := storage.NormalizePath(input.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Path = path
+
+	query, err := a.Signer.SignUpload(method, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.StorageUpload{
+		Method:    method,
+		URL:       a.BaseURL + "/" + (&url.URL{Path: path}).EscapedPath() + "?" + query.Encode(),
+		ExpiresAt: time.Now().Add(input.Expiration),
+	}, nil
 }
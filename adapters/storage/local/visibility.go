@@ -3,7 +3,6 @@ package local
 import (
 	"context"
 	"os"
-	"path/filepath"
 
 	"github.com/gonstruct/providers/entities"
 	"github.com/gonstruct/providers/storage"
@@ -11,7 +10,10 @@ import (
 
 // GetVisibility returns the visibility of a file based on its permissions.
 func (a *Adapter) GetVisibility(ctx context.Context, path string) (entities.Visibility, error) {
-	fullPath := filepath.Join(a.Root, path)
+	fullPath, err := a.fullPath(path)
+	if err != nil {
+		return "", err
+	}
 
 	info, err := os.Stat(fullPath)
 	if err != nil {
@@ -33,7 +35,10 @@ func (a *Adapter) GetVisibility(ctx context.Context, path string) (entities.Visi
 
 // SetVisibility changes the visibility of a file.
 func (a *Adapter) SetVisibility(ctx context.Context, path string, visibility entities.Visibility) error {
-	fullPath := filepath.Join(a.Root, path)
+	fullPath, err := a.fullPath(path)
+	if err != nil {
+		return err
+	}
 
 	perm := a.getVisibilityPermission(visibility)
 	if err := os.Chmod(fullPath, os.FileMode(perm)); err != nil {
@@ -2,6 +2,7 @@ package contracts
 
 import (
 	"context"
+	"time"
 
 	"github.com/gonstruct/providers/entities"
 )
@@ -9,3 +10,57 @@ import (
 type Mail interface {
 	Send(context context.Context, input entities.MailInput) error
 }
+
+// ReceiptMail is implemented by mail adapters that report what the transport did with the message.
+// Adapters that only implement Mail get a receipt with the generated Message-ID and every recipient accepted.
+type ReceiptMail interface {
+	Mail
+	SendWithReceipt(context context.Context, input entities.MailInput) (*entities.MailReceipt, error)
+}
+
+// BatchMail is implemented by mail adapters that send many messages more efficiently
+// than one Send call per message, for example by reusing a connection.
+type BatchMail interface {
+	Mail
+	// SendBatch sends every input and returns one error per input, nil for the inputs that were sent.
+	SendBatch(context context.Context, inputs []entities.MailInput) []error
+}
+
+// ReceiptBatchMail is implemented by batch mail adapters that report what the transport did with
+// every message, like ReceiptMail. Batch adapters that only implement BatchMail get receipts with
+// the generated Message-ID and every recipient accepted.
+type ReceiptBatchMail interface {
+	BatchMail
+	// SendBatchWithReceipt sends every input and returns one receipt and one error per input,
+	// the receipt is nil for the inputs that were not sent.
+	SendBatchWithReceipt(context context.Context, inputs []entities.MailInput) ([]*entities.MailReceipt, []error)
+}
+
+// MailLimits is implemented by mail adapters whose transport rejects messages above a size.
+type MailLimits interface {
+	// MaxMessageSize returns the maximum size of an encoded message in bytes.
+	MaxMessageSize() int64
+}
+
+// ScheduledMail is implemented by mail adapters whose provider can deliver messages at a later time.
+type ScheduledMail interface {
+	Mail
+	// Schedule hands the message to the provider for delivery at the given time
+	// and returns the ID to cancel it with.
+	Schedule(context context.Context, input entities.MailInput, at time.Time) (string, error)
+	// CancelScheduled cancels a message scheduled with Schedule.
+	CancelScheduled(context context.Context, id string) error
+	// MaxScheduleDelay returns how far ahead messages can be scheduled, 0 when there is no limit.
+	MaxScheduleDelay() time.Duration
+}
+
+// MailScheduleStore persists the messages of the local scheduler, so they survive restarts.
+type MailScheduleStore interface {
+	// Save stores the message, replacing the message with the same ID.
+	Save(context context.Context, message entities.ScheduledMail) error
+	// Delete removes the message and reports whether it existed.
+	Delete(context context.Context, id string) (bool, error)
+	// Due returns the messages that should be sent at now: messages whose SendAt
+	// and LockedUntil have passed and that have not failed.
+	Due(context context.Context, now time.Time) ([]entities.ScheduledMail, error)
+}
@@ -7,3 +7,16 @@ type Mailable interface {
 	Content() mailables.Content
 	Attachments() mailables.AttachmentSlice
 }
+
+// LocalizedMailable is a mailable that knows the locale of its recipient.
+type LocalizedMailable interface {
+	Mailable
+	Locale() string
+}
+
+// ProcessedMailable is a mailable whose built message needs processing, like signing or encryption.
+// Its processors are applied after the processors configured on the mailer.
+type ProcessedMailable interface {
+	Mailable
+	Processors() []mailables.MessageProcessor
+}
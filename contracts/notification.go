@@ -0,0 +1,50 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/gonstruct/providers/entities"
+)
+
+// Notifiable is a recipient of notifications, like a user or a team.
+type Notifiable interface {
+	// RouteNotificationFor returns where the notifiable receives notifications on the channel,
+	// like an email address for "mail". Channels without a route are skipped.
+	RouteNotificationFor(channel string) string
+}
+
+// Notification is an event sent to notifiables on one or more channels. It implements
+// the representation for each of its channels, like MailNotification for "mail".
+type Notification interface {
+	// Via returns the channels the notification is sent on for the notifiable.
+	Via(notifiable Notifiable) []string
+}
+
+// MailNotification is a notification with a mail representation.
+// The route of the notifiable is used as recipient when the mailable has none.
+type MailNotification interface {
+	Notification
+	ToMail() Mailable
+}
+
+// WebhookNotification is a notification with a webhook representation.
+type WebhookNotification interface {
+	Notification
+	ToWebhook() entities.WebhookMessage
+}
+
+// DatabaseNotification is a notification with a database representation.
+type DatabaseNotification interface {
+	Notification
+	ToDatabase() map[string]any
+}
+
+// NamedNotification is a notification that chooses the type stored with database notifications.
+type NamedNotification interface {
+	Notification
+	NotificationType() string
+}
+
+type NotificationChannel interface {
+	Send(context context.Context, input entities.NotificationInput) error
+}
@@ -47,3 +47,56 @@ type Storage interface {
 	URL(path string) string
 	TemporaryURL(ctx context.Context, path string, expiration time.Duration) (string, error)
 }
+
+// StorageLister is implemented by storage adapters that list directories page by page,
+// with the size and modification time of every file. storage.List falls back to the
+// Files and Directories methods of adapters that do not implement it.
+type StorageLister interface {
+	List(ctx context.Context, input entities.StorageListInput) (*entities.StoragePage, error)
+}
+
+// StorageStater is implemented by storage adapters that return all metadata of a file at once.
+// storage.Stat falls back to the Size, LastModified, MimeType and GetVisibility methods of adapters
+// that do not implement it.
+type StorageStater interface {
+	Stat(ctx context.Context, path string) (*entities.StorageMetadata, error)
+}
+
+// StorageWriter is implemented by storage adapters that store files with write options.
+// storage.Put and storage.PutStream fail with storage.ErrNotSupported when write options
+// are given for adapters that do not implement it.
+type StorageWriter interface {
+	Write(ctx context.Context, path string, body io.Reader, options entities.StorageWriteOptions) error
+}
+
+// StorageRangeReader is implemented by storage adapters that read parts of a file without
+// downloading the rest of it. storage.GetRange and storage.Open fall back to GetStream and
+// Get for adapters that do not implement it.
+type StorageRangeReader interface {
+	// GetRange reads length bytes from offset, or to the end of the file when length is negative.
+	GetRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error)
+	// Open returns a seekable reader, which reads only the parts of the file that are read from it.
+	Open(ctx context.Context, path string) (io.ReadSeekCloser, error)
+}
+
+// StorageUploader is implemented by storage adapters that presign uploads, so clients upload
+// files straight to the storage instead of through the application. storage.TemporaryUploadURL
+// and storage.TemporaryUploadForm fail with storage.ErrNotSupported for adapters that do not
+// implement it.
+type StorageUploader interface {
+	// TemporaryUploadURL returns a presigned PUT request.
+	TemporaryUploadURL(ctx context.Context, input entities.StorageUploadInput) (*entities.StorageUpload, error)
+	// TemporaryUploadForm returns a presigned POST request, for HTML forms.
+	TemporaryUploadForm(ctx context.Context, input entities.StorageUploadInput) (*entities.StorageUpload, error)
+}
+
+// StorageMultipartUploader is implemented by storage adapters that assemble a file from parts
+// uploaded separately, like S3 multipart uploads. All parts but the last one are at least
+// MinimumPartSize bytes, the parts are numbered from 1.
+type StorageMultipartUploader interface {
+	MinimumPartSize() int64
+	CreateMultipartUpload(ctx context.Context, path string, options entities.StorageWriteOptions) (string, error)
+	UploadPart(ctx context.Context, path, uploadID string, number int, part []byte) (*entities.StoragePart, error)
+	CompleteMultipartUpload(ctx context.Context, path, uploadID string, parts []entities.StoragePart) error
+	AbortMultipartUpload(ctx context.Context, path, uploadID string) error
+}
@@ -2,6 +2,7 @@ package entities
 
 import (
 	"bytes"
+	"time"
 
 	"github.com/gonstruct/providers/entities/mailables"
 )
@@ -10,4 +11,59 @@ type MailInput struct {
 	Envelope    mailables.Envelope
 	Attachments mailables.AttachmentSlice
 	Html        bytes.Buffer
+	// Data is the data the content was rendered with, for adapters that render with provider templates
+	Data map[string]any
+	// Processors are applied in order to the built MIME message, adapters that cannot send
+	// raw messages return mail.ErrRawUnsupported when any are set
+	Processors []mailables.MessageProcessor
+	// Mailable is the contracts.Mailable the input was built from, used by fakes to assert on mailables
+	Mailable any
+	// IdempotencyKey identifies the message across delivery attempts, adapters whose provider
+	// deduplicates requests send it along
+	IdempotencyKey string
+	// MessageID is the Message-ID header of the message, including the angle brackets
+	MessageID string
+}
+
+// MailReceipt describes a message accepted by the transport.
+type MailReceipt struct {
+	// MessageID is the Message-ID header of the sent message, including the angle brackets.
+	// Store it to thread replies with Envelope.Threaded and to match bounces.
+	MessageID string
+	// ProviderID is the ID the provider assigned to the message, like the SES MessageId,
+	// empty for transports without one
+	ProviderID string
+	// Accepted and Rejected are the recipients the transport accepted and rejected
+	Accepted []string
+	Rejected []string
+	SentAt   time.Time
+	// ScheduleID is the ID to cancel the message with when it was scheduled with SendAt,
+	// SentAt is then the time it will be sent at
+	ScheduleID string
+}
+
+// ScheduledMail is a message persisted by the local scheduler until it is due.
+type ScheduledMail struct {
+	ID     string
+	SendAt time.Time
+	// Attempts counts the deliveries that were started, LastError is the error of the last failed one
+	Attempts  int
+	LastError string `json:",omitempty"`
+	// LockedUntil is set while the message is being delivered and after a failure, until it may be retried
+	LockedUntil time.Time
+	// FailedAt is set when the message is no longer retried
+	FailedAt *time.Time `json:",omitempty"`
+
+	MessageID   string
+	Envelope    mailables.Envelope
+	Html        string
+	Data        map[string]any        `json:",omitempty"`
+	Attachments []ScheduledAttachment `json:",omitempty"`
+}
+
+// ScheduledAttachment is an attachment of a scheduled message, read into memory so it can be persisted.
+type ScheduledAttachment struct {
+	Name    string
+	Mime    string
+	Content []byte
 }
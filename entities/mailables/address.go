@@ -1,6 +1,9 @@
 package mailables
 
-import "net/mail"
+import (
+	"net/mail"
+	"strings"
+)
 
 type (
 	address      = mail.Address
@@ -16,6 +19,16 @@ func (addresses addressSlice) String() []string {
 	return stringAddresses
 }
 
+func (addresses addressSlice) contains(needle *address) bool {
+	for _, address := range addresses {
+		if address != nil && needle != nil && strings.EqualFold(address.Address, needle.Address) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func Address(email, name string) *address {
 	return &mail.Address{
 		Address: email,
@@ -23,6 +36,9 @@ func Address(email, name string) *address {
 	}
 }
 
+// Addresses builds an address list from addresses or strings such as "user@example.com"
+// and "Jane Doe <jane@example.com>". Strings that cannot be parsed are kept as-is
+// so that Envelope.Normalize reports them.
 func Addresses[S Sender](addresses ...S) addressSlice {
 	if len(addresses) == 0 {
 		return nil
@@ -32,7 +48,12 @@ func Addresses[S Sender](addresses ...S) addressSlice {
 	for i, addr := range addresses {
 		switch email := any(addr).(type) {
 		case string:
-			slice[i] = Address("", email)
+			parsed, err := mail.ParseAddress(email)
+			if err != nil {
+				parsed = Address(strings.TrimSpace(email), "")
+			}
+
+			slice[i] = parsed
 		case *address:
 			slice[i] = email
 		}
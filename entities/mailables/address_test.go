@@ -0,0 +1,163 @@
+package mailables_test
+
+import (
+	"errors"
+	"net/mail"
+	"testing"
+
+	"github.com/gonstruct/providers/entities/mailables"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		input   string
+		name    string
+		address string
+	}{
+		{"user@example.com", "", "user@example.com"},
+		{"Jane Doe <jane@example.com>", "Jane Doe", "jane@example.com"},
+		{"  spaced@example.com  ", "", "spaced@example.com"},
+		{"user@EXAMPLE.com", "", "user@example.com"},
+		{"user@exämple.com", "", "user@xn--exmple-cua.com"},
+		{"José <josé@例え.jp>", "José", "josé@xn--r8jz45g.jp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := mailables.ParseAddress(tt.input)
+			if err != nil {
+				t.Fatalf("ParseAddress() error = %v", err)
+			}
+
+			if got.Name != tt.name || got.Address != tt.address {
+				t.Errorf("ParseAddress() = %q <%s>, want %q <%s>", got.Name, got.Address, tt.name, tt.address)
+			}
+		})
+	}
+}
+
+func TestParseAddress_Invalid(t *testing.T) {
+	inputs := []string{
+		"",
+		"not-an-address",
+		"user@",
+		"user@-invalid.com",
+		"user@exa mple.com",
+		"a@b..com",
+		"toolonglocalpart-toolonglocalpart-toolonglocalpart-toolonglocalpart@example.com",
+	}
+
+	for _, input := range inputs {
+		if _, err := mailables.ParseAddress(input); err == nil {
+			t.Errorf("ParseAddress(%q) should fail", input)
+		}
+	}
+}
+
+func TestAddresses_String(t *testing.T) {
+	addresses := mailables.Addresses("Jane Doe <jane@example.com>", "john@example.com")
+
+	if addresses[0].Name != "Jane Doe" || addresses[0].Address != "jane@example.com" {
+		t.Errorf("Addresses()[0] = %q <%s>", addresses[0].Name, addresses[0].Address)
+	}
+
+	if addresses[1].Name != "" || addresses[1].Address != "john@example.com" {
+		t.Errorf("Addresses()[1] = %q <%s>", addresses[1].Name, addresses[1].Address)
+	}
+}
+
+func TestEnvelope_Normalize(t *testing.T) {
+	envelope := mailables.Envelope{
+		From: mailables.Address("noreply@Example.com", "App"),
+		To:   mailables.Addresses("user@example.com", "USER@example.com", "other@exämple.com"),
+		Cc:   mailables.Addresses("user@example.com", "cc@example.com"),
+		Bcc:  mailables.Addresses("cc@example.com", "bcc@example.com"),
+	}
+
+	normalized, err := envelope.Normalize()
+	if err != nil {
+		t.Fatalf("Normalize() error = %v", err)
+	}
+
+	if normalized.From.Address != "noreply@example.com" {
+		t.Errorf("From = %q, want %q", normalized.From.Address, "noreply@example.com")
+	}
+
+	want := map[string][]string{
+		"To":  {"user@example.com", "other@xn--exmple-cua.com"},
+		"Cc":  {"cc@example.com"},
+		"Bcc": {"bcc@example.com"},
+	}
+
+	for field, addresses := range map[string][]string{
+		"To":  addressStrings(normalized.To),
+		"Cc":  addressStrings(normalized.Cc),
+		"Bcc": addressStrings(normalized.Bcc),
+	} {
+		if len(addresses) != len(want[field]) {
+			t.Errorf("%s = %v, want %v", field, addresses, want[field])
+
+			continue
+		}
+
+		for i := range addresses {
+			if addresses[i] != want[field][i] {
+				t.Errorf("%s[%d] = %q, want %q", field, i, addresses[i], want[field][i])
+			}
+		}
+	}
+}
+
+func TestEnvelope_Normalize_ReportsEveryInvalidAddress(t *testing.T) {
+	envelope := mailables.Envelope{
+		From: mailables.Address("", "Nameless"),
+		To:   mailables.Addresses("valid@example.com", "not-an-address"),
+		Bcc:  mailables.Addresses("bad@-domain.com"),
+	}
+
+	_, err := envelope.Normalize()
+	if err == nil {
+		t.Fatal("Normalize() should fail")
+	}
+
+	if !errors.Is(err, mailables.ErrInvalidAddress) {
+		t.Errorf("errors.Is(err, ErrInvalidAddress) = false for %v", err)
+	}
+
+	var validation *mailables.ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("error %T is not a *ValidationError", err)
+	}
+
+	if len(validation.Errors) != 3 {
+		t.Fatalf("len(Errors) = %d, want 3: %v", len(validation.Errors), err)
+	}
+
+	fields := []string{"From", "To", "Bcc"}
+	for i, field := range fields {
+		if validation.Errors[i].Field != field {
+			t.Errorf("Errors[%d].Field = %q, want %q", i, validation.Errors[i].Field, field)
+		}
+	}
+}
+
+func TestEnvelope_RequiresSMTPUTF8(t *testing.T) {
+	ascii := mailables.Envelope{To: mailables.Addresses("user@exämple.com")}
+	if ascii.RequiresSMTPUTF8() {
+		t.Error("RequiresSMTPUTF8() = true for an internationalized domain only")
+	}
+
+	utf8 := mailables.Envelope{To: mailables.Addresses("josé@example.com")}
+	if !utf8.RequiresSMTPUTF8() {
+		t.Error("RequiresSMTPUTF8() = false for a non-ASCII local part")
+	}
+}
+
+func addressStrings(addresses []*mail.Address) []string {
+	result := make([]string, len(addresses))
+	for i, address := range addresses {
+		result[i] = address.Address
+	}
+
+	return result
+}
@@ -1,11 +1,39 @@
 package mailables
 
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	gomime "github.com/cubewise-code/go-mime"
+)
+
+// Disk is the part of contracts.Storage needed to attach stored files.
+type Disk interface {
+	GetStream(ctx context.Context, path string) (io.ReadCloser, error)
+	MimeType(ctx context.Context, path string) (string, error)
+	Size(ctx context.Context, path string) (int64, error)
+}
+
 type attachment struct {
 	Name    string
 	Mime    string
 	content []byte
+
+	// Lazy sources are only opened when the message is built
+	source   string
+	open     func(ctx context.Context) (io.ReadCloser, error)
+	mimeType func(ctx context.Context) (string, error)
+	size     func(ctx context.Context) (int64, error)
 }
 
+// Content returns the in-memory content of the attachment.
+// Attachments created with WithPath, WithStorage or WithReader must be read with Open.
 func (a attachment) Content() []byte {
 	if a.content == nil {
 		return []byte{}
@@ -14,12 +42,134 @@ func (a attachment) Content() []byte {
 	return a.content
 }
 
+// Open returns a reader for the attachment content, opening lazy sources.
+func (a attachment) Open(ctx context.Context) (io.ReadCloser, error) {
+	if a.open == nil {
+		return io.NopCloser(bytes.NewReader(a.Content())), nil
+	}
+
+	reader, err := a.open(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open attachment %q: %w", a.Name, err)
+	}
+
+	return reader, nil
+}
+
+// ReadContent reads the full attachment content into memory.
+func (a attachment) ReadContent(ctx context.Context) ([]byte, error) {
+	reader, err := a.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment %q: %w", a.Name, err)
+	}
+
+	return content, nil
+}
+
+// Size returns the size of the attachment content in bytes, before encoding.
+// Files and stored files are not read, attachments created with WithReader are read to count their size.
+func (a attachment) Size(ctx context.Context) (int64, error) {
+	if a.size != nil {
+		size, err := a.size(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get size of attachment %q: %w", a.Name, err)
+		}
+
+		return size, nil
+	}
+
+	if a.open == nil {
+		return int64(len(a.content)), nil
+	}
+
+	reader, err := a.Open(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	size, err := io.Copy(io.Discard, reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read attachment %q: %w", a.Name, err)
+	}
+
+	return size, nil
+}
+
+// Resolve determines the MIME type of the attachment when it was not set explicitly,
+// asking the storage disk for stored files and using the file extension otherwise.
+// The content itself is not read.
+func (a attachment) Resolve(ctx context.Context) (attachment, error) {
+	if a.Mime != "" {
+		return a, nil
+	}
+
+	if a.mimeType != nil {
+		mimeType, err := a.mimeType(ctx)
+		if err != nil {
+			return a, fmt.Errorf("failed to resolve attachment %q: %w", a.Name, err)
+		}
+
+		a.Mime = mimeType
+	}
+
+	if a.Mime == "" {
+		a.Mime = gomime.TypeByExtension(filepath.Ext(a.Name))
+	}
+
+	if a.Mime == "" {
+		a.Mime = "application/octet-stream"
+	}
+
+	return a, nil
+}
+
+// IsCalendar reports whether the attachment is an iCalendar invite (see Calendar.Attachment).
+func (a attachment) IsCalendar() bool {
+	return strings.HasPrefix(strings.ToLower(a.Mime), "text/calendar")
+}
+
 type AttachmentSlice []attachment
 
 func Attachments(attachments ...attachment) AttachmentSlice {
 	return attachments
 }
 
+// Resolve resolves every attachment, see attachment.Resolve.
+func (attachments AttachmentSlice) Resolve(ctx context.Context) (AttachmentSlice, error) {
+	if len(attachments) == 0 {
+		return attachments, nil
+	}
+
+	resolved := make(AttachmentSlice, len(attachments))
+
+	for i, attachment := range attachments {
+		var err error
+		if resolved[i], err = attachment.Resolve(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return resolved, nil
+}
+
+// HasCalendar reports whether any of the attachments is an iCalendar invite.
+func (attachments AttachmentSlice) HasCalendar() bool {
+	for _, attachment := range attachments {
+		if attachment.IsCalendar() {
+			return true
+		}
+	}
+
+	return false
+}
+
 type attachmentOption func(*attachment)
 
 func Attachment(options ...attachmentOption) attachment {
@@ -29,6 +179,10 @@ func Attachment(options ...attachmentOption) attachment {
 		option(&attachment)
 	}
 
+	if attachment.Name == "" && attachment.source != "" {
+		attachment.Name = path.Base(filepath.ToSlash(attachment.source))
+	}
+
 	return attachment
 }
 
@@ -49,3 +203,54 @@ func WithContent(content []byte) attachmentOption {
 		a.content = content
 	}
 }
+
+// WithPath attaches a file from the local filesystem. The name defaults to the file name.
+func WithPath(path string) attachmentOption {
+	return func(a *attachment) {
+		a.source = path
+		a.open = func(context.Context) (io.ReadCloser, error) {
+			return os.Open(path)
+		}
+		a.mimeType = func(context.Context) (string, error) {
+			if _, err := os.Stat(path); err != nil {
+				return "", err
+			}
+
+			return gomime.TypeByExtension(filepath.Ext(path)), nil
+		}
+		a.size = func(context.Context) (int64, error) {
+			info, err := os.Stat(path)
+			if err != nil {
+				return 0, err
+			}
+
+			return info.Size(), nil
+		}
+	}
+}
+
+// WithStorage attaches a file stored on a storage disk, such as a contracts.Storage adapter.
+// The file is streamed from the disk when the message is built and the name defaults to the file name.
+func WithStorage(disk Disk, path string) attachmentOption {
+	return func(a *attachment) {
+		a.source = path
+		a.open = func(ctx context.Context) (io.ReadCloser, error) {
+			return disk.GetStream(ctx, path)
+		}
+		a.mimeType = func(ctx context.Context) (string, error) {
+			return disk.MimeType(ctx, path)
+		}
+		a.size = func(ctx context.Context) (int64, error) {
+			return disk.Size(ctx, path)
+		}
+	}
+}
+
+// WithReader attaches content from a reader factory, which is called every time the content is needed.
+func WithReader(open func() (io.ReadCloser, error)) attachmentOption {
+	return func(a *attachment) {
+		a.open = func(context.Context) (io.ReadCloser, error) {
+			return open()
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package mailables
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+)
+
+// CalendarMethod is the iTIP method (RFC 5546) of a calendar invite.
+type CalendarMethod string
+
+const (
+	CalendarPublish CalendarMethod = "PUBLISH"
+	CalendarRequest CalendarMethod = "REQUEST"
+	CalendarCancel  CalendarMethod = "CANCEL"
+)
+
+// AttendeeRole is the participation role of an attendee.
+type AttendeeRole string
+
+const (
+	RoleRequired       AttendeeRole = "REQ-PARTICIPANT"
+	RoleOptional       AttendeeRole = "OPT-PARTICIPANT"
+	RoleNonParticipant AttendeeRole = "NON-PARTICIPANT"
+	RoleChair          AttendeeRole = "CHAIR"
+)
+
+const (
+	calendarProductID   = "-//gonstruct//providers//EN"
+	calendarLineLength  = 75
+	calendarUTCFormat   = "20060102T150405Z"
+	calendarLocalFormat = "20060102T150405"
+)
+
+var (
+	errCalendarNoEvents    = errors.New("calendar has no events")
+	errCalendarNoUID       = errors.New("event has no UID")
+	errCalendarNoStart     = errors.New("event has no start time")
+	errCalendarEndBefore   = errors.New("event ends before it starts")
+	errCalendarNoOrganizer = errors.New("event has no organizer")
+	errCalendarNoAttendee  = errors.New("event has an attendee without address")
+)
+
+// Calendar is an iCalendar (RFC 5545) object that can be attached to a mailable as an invite.
+type Calendar struct {
+	Method CalendarMethod
+	// ProductID defaults to "-//gonstruct//providers//EN"
+	ProductID string
+	Events    []Event
+}
+
+// Event is a VEVENT. Start and End are written in the time zone of Start,
+// including a VTIMEZONE definition, unless Start is in UTC or time.Local.
+type Event struct {
+	// UID identifies the event across updates and cancellations and must be stable
+	UID string
+	// Sequence must be incremented every time an already sent event is changed or cancelled
+	Sequence    int
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	Organizer   *address
+	Attendees   []Attendee
+	Alarms      []Alarm
+	// Stamp is the DTSTAMP of the event and defaults to the current time
+	Stamp time.Time
+}
+
+type Attendee struct {
+	Address *address
+	// Role defaults to RoleRequired
+	Role AttendeeRole
+	RSVP bool
+}
+
+// Alarm is a display reminder triggered Before the start of the event.
+type Alarm struct {
+	Before      time.Duration
+	Description string
+}
+
+// Encode renders the calendar as iCalendar text with CRLF line endings and folded lines.
+func (calendar Calendar) Encode() ([]byte, error) {
+	if len(calendar.Events) == 0 {
+		return nil, errCalendarNoEvents
+	}
+
+	method := calendar.method()
+
+	productID := calendar.ProductID
+	if productID == "" {
+		productID = calendarProductID
+	}
+
+	w := &calendarWriter{}
+	w.line("BEGIN:VCALENDAR")
+	w.line("PRODID:" + productID)
+	w.line("VERSION:2.0")
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:" + string(method))
+
+	written := make(map[string]bool)
+
+	for _, event := range calendar.Events {
+		if location := event.location(); location != nil && !written[location.String()] {
+			writeTimeZone(w, location, event.Start)
+
+			written[location.String()] = true
+		}
+	}
+
+	for _, event := range calendar.Events {
+		if err := event.validate(method); err != nil {
+			return nil, fmt.Errorf("event %q: %w", event.UID, err)
+		}
+
+		event.write(w, method)
+	}
+
+	w.line("END:VCALENDAR")
+
+	return w.buf.Bytes(), nil
+}
+
+// Attachment returns the calendar as an invite attachment. Mail adapters send it as a
+// text/calendar alternative of the HTML body, which is what makes mail clients show
+// accept and decline buttons, and as an "invite.ics" file.
+func (calendar Calendar) Attachment() (attachment, error) {
+	content, err := calendar.Encode()
+	if err != nil {
+		return attachment{}, err
+	}
+
+	return Attachment(
+		WithName("invite.ics"),
+		WithMime(mime.FormatMediaType("text/calendar", map[string]string{"method": string(calendar.method())})),
+		WithContent(content),
+	), nil
+}
+
+func (calendar Calendar) method() CalendarMethod {
+	if calendar.Method == "" {
+		return CalendarRequest
+	}
+
+	return calendar.Method
+}
+
+func (event Event) validate(method CalendarMethod) error {
+	switch {
+	case event.UID == "":
+		return errCalendarNoUID
+	case event.Start.IsZero():
+		return errCalendarNoStart
+	case !event.End.IsZero() && event.End.Before(event.Start):
+		return errCalendarEndBefore
+	case method != CalendarPublish && event.Organizer == nil:
+		return errCalendarNoOrganizer
+	}
+
+	for i, attendee := range event.Attendees {
+		if attendee.Address == nil || attendee.Address.Address == "" {
+			return fmt.Errorf("%w: attendee %d", errCalendarNoAttendee, i)
+		}
+	}
+
+	return nil
+}
+
+// location returns the time zone the event is written in, or nil for UTC.
+func (event Event) location() *time.Location {
+	location := event.Start.Location()
+	if location == time.UTC || location == time.Local || location.String() == "UTC" {
+		return nil
+	}
+
+	return location
+}
+
+func (event Event) write(w *calendarWriter, method CalendarMethod) {
+	stamp := event.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + escapeText(event.UID))
+	w.line(fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+	w.line("DTSTAMP:" + stamp.UTC().Format(calendarUTCFormat))
+	w.line(event.dateTime("DTSTART", event.Start))
+
+	if !event.End.IsZero() {
+		w.line(event.dateTime("DTEND", event.End))
+	}
+
+	if event.Summary != "" {
+		w.line("SUMMARY:" + escapeText(event.Summary))
+	}
+
+	if event.Description != "" {
+		w.line("DESCRIPTION:" + escapeText(event.Description))
+	}
+
+	if event.Location != "" {
+		w.line("LOCATION:" + escapeText(event.Location))
+	}
+
+	if event.URL != "" {
+		w.line("URL:" + event.URL)
+	}
+
+	if event.Organizer != nil {
+		w.line("ORGANIZER" + commonName(event.Organizer) + ":mailto:" + event.Organizer.Address)
+	}
+
+	for _, attendee := range event.Attendees {
+		role := attendee.Role
+		if role == "" {
+			role = RoleRequired
+		}
+
+		w.line(fmt.Sprintf(
+			"ATTENDEE%s;ROLE=%s;PARTSTAT=NEEDS-ACTION;RSVP=%s:mailto:%s",
+			commonName(attendee.Address), role, strings.ToUpper(fmt.Sprint(attendee.RSVP)), attendee.Address.Address,
+		))
+	}
+
+	if method == CalendarCancel {
+		w.line("STATUS:CANCELLED")
+	} else {
+		w.line("STATUS:CONFIRMED")
+	}
+
+	for _, alarm := range event.Alarms {
+		description := alarm.Description
+		if description == "" {
+			description = event.Summary
+		}
+
+		w.line("BEGIN:VALARM")
+		w.line("ACTION:DISPLAY")
+		w.line("DESCRIPTION:" + escapeText(description))
+		w.line("TRIGGER:" + formatDuration(-alarm.Before))
+		w.line("END:VALARM")
+	}
+
+	w.line("END:VEVENT")
+}
+
+func (event Event) dateTime(property string, t time.Time) string {
+	location := event.location()
+	if location == nil {
+		return property + ":" + t.UTC().Format(calendarUTCFormat)
+	}
+
+	return property + ";TZID=" + location.String() + ":" + t.In(location).Format(calendarLocalFormat)
+}
+
+// writeTimeZone writes a VTIMEZONE with every offset transition in the year of the event.
+func writeTimeZone(w *calendarWriter, location *time.Location, around time.Time) {
+	start := time.Date(around.Year(), time.January, 1, 0, 0, 0, 0, location)
+	end := start.AddDate(1, 0, 0)
+
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:" + location.String())
+
+	_, offset := start.Zone()
+	transitions := 0
+
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		if _, nextOffset := next.Zone(); nextOffset == offset {
+			continue
+		}
+
+		at := findTransition(day, next, offset)
+		name, to := at.Zone()
+
+		component := "STANDARD"
+		if to > offset {
+			component = "DAYLIGHT"
+		}
+
+		w.line("BEGIN:" + component)
+		w.line("DTSTART:" + at.In(time.FixedZone("", offset)).Format(calendarLocalFormat))
+		w.line("TZOFFSETFROM:" + formatOffset(offset))
+		w.line("TZOFFSETTO:" + formatOffset(to))
+		w.line("TZNAME:" + name)
+		w.line("END:" + component)
+
+		offset = to
+		transitions++
+	}
+
+	if transitions == 0 {
+		name, _ := start.Zone()
+
+		w.line("BEGIN:STANDARD")
+		w.line("DTSTART:19700101T000000")
+		w.line("TZOFFSETFROM:" + formatOffset(offset))
+		w.line("TZOFFSETTO:" + formatOffset(offset))
+		w.line("TZNAME:" + name)
+		w.line("END:STANDARD")
+	}
+
+	w.line("END:VTIMEZONE")
+}
+
+// findTransition returns the first instant in (from, to] whose offset differs from offset.
+func findTransition(from, to time.Time, offset int) time.Time {
+	for to.Sub(from) > time.Second {
+		middle := from.Add(to.Sub(from) / 2)
+		if _, middleOffset := middle.Zone(); middleOffset == offset {
+			from = middle
+		} else {
+			to = middle
+		}
+	}
+
+	return to.Truncate(time.Second)
+}
+
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+func formatDuration(duration time.Duration) string {
+	sign := ""
+	if duration < 0 {
+		sign = "-"
+		duration = -duration
+	}
+
+	minutes := int(duration.Round(time.Minute) / time.Minute)
+	days, hours, minutes := minutes/(24*60), minutes%(24*60)/60, minutes%60
+
+	var b strings.Builder
+
+	b.WriteString(sign + "P")
+
+	if days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+	}
+
+	if hours > 0 || minutes > 0 || days == 0 {
+		b.WriteString("T")
+
+		if hours > 0 {
+			fmt.Fprintf(&b, "%dH", hours)
+		}
+
+		if minutes > 0 || hours == 0 {
+			fmt.Fprintf(&b, "%dM", minutes)
+		}
+	}
+
+	return b.String()
+}
+
+func commonName(address *address) string {
+	if address.Name == "" {
+		return ""
+	}
+
+	return `;CN="` + strings.ReplaceAll(address.Name, `"`, "'") + `"`
+}
+
+func escapeText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+type calendarWriter struct {
+	buf bytes.Buffer
+}
+
+// line writes a content line, folding it at 75 octets without splitting UTF-8 sequences.
+func (w *calendarWriter) line(content string) {
+	limit := calendarLineLength
+
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && content[cut]&0xC0 == 0x80 {
+			cut--
+		}
+
+		w.buf.WriteString(content[:cut])
+		w.buf.WriteString("\r\n ")
+
+		content = content[cut:]
+		limit = calendarLineLength - 1
+	}
+
+	w.buf.WriteString(content)
+	w.buf.WriteString("\r\n")
+}
@@ -0,0 +1,147 @@
+package mailables_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gonstruct/providers/entities/mailables"
+)
+
+func testEvent() mailables.Event {
+	return mailables.Event{
+		UID:         "appointment-42@example.com",
+		Summary:     "Dental check-up; bring card, please",
+		Description: "Room 3\nSecond floor",
+		Location:    "Main Street 1",
+		Start:       time.Date(2026, time.October, 20, 9, 0, 0, 0, time.UTC),
+		End:         time.Date(2026, time.October, 20, 9, 30, 0, 0, time.UTC),
+		Organizer:   mailables.Address("clinic@example.com", "The Clinic"),
+		Attendees: []mailables.Attendee{
+			{Address: mailables.Address("patient@example.com", "Pat"), RSVP: true},
+		},
+		Alarms: []mailables.Alarm{{Before: 90 * time.Minute}},
+		Stamp:  time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestCalendar_Encode(t *testing.T) {
+	encoded, err := mailables.Calendar{Events: []mailables.Event{testEvent()}}.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	text := string(encoded)
+
+	for _, line := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"METHOD:REQUEST\r\n",
+		"UID:appointment-42@example.com\r\n",
+		"DTSTAMP:20261001T120000Z\r\n",
+		"DTSTART:20261020T090000Z\r\n",
+		"DTEND:20261020T093000Z\r\n",
+		`SUMMARY:Dental check-up\; bring card\, please` + "\r\n",
+		`DESCRIPTION:Room 3\nSecond floor` + "\r\n",
+		`ORGANIZER;CN="The Clinic":mailto:clinic@example.com` + "\r\n",
+		"STATUS:CONFIRMED\r\n",
+		"TRIGGER:-PT1H30M\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(text, line) {
+			t.Errorf("Encode() is missing %q in:\n%s", line, text)
+		}
+	}
+
+	if !strings.Contains(strings.ReplaceAll(text, "\r\n ", ""), "RSVP=TRUE:mailto:patient@example.com") {
+		t.Errorf("Encode() is missing the attendee in:\n%s", text)
+	}
+
+	for _, line := range strings.Split(text, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line is not folded at 75 octets: %q", line)
+		}
+	}
+}
+
+func TestCalendar_Encode_TimeZone(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+
+	event := testEvent()
+	event.Start = time.Date(2026, time.October, 20, 9, 0, 0, 0, amsterdam)
+	event.End = event.Start.Add(time.Hour)
+
+	encoded, err := mailables.Calendar{Events: []mailables.Event{event}}.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	text := string(encoded)
+
+	for _, line := range []string{
+		"TZID:Europe/Amsterdam\r\n",
+		"BEGIN:DAYLIGHT\r\nDTSTART:20260329T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\n",
+		"BEGIN:STANDARD\r\nDTSTART:20261025T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\n",
+		"DTSTART;TZID=Europe/Amsterdam:20261020T090000\r\n",
+		"DTEND;TZID=Europe/Amsterdam:20261020T100000\r\n",
+	} {
+		if !strings.Contains(text, line) {
+			t.Errorf("Encode() is missing %q in:\n%s", line, text)
+		}
+	}
+}
+
+func TestCalendar_Cancel(t *testing.T) {
+	event := testEvent()
+	event.Sequence = 1
+
+	attachment, err := mailables.Calendar{Method: mailables.CalendarCancel, Events: []mailables.Event{event}}.Attachment()
+	if err != nil {
+		t.Fatalf("Attachment() error = %v", err)
+	}
+
+	if attachment.Mime != "text/calendar; method=CANCEL" {
+		t.Errorf("Mime = %q, want %q", attachment.Mime, "text/calendar; method=CANCEL")
+	}
+
+	if !attachment.IsCalendar() {
+		t.Error("IsCalendar() = false for a calendar attachment")
+	}
+
+	text := string(attachment.Content())
+	if !strings.Contains(text, "METHOD:CANCEL\r\n") || !strings.Contains(text, "STATUS:CANCELLED\r\n") ||
+		!strings.Contains(text, "SEQUENCE:1\r\n") {
+		t.Errorf("cancellation is missing METHOD, STATUS or SEQUENCE:\n%s", text)
+	}
+}
+
+func TestCalendar_Encode_Invalid(t *testing.T) {
+	noOrganizer := testEvent()
+	noOrganizer.Organizer = nil
+
+	endsEarly := testEvent()
+	endsEarly.End = endsEarly.Start.Add(-time.Hour)
+
+	noAttendeeAddress := testEvent()
+	noAttendeeAddress.Attendees = append(noAttendeeAddress.Attendees, mailables.Attendee{RSVP: true})
+
+	emptyAttendeeAddress := testEvent()
+	emptyAttendeeAddress.Attendees = []mailables.Attendee{{Address: mailables.Address("", "Nobody")}}
+
+	tests := map[string]mailables.Calendar{
+		"no events":              {},
+		"no uid":                 {Events: []mailables.Event{{Start: time.Now()}}},
+		"no organizer":           {Events: []mailables.Event{noOrganizer}},
+		"ends early":             {Events: []mailables.Event{endsEarly}},
+		"attendee without email": {Events: []mailables.Event{noAttendeeAddress}},
+		"attendee empty email":   {Events: []mailables.Event{emptyAttendeeAddress}},
+	}
+
+	for name, calendar := range tests {
+		if _, err := calendar.Encode(); err == nil {
+			t.Errorf("%s: Encode() should fail", name)
+		}
+	}
+}
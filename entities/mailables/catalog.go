@@ -0,0 +1,88 @@
+package mailables
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Catalog looks up translated messages for templates.
+type Catalog interface {
+	Lookup(locale, key string) (string, bool)
+}
+
+// Messages is an in-memory catalog of messages keyed by locale and then by message key.
+type Messages map[string]map[string]string
+
+func (messages Messages) Lookup(locale, key string) (string, bool) {
+	message, ok := messages[locale][key]
+
+	return message, ok
+}
+
+// LoadCatalog loads every "<locale>.json", "<locale>.yaml" and "<locale>.yml" file in dir.
+// Nested objects are flattened into dot separated keys, so {"welcome": {"title": "Hi"}}
+// is available as "welcome.title".
+func LoadCatalog(fsys fs.FS, dir string) (Messages, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read translations: %w", err)
+	}
+
+	messages := make(Messages)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		extension := path.Ext(entry.Name())
+		if extension != ".json" && extension != ".yaml" && extension != ".yml" {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read translations: %w", err)
+		}
+
+		var tree map[string]any
+		if extension == ".json" {
+			err = json.Unmarshal(data, &tree)
+		} else {
+			err = yaml.Unmarshal(data, &tree)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse translations %q: %w", entry.Name(), err)
+		}
+
+		locale := normalizeLocale(strings.TrimSuffix(entry.Name(), extension))
+		if messages[locale] == nil {
+			messages[locale] = make(map[string]string)
+		}
+
+		flatten(messages[locale], "", tree)
+	}
+
+	return messages, nil
+}
+
+func flatten(messages map[string]string, prefix string, tree map[string]any) {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch value := value.(type) {
+		case map[string]any:
+			flatten(messages, key, value)
+		default:
+			messages[key] = fmt.Sprint(value)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"embed"
 	"fmt"
+	"io/fs"
 	"path"
 	"text/template"
 )
@@ -14,7 +15,15 @@ type Content struct {
 }
 
 func (content Content) Parse(templates embed.FS) (bytes.Buffer, error) {
-	template, err := template.ParseFS(templates, path.Join("mail", content.View))
+	return content.ParseLocalized(templates, Localization{})
+}
+
+// ParseLocalized parses the most specific localized variant of the view and executes it
+// with the translation and formatting helpers of the localization.
+func (content Content) ParseLocalized(templates fs.FS, localization Localization) (bytes.Buffer, error) {
+	view := localization.View(templates, "mail", content.View)
+
+	template, err := template.New(path.Base(view)).Funcs(localization.funcs()).ParseFS(templates, path.Join("mail", view))
 	if err != nil {
 		return bytes.Buffer{}, fmt.Errorf("failed to parse email template: %w", err)
 	}
@@ -1,5 +1,11 @@
 package mailables
 
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
 type Envelope struct {
 	From    *address
 	To      addressSlice
@@ -7,34 +13,210 @@ type Envelope struct {
 	Bcc     addressSlice
 	ReplyTo *address
 	Subject string
-	// Tags     []string
-	// Metadata map[string]any
+	// Tags categorize the message at providers that support it, like Mailgun tags or SendGrid categories
+	Tags []string
+	// Metadata is attached to the message at providers that support it and returned in their webhooks
+	Metadata map[string]string
+	// Headers are added to the message as custom headers
+	Headers map[string]string
+	// InReplyTo is the Message-ID of the message this message replies to and References lists the
+	// Message-IDs of the thread, oldest first. Mail clients use them to thread replies, see Threaded.
+	InReplyTo  string
+	References []string
 }
 
-func (envelope *Envelope) Merge(override Envelope) Envelope {
-	if override.From != nil {
-		envelope.From = override.From
+// MessageID returns id enclosed in angle brackets, the form Message-ID headers use.
+func MessageID(id string) string {
+	id = strings.TrimSpace(id)
+	if id == "" || strings.HasPrefix(id, "<") {
+		return id
 	}
 
-	if len(override.To) > 0 {
-		envelope.To = append(envelope.To, override.To...)
+	return "<" + id + ">"
+}
+
+// Threaded returns the envelope as a reply to the message with the given Message-ID,
+// with the references of that message followed by its Message-ID as References.
+func (envelope Envelope) Threaded(messageID string, references ...string) Envelope {
+	envelope.InReplyTo = messageID
+	envelope.References = append(slices.Clone(references), messageID)
+
+	return envelope
+}
+
+// MergeStrategy decides how recipient lists of an override are combined with the base envelope.
+type MergeStrategy int
+
+const (
+	// MergeAppend adds the override recipients after the base recipients.
+	MergeAppend MergeStrategy = iota
+	// MergeReplace uses the override recipients instead of the base recipients when any are set.
+	MergeReplace
+)
+
+// FromPolicy decides which sender wins when both envelopes specify one.
+type FromPolicy int
+
+const (
+	// FromOverride lets the override sender replace the base sender.
+	FromOverride FromPolicy = iota
+	// FromKeepBase always keeps the base sender when it is set.
+	FromKeepBase
+	// FromAsReplyTo keeps the base sender and turns the override sender into the Reply-To address,
+	// unless the override already specifies one.
+	FromAsReplyTo
+)
+
+type mergeOptions struct {
+	To        MergeStrategy
+	Cc        MergeStrategy
+	Bcc       MergeStrategy
+	From      FromPolicy
+	AlwaysBcc addressSlice
+}
+
+type MergeOption func(*mergeOptions)
+
+// WithRecipientStrategy sets the strategy for To, Cc and Bcc at once.
+func WithRecipientStrategy(strategy MergeStrategy) MergeOption {
+	return func(o *mergeOptions) {
+		o.To = strategy
+		o.Cc = strategy
+		o.Bcc = strategy
 	}
+}
 
-	if len(override.Cc) > 0 {
-		envelope.Cc = append(envelope.Cc, override.Cc...)
+func WithToStrategy(strategy MergeStrategy) MergeOption {
+	return func(o *mergeOptions) {
+		o.To = strategy
 	}
+}
+
+func WithCcStrategy(strategy MergeStrategy) MergeOption {
+	return func(o *mergeOptions) {
+		o.Cc = strategy
+	}
+}
+
+func WithBccStrategy(strategy MergeStrategy) MergeOption {
+	return func(o *mergeOptions) {
+		o.Bcc = strategy
+	}
+}
+
+func WithFromPolicy(policy FromPolicy) MergeOption {
+	return func(o *mergeOptions) {
+		o.From = policy
+	}
+}
+
+// WithAlwaysBcc adds the given addresses (e.g. an archive mailbox) to the Bcc of every merged envelope.
+func WithAlwaysBcc(addresses ...*address) MergeOption {
+	return func(o *mergeOptions) {
+		o.AlwaysBcc = append(o.AlwaysBcc, addresses...)
+	}
+}
+
+// Merge returns a new envelope with override applied on top of envelope.
+// Neither envelope is modified, so it is safe to merge into a shared default envelope.
+func (envelope Envelope) Merge(override Envelope, options ...MergeOption) Envelope {
+	opts := mergeOptions{}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	merged := Envelope{
+		From:     envelope.From,
+		To:       mergeAddresses(envelope.To, override.To, opts.To),
+		Cc:       mergeAddresses(envelope.Cc, override.Cc, opts.Cc),
+		Bcc:      mergeAddresses(envelope.Bcc, override.Bcc, opts.Bcc),
+		ReplyTo:  envelope.ReplyTo,
+		Subject:  envelope.Subject,
+		Tags:     mergeTags(envelope.Tags, override.Tags),
+		Metadata: mergeMap(envelope.Metadata, override.Metadata),
+		Headers:  mergeMap(envelope.Headers, override.Headers),
 
-	if len(override.Bcc) > 0 {
-		envelope.Bcc = append(envelope.Bcc, override.Bcc...)
+		InReplyTo:  envelope.InReplyTo,
+		References: slices.Clone(envelope.References),
+	}
+
+	if override.InReplyTo != "" {
+		merged.InReplyTo = override.InReplyTo
+	}
+
+	if len(override.References) > 0 {
+		merged.References = slices.Clone(override.References)
 	}
 
 	if override.ReplyTo != nil {
-		envelope.ReplyTo = override.ReplyTo
+		merged.ReplyTo = override.ReplyTo
+	}
+
+	if override.From != nil {
+		switch {
+		case merged.From == nil || opts.From == FromOverride:
+			merged.From = override.From
+		case opts.From == FromAsReplyTo && override.ReplyTo == nil:
+			merged.ReplyTo = override.From
+		}
 	}
 
 	if override.Subject != "" {
-		envelope.Subject = override.Subject
+		merged.Subject = override.Subject
+	}
+
+	for _, bcc := range opts.AlwaysBcc {
+		if !merged.Bcc.contains(bcc) {
+			merged.Bcc = append(merged.Bcc, bcc)
+		}
+	}
+
+	return merged
+}
+
+func mergeAddresses(base, override addressSlice, strategy MergeStrategy) addressSlice {
+	if len(override) > 0 && strategy == MergeReplace {
+		return slices.Clone(override)
+	}
+
+	if len(base)+len(override) == 0 {
+		return nil
 	}
 
-	return *envelope
+	merged := make(addressSlice, 0, len(base)+len(override))
+	merged = append(merged, base...)
+
+	return append(merged, override...)
+}
+
+func mergeTags(base, override []string) []string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+
+	merged := slices.Clone(base)
+
+	for _, tag := range override {
+		if !slices.Contains(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+
+	return merged
+}
+
+// mergeMap returns a new map with the override values taking precedence.
+func mergeMap(base, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+
+	merged := maps.Clone(base)
+	if merged == nil {
+		merged = make(map[string]string, len(override))
+	}
+
+	maps.Copy(merged, override)
+
+	return merged
 }
@@ -0,0 +1,276 @@
+package mailables
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// Localization selects localized views and provides translation and formatting helpers to templates.
+//
+// For the locale "nl-BE" with fallback "en", the view "welcome.html" is looked up as
+// "welcome.nl-BE.html", "welcome.nl.html", "welcome.en.html" and finally "welcome.html".
+// Messages are looked up in the catalog in the same order.
+//
+// Templates can use:
+//
+//	{{ t "welcome.title" .name }}   translated message, formatted with fmt when arguments are given
+//	{{ date .when "long" }}         localized date, style is "short", "medium", "long" or "full"
+//	{{ number .amount 2 }}          localized number with a fixed number of decimals
+//	{{ currency .amount "EUR" }}    localized amount with currency symbol
+//	{{ locale }}                    the requested locale
+type Localization struct {
+	Locale    string
+	Fallbacks []string
+	Catalog   Catalog
+}
+
+// Chain returns the locales to try in order, including the base language of every locale.
+func (localization Localization) Chain() []string {
+	var chain []string
+
+	seen := make(map[string]bool)
+
+	for _, locale := range append([]string{localization.Locale}, localization.Fallbacks...) {
+		locale = normalizeLocale(locale)
+
+		for locale != "" {
+			if !seen[locale] {
+				seen[locale] = true
+				chain = append(chain, locale)
+			}
+
+			dash := strings.LastIndex(locale, "-")
+			if dash < 0 {
+				break
+			}
+
+			locale = locale[:dash]
+		}
+	}
+
+	return chain
+}
+
+// Translate returns the message for key in the first locale of the chain that has it,
+// or the key itself when no locale has it.
+func (localization Localization) Translate(key string, args ...any) string {
+	message := key
+
+	if localization.Catalog != nil {
+		for _, locale := range localization.Chain() {
+			if translated, ok := localization.Catalog.Lookup(locale, key); ok {
+				message = translated
+
+				break
+			}
+		}
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+
+	return message
+}
+
+// View returns the most specific localized variant of view that exists in templates.
+func (localization Localization) View(templates fs.FS, directory, view string) string {
+	extension := path.Ext(view)
+	name := strings.TrimSuffix(view, extension)
+
+	for _, locale := range localization.Chain() {
+		candidate := name + "." + locale + extension
+		if _, err := fs.Stat(templates, path.Join(directory, candidate)); err == nil {
+			return candidate
+		}
+	}
+
+	return view
+}
+
+func (localization Localization) funcs() template.FuncMap {
+	tag := language.Make(localization.Locale)
+	printer := message.NewPrinter(tag)
+
+	return template.FuncMap{
+		"t":      localization.Translate,
+		"locale": func() string { return localization.Locale },
+		"date": func(value time.Time, style string) string {
+			return formatDate(localization.Chain(), value, style)
+		},
+		"number": func(value any, decimals int) (string, error) {
+			amount, err := toFloat(value)
+			if err != nil {
+				return "", err
+			}
+
+			return printer.Sprint(number.Decimal(amount, number.Scale(decimals))), nil
+		},
+		"currency": func(value any, code string) (string, error) {
+			amount, err := toFloat(value)
+			if err != nil {
+				return "", err
+			}
+
+			unit, err := currency.ParseISO(code)
+			if err != nil {
+				return "", err
+			}
+
+			return printer.Sprint(currency.Symbol(unit.Amount(amount))), nil
+		},
+	}
+}
+
+// normalizeLocale turns "nl_be" into "nl-BE".
+func normalizeLocale(locale string) string {
+	locale = strings.TrimSpace(strings.ReplaceAll(locale, "_", "-"))
+	if locale == "" {
+		return ""
+	}
+
+	parts := strings.Split(locale, "-")
+	parts[0] = strings.ToLower(parts[0])
+
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i])
+		}
+	}
+
+	return strings.Join(parts, "-")
+}
+
+func toFloat(value any) (float64, error) {
+	switch value := value.(type) {
+	case int:
+		return float64(value), nil
+	case int8:
+		return float64(value), nil
+	case int16:
+		return float64(value), nil
+	case int32:
+		return float64(value), nil
+	case int64:
+		return float64(value), nil
+	case uint:
+		return float64(value), nil
+	case uint8:
+		return float64(value), nil
+	case uint16:
+		return float64(value), nil
+	case uint32:
+		return float64(value), nil
+	case uint64:
+		return float64(value), nil
+	case float32:
+		return float64(value), nil
+	case float64:
+		return value, nil
+	}
+
+	return 0, fmt.Errorf("cannot format %T as a number", value)
+}
+
+type dateNames struct {
+	months      [12]string
+	shortMonths [12]string
+	weekdays    [7]string
+	// Layouts use {d}, {dd}, {mm}, {yyyy}, {month}, {mon} and {weekday}
+	layouts map[string]string
+}
+
+var dateLocales = map[string]dateNames{
+	"en": {
+		months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		weekdays:    [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		layouts: map[string]string{
+			"short":  "{mm}/{dd}/{yyyy}",
+			"medium": "{mon} {d}, {yyyy}",
+			"long":   "{month} {d}, {yyyy}",
+			"full":   "{weekday}, {month} {d}, {yyyy}",
+		},
+	},
+	"nl": {
+		months:      [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		shortMonths: [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		weekdays:    [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		layouts: map[string]string{
+			"short":  "{dd}-{mm}-{yyyy}",
+			"medium": "{d} {mon} {yyyy}",
+			"long":   "{d} {month} {yyyy}",
+			"full":   "{weekday} {d} {month} {yyyy}",
+		},
+	},
+	"de": {
+		months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths: [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		weekdays:    [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		layouts: map[string]string{
+			"short":  "{dd}.{mm}.{yyyy}",
+			"medium": "{d}. {mon} {yyyy}",
+			"long":   "{d}. {month} {yyyy}",
+			"full":   "{weekday}, {d}. {month} {yyyy}",
+		},
+	},
+	"fr": {
+		months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		weekdays:    [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		layouts: map[string]string{
+			"short":  "{dd}/{mm}/{yyyy}",
+			"medium": "{d} {mon} {yyyy}",
+			"long":   "{d} {month} {yyyy}",
+			"full":   "{weekday} {d} {month} {yyyy}",
+		},
+	},
+	"es": {
+		months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		weekdays:    [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		layouts: map[string]string{
+			"short":  "{dd}/{mm}/{yyyy}",
+			"medium": "{d} {mon} {yyyy}",
+			"long":   "{d} de {month} de {yyyy}",
+			"full":   "{weekday}, {d} de {month} de {yyyy}",
+		},
+	},
+}
+
+// formatDate formats value in the first locale of the chain with built-in date names, defaulting to English.
+func formatDate(chain []string, value time.Time, style string) string {
+	names := dateLocales["en"]
+
+	for _, locale := range chain {
+		if localized, ok := dateLocales[locale]; ok {
+			names = localized
+
+			break
+		}
+	}
+
+	layout, ok := names.layouts[style]
+	if !ok {
+		layout = names.layouts["medium"]
+	}
+
+	return strings.NewReplacer(
+		"{dd}", fmt.Sprintf("%02d", value.Day()),
+		"{d}", fmt.Sprint(value.Day()),
+		"{mm}", fmt.Sprintf("%02d", int(value.Month())),
+		"{yyyy}", fmt.Sprint(value.Year()),
+		"{month}", names.months[value.Month()-1],
+		"{mon}", names.shortMonths[value.Month()-1],
+		"{weekday}", names.weekdays[value.Weekday()],
+	).Replace(layout)
+}
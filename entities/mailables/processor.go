@@ -0,0 +1,9 @@
+package mailables
+
+import "context"
+
+// MessageProcessor transforms the built MIME message before it is handed to the transport,
+// for example to sign or encrypt it. The message uses CRLF line endings and includes its headers.
+type MessageProcessor interface {
+	Process(ctx context.Context, envelope Envelope, message []byte) ([]byte, error)
+}
@@ -0,0 +1,238 @@
+package mailables
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+const (
+	maxLocalPartLength = 64
+	maxAddressLength   = 254
+)
+
+// ErrInvalidAddress is matched by every address validation error.
+var ErrInvalidAddress = errors.New("invalid email address")
+
+var (
+	errEmptyAddress     = errors.New("address is empty")
+	errLocalPartTooLong = errors.New("local part exceeds 64 octets")
+	errAddressTooLong   = errors.New("address exceeds 254 octets")
+)
+
+// AddressError describes a single envelope address that failed validation.
+type AddressError struct {
+	Field  string
+	Input  string
+	Reason error
+}
+
+func (e AddressError) Error() string {
+	return fmt.Sprintf("%s %q: %v", e.Field, e.Input, e.Reason)
+}
+
+func (e AddressError) Unwrap() []error {
+	return []error{ErrInvalidAddress, e.Reason}
+}
+
+// ValidationError lists every address of an envelope that failed validation.
+type ValidationError struct {
+	Errors []AddressError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%d invalid email address(es): %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// ParseAddress parses and normalizes a single RFC 5322 address such as
+// "user@example.com" or "Jane Doe <jane@example.com>".
+// Internationalized domains are converted to their ASCII (punycode) form.
+func ParseAddress(input string) (*address, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, errEmptyAddress
+	}
+
+	parsed, err := mail.ParseAddress(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return normalizeAddress(parsed)
+}
+
+// RequiresSMTPUTF8 reports whether any address has a non-ASCII local part,
+// which can only be delivered by servers supporting the SMTPUTF8 extension.
+func (envelope Envelope) RequiresSMTPUTF8() bool {
+	for _, address := range envelope.all() {
+		if address == nil {
+			continue
+		}
+
+		if at := strings.LastIndex(address.Address, "@"); at > 0 && !isASCII(address.Address[:at]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Normalize validates every address of the envelope and returns a copy with
+// normalized addresses and duplicate recipients removed across To, Cc and Bcc.
+// When any address is invalid a *ValidationError listing all of them is returned.
+func (envelope Envelope) Normalize() (Envelope, error) {
+	validation := &ValidationError{}
+	seen := make(map[string]bool)
+
+	normalized := Envelope{
+		From:     validation.single("From", envelope.From),
+		To:       validation.list("To", envelope.To, seen),
+		Cc:       validation.list("Cc", envelope.Cc, seen),
+		Bcc:      validation.list("Bcc", envelope.Bcc, seen),
+		ReplyTo:  validation.single("ReplyTo", envelope.ReplyTo),
+		Subject:  envelope.Subject,
+		Tags:     envelope.Tags,
+		Metadata: envelope.Metadata,
+		Headers:  envelope.Headers,
+
+		InReplyTo: MessageID(envelope.InReplyTo),
+	}
+
+	for _, reference := range envelope.References {
+		if reference = MessageID(reference); reference != "" {
+			normalized.References = append(normalized.References, reference)
+		}
+	}
+
+	if len(validation.Errors) > 0 {
+		return envelope, validation
+	}
+
+	return normalized, nil
+}
+
+func (envelope Envelope) all() addressSlice {
+	addresses := addressSlice{envelope.From, envelope.ReplyTo}
+	addresses = append(addresses, envelope.To...)
+	addresses = append(addresses, envelope.Cc...)
+
+	return append(addresses, envelope.Bcc...)
+}
+
+func (e *ValidationError) single(field string, input *address) *address {
+	if input == nil {
+		return nil
+	}
+
+	normalized, err := validateAddress(input)
+	if err != nil {
+		e.Errors = append(e.Errors, AddressError{Field: field, Input: input.Address, Reason: err})
+	}
+
+	return normalized
+}
+
+func (e *ValidationError) list(field string, inputs addressSlice, seen map[string]bool) addressSlice {
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	addresses := make(addressSlice, 0, len(inputs))
+
+	for _, input := range inputs {
+		if input == nil {
+			e.Errors = append(e.Errors, AddressError{Field: field, Reason: errEmptyAddress})
+
+			continue
+		}
+
+		normalized, err := validateAddress(input)
+		if err != nil {
+			e.Errors = append(e.Errors, AddressError{Field: field, Input: input.Address, Reason: err})
+
+			continue
+		}
+
+		key := strings.ToLower(normalized.Address)
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		addresses = append(addresses, normalized)
+	}
+
+	return addresses
+}
+
+func validateAddress(input *address) (*address, error) {
+	if strings.TrimSpace(input.Address) == "" {
+		return nil, errEmptyAddress
+	}
+
+	// Round-trip through the RFC 5322 encoder so quoted local parts are validated as written.
+	parsed, err := mail.ParseAddress((&mail.Address{Address: input.Address}).String())
+	if err != nil {
+		return nil, err
+	}
+
+	parsed.Name = input.Name
+
+	return normalizeAddress(parsed)
+}
+
+func normalizeAddress(parsed *address) (*address, error) {
+	at := strings.LastIndex(parsed.Address, "@")
+	local, domain := parsed.Address[:at], parsed.Address[at+1:]
+
+	if len(local) > maxLocalPartLength {
+		return nil, errLocalPartTooLong
+	}
+
+	if !strings.HasPrefix(domain, "[") {
+		ascii, err := idna.Lookup.ToASCII(domain)
+		if err != nil {
+			return nil, err
+		}
+
+		domain = ascii
+	}
+
+	normalized := local + "@" + domain
+	if len(normalized) > maxAddressLength {
+		return nil, errAddressTooLong
+	}
+
+	return &mail.Address{
+		Name:    strings.TrimSpace(parsed.Name),
+		Address: normalized,
+	}, nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,34 @@
+package entities
+
+import "time"
+
+type NotificationInput struct {
+	// Channel is the name of the channel the notification is sent on, like "mail" or "webhook"
+	Channel string
+	// Route is where the notifiable receives notifications on the channel, like an email address or URL
+	Route string
+	// Notifiable and Notification are the contracts.Notifiable and contracts.Notification being sent
+	Notifiable   any
+	Notification any
+}
+
+// WebhookMessage is the webhook representation of a notification.
+type WebhookMessage struct {
+	// URL overrides the route of the notifiable when set
+	URL string
+	// Payload is sent as the JSON request body, like {"text": "..."} for a Slack webhook
+	Payload any
+	Headers map[string]string
+}
+
+// DatabaseNotification is a notification stored for display in the application.
+type DatabaseNotification struct {
+	ID string
+	// Type identifies the notification, the Go type name unless the notification names itself
+	Type string
+	// Notifiable is the route of the notifiable on the database channel, usually its ID
+	Notifiable string
+	Data       map[string]any
+	CreatedAt  time.Time
+	ReadAt     *time.Time
+}
@@ -1,7 +1,12 @@
 package entities
 
 import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gonstruct/providers/entities/file"
 )
@@ -15,9 +20,10 @@ const (
 )
 
 type StorageInput struct {
-	ID   string
-	File file.File
-	Path string
+	ID      string
+	File    file.File
+	Path    string
+	Options StorageWriteOptions
 }
 
 func (i StorageInput) Name() string {
@@ -30,3 +36,255 @@ type StorageObject struct {
 	Path     string
 	MimeType string
 }
+
+// Storage errors, re-exported by the storage package.
+var (
+	// ErrStorageFileNotFound is returned when a file does not exist
+	ErrStorageFileNotFound = errors.New("file not found")
+	// ErrStorageInvalidPath is returned for paths that are absolute, escape the root or contain NUL bytes or backslashes
+	ErrStorageInvalidPath = errors.New("invalid path")
+
+	// ErrStorageAlreadyExists is returned when a file exists and IfNoneMatch is "*"
+	ErrStorageAlreadyExists = errors.New("file already exists")
+	// ErrStoragePreconditionFailed is returned when the ETag of a file does not match IfMatch
+	ErrStoragePreconditionFailed = errors.New("precondition failed")
+	// ErrStorageInvalidRange is returned for range reads that start beyond the end of the file
+	ErrStorageInvalidRange = errors.New("invalid range")
+	// ErrStorageUploadRejected is returned for uploads that do not meet the constraints of their StorageUploadInput
+	ErrStorageUploadRejected = errors.New("upload rejected")
+)
+
+// StorageRangeLength returns the number of bytes a range read of length bytes from offset returns
+// for a file of size bytes. A negative length reads to the end of the file.
+func StorageRangeLength(offset, length, size int64) (int64, error) {
+	if offset < 0 || offset > size {
+		return 0, ErrStorageInvalidRange
+	}
+
+	if remaining := size - offset; length < 0 || length > remaining {
+		return remaining, nil
+	}
+
+	return length, nil
+}
+
+// StorageWriteOptions are the attributes a file is stored with besides its content, and the
+// preconditions the write depends on.
+type StorageWriteOptions struct {
+	// ContentType replaces the MIME type inferred from the extension
+	ContentType string
+	// Visibility is the visibility the file is created with, the adapter default when empty
+	Visibility         Visibility
+	CacheControl       string
+	ContentDisposition string
+	ContentEncoding    string
+	// Metadata is user defined metadata, stored as x-amz-meta-* headers on S3
+	Metadata map[string]string
+
+	// IfNoneMatch "*" only writes the file when it does not exist yet
+	IfNoneMatch string
+	// IfMatch only overwrites the file when its ETag, see StorageMetadata, is this one
+	IfMatch string
+}
+
+// IsZero reports whether no options are set.
+func (options StorageWriteOptions) IsZero() bool {
+	return options.ContentType == "" && options.Visibility == "" && options.CacheControl == "" &&
+		options.ContentDisposition == "" && options.ContentEncoding == "" && len(options.Metadata) == 0 &&
+		options.IfNoneMatch == "" && options.IfMatch == ""
+}
+
+// CheckPreconditions returns the error of a write with the options to a file with the given ETag,
+// which is empty when the file does not exist. Adapters without conditional writes check the
+// preconditions before writing.
+func (options StorageWriteOptions) CheckPreconditions(etag string) error {
+	if options.IfNoneMatch == "*" && etag != "" {
+		return ErrStorageAlreadyExists
+	}
+
+	if options.IfMatch != "" && options.IfMatch != etag {
+		return ErrStoragePreconditionFailed
+	}
+
+	return nil
+}
+
+// StorageUploadInput describes a file that clients upload straight to the storage with a presigned
+// request, and the constraints the upload must meet.
+type StorageUploadInput struct {
+	Path string
+	// Expiration is how long the presigned request is valid
+	Expiration time.Duration
+	// ContentType is the content type the upload must have, any when empty
+	ContentType string
+	// ContentLength is the exact size the upload must have, any when zero
+	ContentLength int64
+	// MaxSize is the maximum size of the upload, unlimited when zero
+	MaxSize int64
+	// Visibility is the visibility the file is created with, the adapter default when empty
+	Visibility Visibility
+}
+
+// CheckContentType returns ErrStorageUploadRejected when an upload with contentType does not meet the constraints.
+func (input StorageUploadInput) CheckContentType(contentType string) error {
+	if input.ContentType != "" && contentType != input.ContentType {
+		return fmt.Errorf("%w: content type %q, want %q", ErrStorageUploadRejected, contentType, input.ContentType)
+	}
+
+	return nil
+}
+
+// CheckSize returns ErrStorageUploadRejected when an upload of size bytes does not meet the constraints.
+func (input StorageUploadInput) CheckSize(size int64) error {
+	if input.ContentLength > 0 && size != input.ContentLength {
+		return fmt.Errorf("%w: size %d, want %d", ErrStorageUploadRejected, size, input.ContentLength)
+	}
+
+	if input.MaxSize > 0 && size > input.MaxSize {
+		return fmt.Errorf("%w: size %d exceeds %d", ErrStorageUploadRejected, size, input.MaxSize)
+	}
+
+	return nil
+}
+
+// StorageUpload is a presigned request that uploads a file. PUT requests send the file as body
+// with the Header, POST requests send a multipart/form-data body with the Fields followed by
+// the file in a field named "file", like HTML forms do.
+type StorageUpload struct {
+	Method    string
+	URL       string
+	Header    map[string]string
+	Fields    map[string]string
+	ExpiresAt time.Time
+}
+
+// StoragePart is an uploaded part of a multipart upload.
+type StoragePart struct {
+	Number   int
+	ETag     string
+	Checksum string
+	Size     int64
+}
+
+// StorageMetadata describes a stored file.
+type StorageMetadata struct {
+	Path         string
+	Size         int64
+	LastModified time.Time
+	ContentType  string
+	ETag         string
+	// Checksum is the base64 encoded SHA-256 checksum of the content, empty when the adapter does not store it.
+	// S3 returns the checksum of the part checksums for multipart uploads, suffixed with the number of parts.
+	Checksum   string
+	Visibility Visibility
+	// StorageClass is the S3 storage class, empty for adapters without storage classes
+	StorageClass string
+
+	CacheControl       string
+	ContentDisposition string
+	ContentEncoding    string
+	Metadata           map[string]string
+}
+
+// StorageEntry is a file or directory of a listing.
+type StorageEntry struct {
+	Path  string
+	IsDir bool
+	// Size, LastModified and ETag are empty for directories and for adapters that do not report them
+	Size         int64
+	LastModified time.Time
+	ETag         string
+}
+
+// StorageListInput describes a page of a directory listing.
+type StorageListInput struct {
+	Directory string
+	// Recursive lists the files of all subdirectories instead of the files and directories
+	// directly in Directory. Directories are not listed, as object stores have none.
+	Recursive bool
+	// Prefix only lists entries whose path relative to Directory starts with it
+	Prefix string
+	// Pattern only lists entries whose name matches it, see path.Match
+	Pattern string
+	// PageSize is the maximum number of entries of the page
+	PageSize int
+	// PageToken continues the listing after the page that returned it
+	PageToken string
+}
+
+// Matches reports whether the entry at entryPath, inside Directory, passes the Prefix and Pattern filters.
+func (input StorageListInput) Matches(entryPath string) bool {
+	relative := strings.TrimPrefix(entryPath, DirectoryPrefix(input.Directory))
+	if !strings.HasPrefix(relative, input.Prefix) {
+		return false
+	}
+
+	if input.Pattern == "" {
+		return true
+	}
+
+	matched, _ := path.Match(input.Pattern, path.Base(entryPath))
+
+	return matched
+}
+
+// Paginate filters and sorts the entries of a complete listing and returns the page of the input,
+// for adapters that cannot list page by page. The page token is the path of the last entry of the previous page.
+func (input StorageListInput) Paginate(entries []StorageEntry) *StoragePage {
+	entries = slices.DeleteFunc(slices.Clone(entries), func(entry StorageEntry) bool {
+		return !input.Matches(entry.Path) || (input.PageToken != "" && entry.Path <= input.PageToken)
+	})
+
+	slices.SortFunc(entries, func(a, b StorageEntry) int { return strings.Compare(a.Path, b.Path) })
+
+	page := &StoragePage{Entries: entries}
+
+	if input.PageSize > 0 && len(entries) > input.PageSize {
+		page.Entries = entries[:input.PageSize]
+		page.NextPageToken = page.Entries[input.PageSize-1].Path
+	}
+
+	return page
+}
+
+// StoragePage is a page of a directory listing.
+type StoragePage struct {
+	Entries []StorageEntry
+	// NextPageToken continues the listing after this page, empty on the last page
+	NextPageToken string
+}
+
+// NormalizeStoragePath returns the clean, slash separated form of a path relative to the root of a
+// disk, like "reports/q1.csv" for "./reports//q1.csv", and "" for the root itself. It rejects
+// absolute paths, paths that escape the root with "..", and paths with NUL bytes or backslashes,
+// which are separators on Windows but part of the name on S3.
+func NormalizeStoragePath(name string) (string, error) {
+	switch {
+	case strings.ContainsRune(name, 0):
+		return "", fmt.Errorf("%w: contains a NUL byte", ErrStorageInvalidPath)
+	case strings.ContainsRune(name, '\\'):
+		return "", fmt.Errorf("%w: contains a backslash", ErrStorageInvalidPath)
+	case strings.HasPrefix(name, "/"):
+		return "", fmt.Errorf("%w: is absolute", ErrStorageInvalidPath)
+	}
+
+	cleaned := path.Clean(name)
+
+	switch {
+	case cleaned == ".":
+		return "", nil
+	case cleaned == ".." || strings.HasPrefix(cleaned, "../"):
+		return "", fmt.Errorf("%w: escapes the root", ErrStorageInvalidPath)
+	default:
+		return cleaned, nil
+	}
+}
+
+// DirectoryPrefix returns directory with a trailing slash, the prefix of the paths inside it.
+func DirectoryPrefix(directory string) string {
+	if directory == "" || strings.HasSuffix(directory, "/") {
+		return directory
+	}
+
+	return directory + "/"
+}
@@ -2,6 +2,7 @@ package facades
 
 import (
 	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
 	"github.com/gonstruct/providers/mail"
 )
 
@@ -16,3 +17,15 @@ func MailableOf[T contracts.Mailable](mailable T) *Mailable[T] {
 func (m Mailable[T]) Send(optionSlice ...mail.Option) error {
 	return mail.Send(m.mailable, optionSlice...)
 }
+
+func (m Mailable[T]) SendMany(recipients []mail.Recipient, optionSlice ...mail.Option) *mail.BatchResult {
+	return mail.SendMany(m.mailable, recipients, optionSlice...)
+}
+
+func (m Mailable[T]) SendWithReceipt(optionSlice ...mail.Option) (*entities.MailReceipt, error) {
+	return mail.SendWithReceipt(m.mailable, optionSlice...)
+}
+
+func (m Mailable[T]) Render(optionSlice ...mail.Option) (*mail.Rendered, error) {
+	return mail.Render(m.mailable, optionSlice...)
+}
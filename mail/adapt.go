@@ -2,6 +2,7 @@ package mail
 
 import (
 	"embed"
+	"time"
 
 	"github.com/gonstruct/providers/contracts"
 	"github.com/gonstruct/providers/entities/mailables"
@@ -13,6 +14,15 @@ type provider struct {
 	adapter         contracts.Mail
 	templates       embed.FS
 	defaultEnvelope *mailables.Envelope
+	mergeOptions    []mailables.MergeOption
+	localization    mailables.Localization
+	processors      []mailables.MessageProcessor
+
+	maxMessageSize    int64
+	maxAttachmentSize int64
+	attachmentLinks   *attachmentLinks
+
+	scheduleStore contracts.MailScheduleStore
 }
 
 func Adapt(adapter contracts.Mail, options ...func(*provider)) {
@@ -42,3 +52,72 @@ func WithDefaultEnvelope(envelope mailables.Envelope) func(*provider) {
 		p.defaultEnvelope = &envelope
 	}
 }
+
+// WithEnvelopeMerge configures how the default envelope and a mailable's envelope are merged.
+func WithEnvelopeMerge(options ...mailables.MergeOption) func(*provider) {
+	return func(p *provider) {
+		p.mergeOptions = append(p.mergeOptions, options...)
+	}
+}
+
+// WithDefaultLocale sets the locale used for mailables without a locale.
+func WithDefaultLocale(locale string) func(*provider) {
+	return func(p *provider) {
+		p.localization.Locale = locale
+	}
+}
+
+// WithFallbackLocales sets the locales tried when a view or message is missing in the requested locale.
+func WithFallbackLocales(locales ...string) func(*provider) {
+	return func(p *provider) {
+		p.localization.Fallbacks = locales
+	}
+}
+
+// WithTranslations sets the message catalog used by the "t" template helper.
+func WithTranslations(catalog mailables.Catalog) func(*provider) {
+	return func(p *provider) {
+		p.localization.Catalog = catalog
+	}
+}
+
+// WithMessageProcessors sets processors, like S/MIME or OpenPGP, applied to every built message.
+func WithMessageProcessors(processors ...mailables.MessageProcessor) func(*provider) {
+	return func(p *provider) {
+		p.processors = append(p.processors, processors...)
+	}
+}
+
+// WithMaxMessageSize sets the maximum size of an encoded message in bytes, overriding the limit of the adapter.
+// Messages above the limit are rejected with ErrMessageTooLarge before they reach the transport,
+// unless attachment links are configured.
+func WithMaxMessageSize(size int64) func(*provider) {
+	return func(p *provider) {
+		p.maxMessageSize = size
+	}
+}
+
+// WithMaxAttachmentSize sets the maximum size of a single attachment in bytes.
+func WithMaxAttachmentSize(size int64) func(*provider) {
+	return func(p *provider) {
+		p.maxAttachmentSize = size
+	}
+}
+
+// WithAttachmentLinks uploads attachments that exceed the size limits to the disk and replaces them
+// by download links in the body, valid for the given expiration (DefaultAttachmentLinkExpiration when 0).
+// The largest attachments are linked first until the message fits. The "mail.attachment_links"
+// translation introduces the links, "{expires}" in it is replaced by the expiry date.
+func WithAttachmentLinks(disk contracts.Storage, expiration time.Duration) func(*provider) {
+	return func(p *provider) {
+		p.attachmentLinks = newAttachmentLinks(disk, expiration)
+	}
+}
+
+// WithScheduleStore enables the local scheduler for messages sent with SendAt that the adapter
+// cannot schedule itself. Scheduled messages are persisted in the store and sent by RunScheduler.
+func WithScheduleStore(store contracts.MailScheduleStore) func(*provider) {
+	return func(p *provider) {
+		p.scheduleStore = store
+	}
+}
@@ -0,0 +1,276 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	netmail "net/mail"
+	"sync"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/entities/mailables"
+)
+
+const (
+	// DefaultConcurrency is the number of messages SendMany renders and sends at the same time.
+	DefaultConcurrency = 4
+	// DefaultBatchSize is the number of messages SendMany hands to a batch adapter at once.
+	DefaultBatchSize = 50
+)
+
+// Recipient is a single recipient of SendMany with the data for their copy of the mailable.
+type Recipient struct {
+	Address *netmail.Address
+	// With is merged over the data of the mailable's content
+	With map[string]any
+	// Locale overrides the locale of the mailable for this recipient
+	Locale string
+}
+
+// Result is the outcome of sending to a single recipient.
+type Result struct {
+	Recipient Recipient
+	// ID is the ID of the scheduled message when sent with SendAt, see Cancel
+	ID string
+	// Receipt is the receipt of the message when it was sent
+	Receipt *entities.MailReceipt
+	Err     error
+}
+
+// BatchResult holds the results of SendMany in the order of the recipients.
+type BatchResult struct {
+	Results []Result
+}
+
+// Failed returns the results of the recipients that could not be sent to.
+func (result *BatchResult) Failed() []Result {
+	var failed []Result
+
+	for _, r := range result.Results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+
+	return failed
+}
+
+// Err returns the errors of all failed recipients joined, or nil when every recipient was sent to.
+func (result *BatchResult) Err() error {
+	var errs []error
+
+	for _, r := range result.Failed() {
+		errs = append(errs, fmt.Errorf("%s: %w", r.Recipient.Address, r.Err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// SendMany renders the mailable once per recipient and sends every copy. The To of the
+// mailable is replaced by the recipient and the recipient's data is merged over the content data.
+//
+// Adapters implementing contracts.BatchMail receive the messages in batches of WithBatchSize, the
+// receipts of adapters implementing contracts.ReceiptBatchMail carry their message IDs. Other
+// adapters receive one Send call per recipient. At most WithConcurrency messages or batches are
+// rendered and sent at the same time. With SendAt every copy is scheduled, see Schedule.
+func SendMany(mailable contracts.Mailable, recipients []Recipient, optionSlice ...Option) *BatchResult {
+	options := apply(optionSlice...)
+	result := &BatchResult{Results: make([]Result, len(recipients))}
+	inputs := make([]entities.MailInput, len(recipients))
+
+	parallel(len(recipients), options.Concurrency, func(i int) {
+		result.Results[i].Recipient = recipients[i]
+
+		if err := options.Context.Err(); err != nil {
+			result.Results[i].Err = err
+
+			return
+		}
+
+		if recipients[i].Address == nil {
+			result.Results[i].Err = Err("validate", ErrNoRecipients)
+
+			return
+		}
+
+		recipientOptions := *options
+		if recipients[i].Locale != "" {
+			recipientOptions.Locale = recipients[i].Locale
+		}
+
+		// Every copy needs its own key, or scheduling one would replace the others
+		// and the copies would share a Message-ID
+		if options.IdempotencyKey != "" {
+			recipientOptions.IdempotencyKey = options.IdempotencyKey + ":" + recipients[i].Address.Address
+		}
+
+		inputs[i], result.Results[i].Err = build(recipientMailable{Mailable: mailable, recipient: recipients[i]}, &recipientOptions)
+	})
+
+	var pending []int
+
+	for i := range result.Results {
+		if result.Results[i].Err == nil {
+			pending = append(pending, i)
+		}
+	}
+
+	if scheduled(options) {
+		parallel(len(pending), options.Concurrency, func(i int) {
+			index := pending[i]
+			result.Results[index].ID, result.Results[index].Err = schedule(options, inputs[index])
+		})
+
+		return result
+	}
+
+	batcher, ok := options.Adapter.(contracts.BatchMail)
+	if !ok {
+		parallel(len(pending), options.Concurrency, func(i int) {
+			index := pending[i]
+			result.Results[index].Receipt, result.Results[index].Err = deliver(options.Context, options.Adapter, inputs[index])
+		})
+
+		return result
+	}
+
+	batches := chunk(pending, options.BatchSize)
+
+	parallel(len(batches), options.Concurrency, func(i int) {
+		batch := make([]entities.MailInput, len(batches[i]))
+		for j, index := range batches[i] {
+			batch[j] = inputs[index]
+		}
+
+		var (
+			receipts []*entities.MailReceipt
+			errs     []error
+		)
+
+		if receiptBatcher, ok := batcher.(contracts.ReceiptBatchMail); ok {
+			receipts, errs = receiptBatcher.SendBatchWithReceipt(options.Context, batch)
+		} else {
+			errs = batcher.SendBatch(options.Context, batch)
+		}
+
+		for j, index := range batches[i] {
+			if j < len(errs) {
+				result.Results[index].Err = errs[j]
+			} else {
+				result.Results[index].Err = Err("send batch", ErrSendFailed)
+			}
+
+			if result.Results[index].Err != nil {
+				continue
+			}
+
+			// Adapters that cannot report the message of an input keep the generated Message-ID
+			if j < len(receipts) && receipts[j] != nil {
+				result.Results[index].Receipt = receipts[j]
+			} else {
+				result.Results[index].Receipt = NewReceipt(inputs[index], "")
+			}
+		}
+	})
+
+	return result
+}
+
+// WithConcurrency limits how many messages or batches SendMany renders and sends at the same time.
+func WithConcurrency(concurrency int) Option {
+	return func(options *options) {
+		options.Concurrency = max(concurrency, 1)
+	}
+}
+
+// WithBatchSize sets how many messages SendMany hands to a batch adapter at once.
+func WithBatchSize(size int) Option {
+	return func(options *options) {
+		options.BatchSize = max(size, 1)
+	}
+}
+
+type recipientMailable struct {
+	contracts.Mailable
+	recipient Recipient
+}
+
+func (m recipientMailable) Envelope() mailables.Envelope {
+	envelope := m.Mailable.Envelope()
+	envelope.To = mailables.Addresses(m.recipient.Address)
+
+	return envelope
+}
+
+func (m recipientMailable) Content() mailables.Content {
+	content := m.Mailable.Content()
+
+	with := make(map[string]any, len(content.With)+len(m.recipient.With))
+	for key, value := range content.With {
+		with[key] = value
+	}
+
+	for key, value := range m.recipient.With {
+		with[key] = value
+	}
+
+	content.With = with
+
+	return content
+}
+
+func (m recipientMailable) Unwrap() contracts.Mailable {
+	return m.Mailable
+}
+
+func (m recipientMailable) Processors() []mailables.MessageProcessor {
+	if processed, ok := m.Mailable.(contracts.ProcessedMailable); ok {
+		return processed.Processors()
+	}
+
+	return nil
+}
+
+func (m recipientMailable) Locale() string {
+	if localized, ok := m.Mailable.(contracts.LocalizedMailable); ok {
+		return localized.Locale()
+	}
+
+	return ""
+}
+
+// parallel calls fn for 0..n-1 with at most limit calls running at the same time.
+func parallel(n, limit int, fn func(i int)) {
+	var wg sync.WaitGroup
+
+	semaphore := make(chan struct{}, max(limit, 1))
+
+	for i := range n {
+		wg.Add(1)
+
+		semaphore <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			fn(i)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func chunk(indexes []int, size int) [][]int {
+	var chunks [][]int
+
+	for size < len(indexes) {
+		indexes, chunks = indexes[size:], append(chunks, indexes[:size])
+	}
+
+	if len(indexes) > 0 {
+		chunks = append(chunks, indexes)
+	}
+
+	return chunks
+}
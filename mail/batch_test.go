@@ -0,0 +1,281 @@
+package mail_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/mail"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/entities/mailables"
+	pmail "github.com/gonstruct/providers/mail"
+	"github.com/gonstruct/providers/mail/smime"
+)
+
+func newsletter() testMailable {
+	return testMailable{
+		envelope: mailables.Envelope{
+			From:    mailables.Address("news@example.com", ""),
+			To:      mailables.Addresses("ignored@example.com"),
+			Subject: "Newsletter",
+		},
+		content: mailables.Content{
+			View: "welcome.html",
+			With: map[string]any{"name": "subscriber"},
+		},
+	}
+}
+
+func recipients(emails ...string) []pmail.Recipient {
+	list := make([]pmail.Recipient, len(emails))
+	for i, email := range emails {
+		list[i] = pmail.Recipient{
+			Address: &mail.Address{Address: email},
+			With:    map[string]any{"name": strings.Split(email, "@")[0]},
+		}
+	}
+
+	return list
+}
+
+func TestSendMany_PerRecipient(t *testing.T) {
+	f := pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS))
+	f.SendFunc = func(_ context.Context, input entities.MailInput) error {
+		if input.Envelope.To[0].Address == "bounce@example.com" {
+			return pmail.ErrRejected
+		}
+
+		if want := "Hello " + strings.Split(input.Envelope.To[0].Address, "@")[0]; !strings.Contains(input.Html.String(), want) {
+			t.Errorf("Html = %q, want it to contain %q", input.Html.String(), want)
+		}
+
+		if len(input.Envelope.To) != 1 {
+			t.Errorf("To = %v, want only the recipient", input.Envelope.To)
+		}
+
+		return nil
+	}
+
+	result := pmail.SendMany(newsletter(), recipients("anna@example.com", "bounce@example.com", "bob@example.com"))
+
+	failed := result.Failed()
+	if len(failed) != 1 || failed[0].Recipient.Address.Address != "bounce@example.com" {
+		t.Fatalf("Failed() = %v, want only bounce@example.com", failed)
+	}
+
+	if !errors.Is(result.Err(), pmail.ErrRejected) {
+		t.Errorf("Err() = %v, want ErrRejected", result.Err())
+	}
+
+	for i, r := range result.Results {
+		if r.Recipient.Address.Address != recipients("anna@example.com", "bounce@example.com", "bob@example.com")[i].Address.Address {
+			t.Errorf("Results[%d] = %s, results should be in recipient order", i, r.Recipient.Address)
+		}
+	}
+}
+
+// encryptedNewsletter is a newsletter that every recipient receives S/MIME encrypted.
+type encryptedNewsletter struct {
+	testMailable
+	processor *smime.Processor
+}
+
+func (m encryptedNewsletter) Processors() []mailables.MessageProcessor {
+	return []mailables.MessageProcessor{m.processor}
+}
+
+func recipientCertificate(t *testing.T, email string) *x509.Certificate {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:   big.NewInt(time.Now().UnixNano()),
+		Subject:        pkix.Name{CommonName: email},
+		EmailAddresses: []string{email},
+		NotBefore:      time.Now().Add(-time.Hour),
+		NotAfter:       time.Now().Add(time.Hour),
+		KeyUsage:       x509.KeyUsageKeyEncipherment,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+
+	return certificate
+}
+
+func TestSendMany_ProcessedMailable(t *testing.T) {
+	f := pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS))
+
+	mailable := encryptedNewsletter{newsletter(), smime.New(smime.WithEncryption(smime.Certificates{
+		"anna@example.com": recipientCertificate(t, "anna@example.com"),
+		"bob@example.com":  recipientCertificate(t, "bob@example.com"),
+	}))}
+
+	if err := pmail.SendMany(mailable, recipients("anna@example.com", "bob@example.com")).Err(); err != nil {
+		t.Fatalf("SendMany() error = %v", err)
+	}
+
+	if len(f.Calls) != 2 {
+		t.Fatalf("sent %d messages, want 2", len(f.Calls))
+	}
+
+	for _, call := range f.Calls {
+		raw, err := pmail.Raw(context.Background(), call.Input)
+		if err != nil {
+			t.Fatalf("Raw() error = %v", err)
+		}
+
+		message, err := mail.ReadMessage(bytes.NewReader(raw))
+		if err != nil {
+			t.Fatalf("ReadMessage() error = %v", err)
+		}
+
+		if contentType := message.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "application/pkcs7-mime") {
+			t.Errorf("message to %s: Content-Type = %q, want it encrypted", call.Input.Envelope.To, contentType)
+		}
+
+		if bytes.Contains(raw, []byte("Hello")) {
+			t.Errorf("message to %s contains the plain body", call.Input.Envelope.To)
+		}
+	}
+}
+
+func TestSendMany_Concurrency(t *testing.T) {
+	var running, peak int32
+
+	f := pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS))
+	f.SendFunc = func(context.Context, entities.MailInput) error {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+
+		for {
+			old := atomic.LoadInt32(&peak)
+			if current <= old || atomic.CompareAndSwapInt32(&peak, old, current) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+
+		return nil
+	}
+
+	result := pmail.SendMany(newsletter(), recipients("a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com"), pmail.WithConcurrency(2))
+	if err := result.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	if peak > 2 {
+		t.Errorf("peak concurrent sends = %d, want at most 2", peak)
+	}
+}
+
+// batchAdapter implements contracts.BatchMail and records the batch sizes.
+type batchAdapter struct {
+	mu      sync.Mutex
+	batches []int
+}
+
+func (a *batchAdapter) Send(context.Context, entities.MailInput) error {
+	return errors.New("Send should not be called for batch adapters")
+}
+
+func (a *batchAdapter) SendBatch(_ context.Context, inputs []entities.MailInput) []error {
+	a.mu.Lock()
+	a.batches = append(a.batches, len(inputs))
+	a.mu.Unlock()
+
+	errs := make([]error, len(inputs))
+	for i, input := range inputs {
+		if input.Envelope.To[0].Address == "c@example.com" {
+			errs[i] = pmail.ErrRejected
+		}
+	}
+
+	return errs
+}
+
+func TestSendMany_BatchAdapter(t *testing.T) {
+	pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS))
+
+	adapter := &batchAdapter{}
+	result := pmail.SendMany(
+		newsletter(),
+		recipients("a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com"),
+		pmail.WithAdapter(adapter),
+		pmail.WithBatchSize(2),
+		pmail.WithConcurrency(1),
+	)
+
+	if got := adapter.batches; len(got) != 3 || got[0] != 2 || got[1] != 2 || got[2] != 1 {
+		t.Errorf("batches = %v, want [2 2 1]", got)
+	}
+
+	if failed := result.Failed(); len(failed) != 1 || failed[0].Recipient.Address.Address != "c@example.com" {
+		t.Errorf("Failed() = %v, want only c@example.com", failed)
+	}
+}
+
+// receiptBatchAdapter implements contracts.ReceiptBatchMail, it reports no receipt for b@example.com.
+type receiptBatchAdapter struct {
+	batchAdapter
+}
+
+func (a *receiptBatchAdapter) SendBatchWithReceipt(ctx context.Context, inputs []entities.MailInput) ([]*entities.MailReceipt, []error) {
+	errs := a.SendBatch(ctx, inputs)
+	receipts := make([]*entities.MailReceipt, len(inputs))
+
+	for i, input := range inputs {
+		if errs[i] == nil && input.Envelope.To[0].Address != "b@example.com" {
+			receipts[i] = pmail.NewReceipt(input, "provider-"+input.Envelope.To[0].Address)
+			receipts[i].MessageID = "<provider-" + input.Envelope.To[0].Address + ">"
+		}
+	}
+
+	return receipts, errs
+}
+
+func TestSendMany_ReceiptBatchAdapter(t *testing.T) {
+	pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS))
+
+	result := pmail.SendMany(
+		newsletter(),
+		recipients("a@example.com", "b@example.com", "c@example.com"),
+		pmail.WithAdapter(&receiptBatchAdapter{}),
+	)
+
+	a, b, c := result.Results[0], result.Results[1], result.Results[2]
+
+	if a.Err != nil || a.Receipt == nil || a.Receipt.ProviderID != "provider-a@example.com" || a.Receipt.MessageID != "<provider-a@example.com>" {
+		t.Errorf("receipt of a@example.com = %+v, %v, want the receipt of the adapter", a.Receipt, a.Err)
+	}
+
+	if b.Err != nil || b.Receipt == nil || b.Receipt.ProviderID != "" || b.Receipt.MessageID == "" {
+		t.Errorf("receipt of b@example.com = %+v, %v, want a receipt with the generated Message-ID", b.Receipt, b.Err)
+	}
+
+	if c.Err == nil || c.Receipt != nil {
+		t.Errorf("receipt of c@example.com = %+v, %v, want the error", c.Receipt, c.Err)
+	}
+}
@@ -3,6 +3,9 @@ package mail
 import (
 	"errors"
 	"fmt"
+	"net/http"
+
+	"github.com/gonstruct/providers/entities/mailables"
 )
 
 // Sentinel errors for mail operations.
@@ -11,8 +14,45 @@ var (
 	ErrNoSender     = errors.New("no sender specified")
 	ErrNoRecipients = errors.New("no recipients specified")
 	ErrSendFailed   = errors.New("failed to send email")
+	ErrUnauthorized = errors.New("provider rejected the credentials")
+	ErrRateLimited  = errors.New("provider rate limit exceeded")
+	ErrRejected     = errors.New("provider rejected the message")
+	// ErrRawUnsupported is returned by adapters that cannot send the processed MIME message
+	ErrRawUnsupported = errors.New("transport cannot send raw MIME messages")
+
+	// ErrInvalidAddress matches the *mailables.ValidationError returned when an envelope address is invalid.
+	ErrInvalidAddress = mailables.ErrInvalidAddress
 )
 
+// APIError is returned by HTTP API adapters when the provider responds with an error status.
+// It matches ErrUnauthorized, ErrRateLimited, ErrRejected or ErrSendFailed with errors.Is.
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s API returned status %d", e.Provider, e.StatusCode)
+	}
+
+	return fmt.Sprintf("%s API returned status %d: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= 400 && e.StatusCode < 500:
+		return ErrRejected
+	default:
+		return ErrSendFailed
+	}
+}
+
 // Err wraps an error with mail context.
 func Err(op string, err error) error {
 	return fmt.Errorf("mail: %s: %w", op, err)
@@ -2,8 +2,10 @@ package mail
 
 import (
 	"embed"
+	"time"
 
 	"github.com/gonstruct/providers/adapters/mail/fake"
+	"github.com/gonstruct/providers/contracts"
 	"github.com/gonstruct/providers/entities/mailables"
 )
 
@@ -24,6 +26,73 @@ func WithFakeDefaultEnvelope(envelope mailables.Envelope) FakeOption {
 	}
 }
 
+// WithFakeEnvelopeMerge sets the envelope merge options for the fake adapter.
+func WithFakeEnvelopeMerge(options ...mailables.MergeOption) FakeOption {
+	return func(p *provider) {
+		p.mergeOptions = append(p.mergeOptions, options...)
+	}
+}
+
+// WithFakeDefaultLocale sets the default locale for the fake adapter.
+func WithFakeDefaultLocale(locale string) FakeOption {
+	return func(p *provider) {
+		p.localization.Locale = locale
+	}
+}
+
+// WithFakeFallbackLocales sets the fallback locales for the fake adapter.
+func WithFakeFallbackLocales(locales ...string) FakeOption {
+	return func(p *provider) {
+		p.localization.Fallbacks = locales
+	}
+}
+
+// WithFakeTranslations sets the message catalog for the fake adapter.
+func WithFakeTranslations(catalog mailables.Catalog) FakeOption {
+	return func(p *provider) {
+		p.localization.Catalog = catalog
+	}
+}
+
+// WithFakeMessageProcessors sets the message processors for the fake adapter.
+func WithFakeMessageProcessors(processors ...mailables.MessageProcessor) FakeOption {
+	return func(p *provider) {
+		p.processors = append(p.processors, processors...)
+	}
+}
+
+// WithFakeMaxMessageSize sets the maximum message size for the fake adapter.
+func WithFakeMaxMessageSize(size int64) FakeOption {
+	return func(p *provider) {
+		p.maxMessageSize = size
+	}
+}
+
+// WithFakeMaxAttachmentSize sets the maximum attachment size for the fake adapter.
+func WithFakeMaxAttachmentSize(size int64) FakeOption {
+	return func(p *provider) {
+		p.maxAttachmentSize = size
+	}
+}
+
+// WithFakeAttachmentLinks sets the disk oversized attachments are linked from for the fake adapter.
+func WithFakeAttachmentLinks(disk contracts.Storage, expiration time.Duration) FakeOption {
+	return func(p *provider) {
+		p.attachmentLinks = newAttachmentLinks(disk, expiration)
+	}
+}
+
+// WithFakeScheduleStore sends messages scheduled with SendAt through the local scheduler and the store,
+// for testing RunScheduler and SendDue. Without it the fake adapter queues scheduled messages itself,
+// see fake.Adapter.AssertQueued.
+func WithFakeScheduleStore(store contracts.MailScheduleStore) FakeOption {
+	return func(p *provider) {
+		p.scheduleStore = store
+		// Hide the scheduling of the fake adapter, so the local scheduler is used
+		p.adapter = struct{ contracts.Mail }{p.adapter}
+	}
+}
+
 // Fake sets up a fake mail adapter for testing and returns it for assertions.
 // This replaces any existing mail provider.
 //
@@ -0,0 +1,300 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"mime"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gonstruct/providers/contracts"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// UpdateGoldenEnv is the environment variable that makes AssertGolden write the golden files.
+const UpdateGoldenEnv = "MAIL_UPDATE_GOLDEN"
+
+// TB is the part of testing.TB that AssertGolden uses, so the mail package does not import testing.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
+}
+
+// updateGolden reports whether AssertGolden writes the golden files instead of comparing them,
+// with UpdateGoldenEnv or with an -update flag that the test package defines. The flag is looked
+// up when comparing, after the flags of the test binary are registered and parsed.
+func updateGolden() bool {
+	if update, err := strconv.ParseBool(os.Getenv(UpdateGoldenEnv)); err == nil {
+		return update
+	}
+
+	if update := flag.Lookup("update"); update != nil {
+		if getter, ok := update.Value.(flag.Getter); ok {
+			value, _ := getter.Get().(bool)
+
+			return value
+		}
+	}
+
+	return false
+}
+
+// Volatile values are replaced before comparing, so golden files only change when the mail does.
+var goldenNormalizers = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(?m)^(Date|Message-ID|Message-Id): .*$`), "$1: <$1>"},
+	{regexp.MustCompile(`boundary="?[^";\s]+"?`), `boundary="<boundary>"`},
+	{regexp.MustCompile(`\b(?:Mon|Tue|Wed|Thu|Fri|Sat|Sun), \d{1,2} \w{3} \d{4} \d{2}:\d{2}:\d{2} [+-]\d{4}\b`), "<date>"},
+	{regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2})`), "<timestamp>"},
+}
+
+// AssertGolden renders the mailable like Send does, including the default envelope, localization
+// and attachment limits, and compares the result with golden files in testdata:
+//
+//	testdata/<name>.html     the rendered HTML body
+//	testdata/<name>.txt      the text of the HTML body, to review copy changes
+//	testdata/<name>.headers  the message headers, sorted and decoded
+//
+// Dates, message IDs and MIME boundaries are normalized. Set MAIL_UPDATE_GOLDEN to write the
+// golden files, a boolean -update flag of the test package works too:
+//
+//	MAIL_UPDATE_GOLDEN=1 go test ./...
+//
+// The message is rendered before processors like S/MIME are applied, as their output is not stable.
+// Attachments that WithAttachmentLinks would link are not uploaded, like with Render.
+// A mail provider, usually Fake with the application templates, must be set up first.
+func AssertGolden(t TB, name string, mailable contracts.Mailable, optionSlice ...Option) {
+	t.Helper()
+
+	options := apply(optionSlice...)
+
+	input, localization, err := compose(mailable, options)
+	if err == nil {
+		input, err = enforceLimits(options, localization, input, false)
+	}
+
+	if err != nil {
+		t.Fatalf("AssertGolden(%q) render error = %v", name, err)
+	}
+
+	message, err := NewMessage(options.Context, input)
+	if err != nil {
+		t.Fatalf("AssertGolden(%q) build message error = %v", name, err)
+	}
+
+	var raw bytes.Buffer
+	if _, err := message.WriteTo(&raw); err != nil {
+		t.Fatalf("AssertGolden(%q) write message error = %v", name, err)
+	}
+
+	headers, err := goldenHeaders(raw.Bytes())
+	if err != nil {
+		t.Fatalf("AssertGolden(%q) read headers error = %v", name, err)
+	}
+
+	body := input.Html.String()
+
+	compareGolden(t, filepath.Join("testdata", name+".html"), body)
+	compareGolden(t, filepath.Join("testdata", name+".txt"), PlainText(body))
+	compareGolden(t, filepath.Join("testdata", name+".headers"), headers)
+}
+
+func compareGolden(t TB, path, got string) {
+	t.Helper()
+
+	got = normalizeGolden(got)
+
+	if updateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+		}
+
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil { //nolint:gosec
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("golden file %s does not exist, run the tests with MAIL_UPDATE_GOLDEN=1 to create it", path)
+
+		return
+	}
+
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+
+	if got != string(want) {
+		t.Errorf("%s does not match, run the tests with MAIL_UPDATE_GOLDEN=1 to accept the changes:\n%s", path, lineDiff(string(want), got))
+	}
+}
+
+func normalizeGolden(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+
+	for _, normalizer := range goldenNormalizers {
+		value = normalizer.pattern.ReplaceAllString(value, normalizer.replacement)
+	}
+
+	if !strings.HasSuffix(value, "\n") {
+		value += "\n"
+	}
+
+	return value
+}
+
+// goldenHeaders returns the headers of the raw message as sorted "Name: value" lines.
+func goldenHeaders(raw []byte) (string, error) {
+	message, err := netmail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return "", err
+	}
+
+	decoder := new(mime.WordDecoder)
+
+	var lines []string
+
+	for name, values := range message.Header {
+		for _, value := range values {
+			if decoded, err := decoder.DecodeHeader(value); err == nil {
+				value = decoded
+			}
+
+			lines = append(lines, name+": "+value)
+		}
+	}
+
+	sort.Strings(lines)
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// PlainText returns the text of an HTML body, with a line per block element
+// and the target of links after their text.
+func PlainText(body string) string {
+	var text strings.Builder
+
+	tokenizer := html.NewTokenizer(strings.NewReader(body))
+	skip := 0
+
+	var href string
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return collapseLines(text.String())
+		case html.TextToken:
+			if skip == 0 {
+				text.Write(tokenizer.Text())
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+
+			switch token.DataAtom {
+			case atom.Script, atom.Style, atom.Head:
+				skip++
+			case atom.A:
+				href = attribute(token, "href")
+			case atom.Br, atom.P, atom.Div, atom.Tr, atom.Li, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Table:
+				text.WriteString("\n")
+			}
+		case html.EndTagToken:
+			token := tokenizer.Token()
+
+			switch token.DataAtom {
+			case atom.Script, atom.Style, atom.Head:
+				skip = max(skip-1, 0)
+			case atom.A:
+				if href != "" && !strings.HasPrefix(href, "#") {
+					fmt.Fprintf(&text, " (%s)", href)
+				}
+
+				href = ""
+			case atom.P, atom.Div, atom.Tr, atom.Li, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Table:
+				text.WriteString("\n")
+			case atom.Td, atom.Th:
+				text.WriteString(" ")
+			}
+		}
+	}
+}
+
+func attribute(token html.Token, name string) string {
+	for _, attribute := range token.Attr {
+		if attribute.Key == name {
+			return attribute.Val
+		}
+	}
+
+	return ""
+}
+
+// collapseLines collapses whitespace within lines and removes empty lines.
+func collapseLines(text string) string {
+	var lines []string
+
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// lineDiff returns a line based diff of want and got, with "-" for removed and "+" for added lines.
+func lineDiff(want, got string) string {
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+
+	// lengths[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	var diff strings.Builder
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			diff.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lengths[i+1][j] >= lengths[i][j+1]):
+			diff.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			diff.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+
+	return diff.String()
+}
@@ -0,0 +1,93 @@
+package mail_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gonstruct/providers/entities/mailables"
+	pmail "github.com/gonstruct/providers/mail"
+)
+
+// update is the common -update flag of test packages, the mail package must not define it too.
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func TestAssertGolden(t *testing.T) {
+	catalog, err := mailables.LoadCatalog(os.DirFS("testdata"), "lang")
+	if err != nil {
+		t.Fatalf("LoadCatalog() error = %v", err)
+	}
+
+	pmail.Fake(
+		pmail.WithFakeTemplates(testTemplatesFS),
+		pmail.WithFakeTranslations(catalog),
+		pmail.WithFakeFallbackLocales("en"),
+		pmail.WithFakeDefaultEnvelope(mailables.Envelope{
+			From: mailables.Address("billing@test.com", "Billing"),
+		}),
+	)
+
+	mailable := invoiceMailable("nl")
+	mailable.envelope.Headers = map[string]string{"X-Invoice": "2026-0042"}
+	mailable.attachments = mailables.Attachments(mailables.Attachment(
+		mailables.WithName("invoice.pdf"),
+		mailables.WithContent([]byte("%PDF")),
+	))
+
+	pmail.AssertGolden(t, "golden/invoice.nl", mailable)
+}
+
+func TestPlainText(t *testing.T) {
+	body := `<html><head><style>p { color: red }</style></head><body>
+		<h1>Welcome,   Anna</h1>
+		<p>Confirm your <a href="https://example.com/confirm">account</a>.<br>Thanks!</p>
+		<table><tr><td>Total</td><td>€ 10</td></tr></table>
+	</body></html>`
+
+	want := "Welcome, Anna\nConfirm your account (https://example.com/confirm).\nThanks!\nTotal € 10"
+
+	if got := pmail.PlainText(body); got != want {
+		t.Errorf("PlainText() = %q, want %q", got, want)
+	}
+}
+
+func TestAssertGolden_Update(t *testing.T) {
+	pmail.Fake(
+		pmail.WithFakeTemplates(testTemplatesFS),
+		pmail.WithFakeDefaultEnvelope(mailables.Envelope{From: mailables.Address("billing@test.com", "Billing")}),
+	)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Chdir() error = %v", err)
+	}
+
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	mailable := invoiceMailable("en")
+
+	t.Setenv(pmail.UpdateGoldenEnv, "1")
+	pmail.AssertGolden(t, "env", mailable)
+
+	t.Setenv(pmail.UpdateGoldenEnv, "")
+	*update = true
+
+	t.Cleanup(func() { *update = false })
+	pmail.AssertGolden(t, "flag", mailable)
+
+	for _, name := range []string{"env.html", "env.txt", "env.headers", "flag.html"} {
+		if _, err := os.Stat(filepath.Join(dir, "testdata", name)); err != nil {
+			t.Errorf("golden file %s was not written: %v", name, err)
+		}
+	}
+
+	// Without updates the written files are compared
+	*update = false
+	pmail.AssertGolden(t, "env", mailable)
+}
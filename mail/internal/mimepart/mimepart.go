@@ -0,0 +1,118 @@
+// Package mimepart splits and assembles the MIME entities used to sign and encrypt messages.
+package mimepart
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const crlf = "\r\n"
+
+var ErrMalformed = errors.New("malformed MIME message")
+
+// Split splits a message into the headers that stay on the outer message and the
+// MIME entity, the Content-* headers and the body, that is signed or encrypted.
+// Both are returned with CRLF line endings.
+func Split(message []byte) (outer, entity []byte, err error) {
+	message = Canonicalize(message)
+
+	end := bytes.Index(message, []byte(crlf+crlf))
+	if end < 0 {
+		return nil, nil, ErrMalformed
+	}
+
+	var outerHeaders, contentHeaders bytes.Buffer
+
+	var current *bytes.Buffer
+
+	for _, line := range strings.SplitAfter(string(message[:end+len(crlf)]), crlf) {
+		if line == "" {
+			continue
+		}
+
+		// Folded lines continue the previous header
+		if line[0] != ' ' && line[0] != '\t' {
+			current = &outerHeaders
+			if strings.HasPrefix(strings.ToLower(line), "content-") {
+				current = &contentHeaders
+			}
+		}
+
+		if current == nil {
+			return nil, nil, ErrMalformed
+		}
+
+		current.WriteString(line)
+	}
+
+	contentHeaders.WriteString(crlf)
+	contentHeaders.Write(message[end+2*len(crlf):])
+
+	return outerHeaders.Bytes(), contentHeaders.Bytes(), nil
+}
+
+// Canonicalize converts line endings to CRLF, the form signatures are computed over.
+func Canonicalize(data []byte) []byte {
+	data = bytes.ReplaceAll(data, []byte(crlf), []byte("\n"))
+
+	return bytes.ReplaceAll(data, []byte("\n"), []byte(crlf))
+}
+
+// Join builds a message from the outer headers and a MIME entity.
+func Join(outer, entity []byte) []byte {
+	return append(append(bytes.Clone(outer), entity...), crlf...)
+}
+
+// Entity builds a MIME entity from headers, given as "Name: value" lines, and a body.
+func Entity(body []byte, headers ...string) []byte {
+	var entity bytes.Buffer
+
+	for _, header := range headers {
+		entity.WriteString(header + crlf)
+	}
+
+	entity.WriteString(crlf)
+	entity.Write(body)
+
+	return entity.Bytes()
+}
+
+// Multipart joins the parts into a multipart body with a random boundary.
+func Multipart(parts ...[]byte) (boundary string, body []byte) {
+	random := make([]byte, 16)
+	_, _ = rand.Read(random)
+
+	boundary = hex.EncodeToString(random)
+
+	var buffer bytes.Buffer
+
+	for _, part := range parts {
+		buffer.WriteString("--" + boundary + crlf)
+		buffer.Write(part)
+		buffer.WriteString(crlf)
+	}
+
+	buffer.WriteString("--" + boundary + "--" + crlf)
+
+	return boundary, buffer.Bytes()
+}
+
+// Base64 encodes data as base64 in lines of 76 characters.
+func Base64(data []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	var buffer bytes.Buffer
+
+	for len(encoded) > 76 {
+		buffer.WriteString(encoded[:76] + crlf)
+		encoded = encoded[76:]
+	}
+
+	buffer.WriteString(encoded + crlf)
+
+	return buffer.Bytes()
+}
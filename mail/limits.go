@@ -0,0 +1,333 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"mime/quotedprintable"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/entities/mailables"
+)
+
+const (
+	// MB is a megabyte as used by mail providers for their limits.
+	MB int64 = 1024 * 1024
+
+	// DefaultAttachmentLinkExpiration is how long linked attachments can be downloaded.
+	DefaultAttachmentLinkExpiration = 7 * 24 * time.Hour
+	// AttachmentLinkDirectory is the directory oversized attachments are uploaded to.
+	AttachmentLinkDirectory = "mail-attachments"
+
+	// attachmentLinksKey is the translation key of the text that introduces the links.
+	attachmentLinksKey = "mail.attachment_links"
+
+	// headerOverhead estimates the size of the message headers and MIME boundaries.
+	headerOverhead = 2048
+	// partOverhead estimates the size of the headers of an attachment part.
+	partOverhead = 256
+)
+
+var (
+	ErrMessageTooLarge    = errors.New("message exceeds the maximum message size")
+	ErrAttachmentTooLarge = errors.New("attachment exceeds the maximum attachment size")
+)
+
+// SizeError is returned when a message or attachment is larger than the configured limit.
+// It matches ErrMessageTooLarge or ErrAttachmentTooLarge with errors.Is.
+type SizeError struct {
+	// Attachment is the name of the attachment for ErrAttachmentTooLarge
+	Attachment string
+	Size       int64
+	Limit      int64
+}
+
+func (e *SizeError) Error() string {
+	if e.Attachment != "" {
+		return fmt.Sprintf("attachment %q is %d bytes, the limit is %d bytes", e.Attachment, e.Size, e.Limit)
+	}
+
+	return fmt.Sprintf("message is %d bytes after encoding, the limit is %d bytes", e.Size, e.Limit)
+}
+
+func (e *SizeError) Unwrap() error {
+	if e.Attachment != "" {
+		return ErrAttachmentTooLarge
+	}
+
+	return ErrMessageTooLarge
+}
+
+// attachmentLinks uploads oversized attachments to a disk and links to them from the body.
+type attachmentLinks struct {
+	disk       contracts.Storage
+	expiration time.Duration
+}
+
+func newAttachmentLinks(disk contracts.Storage, expiration time.Duration) *attachmentLinks {
+	if expiration == 0 {
+		expiration = DefaultAttachmentLinkExpiration
+	}
+
+	return &attachmentLinks{disk: disk, expiration: expiration}
+}
+
+// EncodedSize returns the size of content of the given size after base64 encoding in lines of 76 characters.
+func EncodedSize(size int64) int64 {
+	encoded := (size + 2) / 3 * 4
+
+	return encoded + (encoded+75)/76*2
+}
+
+// MessageSize estimates the size of the message on the wire, after the body is encoded as
+// quoted-printable and the attachments as base64. Processed messages are encrypted as a whole
+// and base64 encoded again, so their estimate includes that expansion.
+func MessageSize(ctx context.Context, input entities.MailInput) (int64, error) {
+	sizes, err := attachmentSizes(ctx, input.Attachments)
+	if err != nil {
+		return 0, err
+	}
+
+	return messageSize(input, sizes), nil
+}
+
+func attachmentSizes(ctx context.Context, attachments mailables.AttachmentSlice) ([]int64, error) {
+	sizes := make([]int64, len(attachments))
+
+	for i, attachment := range attachments {
+		size, err := attachment.Size(ctx)
+		if err != nil {
+			return nil, Err("size attachment", err)
+		}
+
+		sizes[i] = size
+	}
+
+	return sizes, nil
+}
+
+func messageSize(input entities.MailInput, sizes []int64) int64 {
+	size := int64(headerOverhead + len(input.Envelope.Subject))
+
+	for _, recipients := range [][]string{input.Envelope.To.String(), input.Envelope.Cc.String()} {
+		for _, recipient := range recipients {
+			size += int64(len(recipient)) + 2
+		}
+	}
+
+	for name, value := range Headers(input) {
+		size += int64(len(name)+len(value)) + 4
+	}
+
+	var body countingWriter
+
+	writer := quotedprintable.NewWriter(&body)
+	_, _ = writer.Write(input.Html.Bytes())
+	_ = writer.Close()
+
+	size += int64(body)
+
+	for i, attachment := range input.Attachments {
+		size += EncodedSize(sizes[i]) + partOverhead
+
+		// Calendar invites are included twice, as an alternative part and as a file
+		if attachment.IsCalendar() {
+			size += EncodedSize(sizes[i]) + partOverhead
+		}
+	}
+
+	if len(input.Processors) > 0 {
+		size = EncodedSize(size) + headerOverhead
+	}
+
+	return size
+}
+
+type countingWriter int
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+
+	return len(p), nil
+}
+
+// enforceLimits checks the message and attachment sizes against the configured limits and,
+// when attachment links are configured, replaces the attachments that do not fit by download links.
+// Without upload the links are previews that point nowhere, nothing is uploaded.
+//
+//nolint:cyclop
+func enforceLimits(options *options, localization mailables.Localization, input entities.MailInput, upload bool) (entities.MailInput, error) {
+	messageLimit := options.MaxMessageSize
+	if limits, ok := options.Adapter.(contracts.MailLimits); ok && messageLimit == 0 {
+		messageLimit = limits.MaxMessageSize()
+	}
+
+	if messageLimit == 0 && options.MaxAttachmentSize == 0 {
+		return input, nil
+	}
+
+	sizes, err := attachmentSizes(options.Context, input.Attachments)
+	if err != nil {
+		return input, err
+	}
+
+	// Attachments that should become links, largest first, calendar invites are always kept
+	var linked []int
+
+	for i, attachment := range input.Attachments {
+		if options.MaxAttachmentSize > 0 && sizes[i] > options.MaxAttachmentSize {
+			if options.AttachmentLinks == nil || attachment.IsCalendar() {
+				return input, Err("check size", &SizeError{Attachment: attachment.Name, Size: sizes[i], Limit: options.MaxAttachmentSize})
+			}
+
+			linked = append(linked, i)
+		}
+	}
+
+	size := messageSize(input, sizes)
+	for _, i := range linked {
+		size -= EncodedSize(sizes[i]) + partOverhead
+	}
+
+	if messageLimit > 0 && size > messageLimit && options.AttachmentLinks != nil {
+		candidates := make([]int, 0, len(input.Attachments))
+
+		for i, attachment := range input.Attachments {
+			if !attachment.IsCalendar() && !containsIndex(linked, i) {
+				candidates = append(candidates, i)
+			}
+		}
+
+		sort.SliceStable(candidates, func(a, b int) bool { return sizes[candidates[a]] > sizes[candidates[b]] })
+
+		for _, i := range candidates {
+			if size <= messageLimit {
+				break
+			}
+
+			linked = append(linked, i)
+			size -= EncodedSize(sizes[i]) + partOverhead
+		}
+	}
+
+	if messageLimit > 0 && size > messageLimit {
+		return input, Err("check size", &SizeError{Size: size, Limit: messageLimit})
+	}
+
+	if len(linked) == 0 {
+		return input, nil
+	}
+
+	return options.AttachmentLinks.replace(options.Context, localization, input, linked, sizes, upload)
+}
+
+func containsIndex(indexes []int, index int) bool {
+	for _, i := range indexes {
+		if i == index {
+			return true
+		}
+	}
+
+	return false
+}
+
+// replace adds a list of download links to the body instead of the linked attachments. With upload
+// it uploads the attachments, without it the links of the preview point to "#".
+func (links *attachmentLinks) replace(
+	ctx context.Context,
+	localization mailables.Localization,
+	input entities.MailInput,
+	linked []int,
+	sizes []int64,
+	upload bool,
+) (entities.MailInput, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return input, Err("link attachment", err)
+	}
+
+	directory := path.Join(AttachmentLinkDirectory, hex.EncodeToString(random))
+
+	var items strings.Builder
+
+	for _, i := range linked {
+		attachment := input.Attachments[i]
+		url := "#"
+
+		if upload {
+			// Names without a base name, like empty names, would be stored as the directory
+			name := path.Base(attachment.Name)
+			if name == "." || name == ".." || name == "/" {
+				name = fmt.Sprintf("attachment-%d", i)
+			}
+
+			location := path.Join(directory, name)
+
+			reader, err := attachment.Open(ctx)
+			if err != nil {
+				return input, Err("link attachment", err)
+			}
+
+			err = links.disk.PutStream(ctx, location, reader)
+			reader.Close()
+
+			if err != nil {
+				return input, Err("link attachment", err)
+			}
+
+			url, err = links.disk.TemporaryURL(ctx, location, links.expiration)
+			if err != nil {
+				return input, Err("link attachment", err)
+			}
+		}
+
+		fmt.Fprintf(&items, `<li><a href="%s">%s</a> (%s)</li>`, html.EscapeString(url), html.EscapeString(attachment.Name), formatSize(sizes[i]))
+	}
+
+	attachments := make(mailables.AttachmentSlice, 0, len(input.Attachments)-len(linked))
+
+	for i, attachment := range input.Attachments {
+		if !containsIndex(linked, i) {
+			attachments = append(attachments, attachment)
+		}
+	}
+
+	// The translation is not a format string, it may leave out the {expires} placeholder
+	intro := localization.Translate(attachmentLinksKey)
+	if intro == attachmentLinksKey {
+		intro = "Some attachments were too large to send by email. You can download them until {expires}:"
+	}
+
+	expires := time.Now().Add(links.expiration).Format("2006-01-02")
+	intro = strings.ReplaceAll(intro, "{expires}", expires)
+	notice := fmt.Sprintf(`<div class="attachment-links"><p>%s</p><ul>%s</ul></div>`, html.EscapeString(intro), items.String())
+
+	body := input.Html.String()
+	if index := strings.LastIndex(strings.ToLower(body), "</body>"); index >= 0 {
+		body = body[:index] + notice + body[index:]
+	} else {
+		body += notice
+	}
+
+	input.Attachments = attachments
+	input.Html = *bytes.NewBufferString(body)
+
+	return input, nil
+}
+
+func formatSize(size int64) string {
+	if size >= MB {
+		return fmt.Sprintf("%.1f MB", float64(size)/float64(MB))
+	}
+
+	return fmt.Sprintf("%d KB", (size+1023)/1024)
+}
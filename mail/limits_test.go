@@ -0,0 +1,174 @@
+package mail_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	storagefake "github.com/gonstruct/providers/adapters/storage/fake"
+	"github.com/gonstruct/providers/entities/mailables"
+	pmail "github.com/gonstruct/providers/mail"
+)
+
+func withAttachments(sizes map[string]int) testMailable {
+	var attachments mailables.AttachmentSlice
+
+	for _, name := range []string{"small.pdf", "large.pdf"} {
+		if size, ok := sizes[name]; ok {
+			attachments = append(attachments, mailables.Attachment(
+				mailables.WithName(name),
+				mailables.WithContent(bytes.Repeat([]byte("x"), size)),
+			))
+		}
+	}
+
+	return testMailable{
+		envelope: mailables.Envelope{
+			From:    mailables.Address("noreply@test.com", ""),
+			To:      mailables.Addresses("user@example.com"),
+			Subject: "Reports",
+		},
+		content:     mailables.Content{View: "welcome.html", With: map[string]any{"name": "Anna"}},
+		attachments: attachments,
+	}
+}
+
+func TestSend_MessageTooLarge(t *testing.T) {
+	f := pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS), pmail.WithFakeMaxMessageSize(20*1024))
+
+	err := pmail.Send(withAttachments(map[string]int{"large.pdf": 30 * 1024}))
+
+	var sizeError *pmail.SizeError
+	if !errors.Is(err, pmail.ErrMessageTooLarge) || !errors.As(err, &sizeError) {
+		t.Fatalf("Send() error = %v, want ErrMessageTooLarge", err)
+	}
+
+	// 30 KB is 40 KB after base64 encoding
+	if sizeError.Size < 40*1024 || sizeError.Limit != 20*1024 {
+		t.Errorf("SizeError = %+v, want the encoded size and the limit", sizeError)
+	}
+
+	f.AssertNothingSent(t)
+}
+
+func TestSend_AttachmentTooLarge(t *testing.T) {
+	pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS), pmail.WithFakeMaxAttachmentSize(10*1024))
+
+	err := pmail.Send(withAttachments(map[string]int{"small.pdf": 1024, "large.pdf": 11 * 1024}))
+	if !errors.Is(err, pmail.ErrAttachmentTooLarge) || !strings.Contains(err.Error(), "large.pdf") {
+		t.Errorf("Send() error = %v, want ErrAttachmentTooLarge for large.pdf", err)
+	}
+}
+
+func TestSend_AttachmentLinks(t *testing.T) {
+	disk := storagefake.New()
+	disk.BaseURL = "https://files.example.com"
+
+	f := pmail.Fake(
+		pmail.WithFakeTemplates(testTemplatesFS),
+		pmail.WithFakeMaxMessageSize(20*1024),
+		pmail.WithFakeAttachmentLinks(disk, 24*time.Hour),
+	)
+
+	if err := pmail.Send(withAttachments(map[string]int{"small.pdf": 4 * 1024, "large.pdf": 30 * 1024})); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	input := f.LastCall().Input
+	if len(input.Attachments) != 1 || input.Attachments[0].Name != "small.pdf" {
+		t.Fatalf("Attachments = %v, want only small.pdf", input.Attachments)
+	}
+
+	link := regexp.MustCompile(`<a href="https://files\.example\.com/(mail-attachments/[0-9a-f]+/large\.pdf)\?expires=[^"]+">large\.pdf</a> \(30 KB\)`)
+
+	match := link.FindStringSubmatch(input.Html.String())
+	if match == nil {
+		t.Fatalf("Html = %q, want a download link for large.pdf", input.Html.String())
+	}
+
+	if !strings.HasSuffix(input.Html.String(), "</div></body></html>\n") {
+		t.Errorf("Html = %q, want the links before </body>", input.Html.String())
+	}
+
+	content, err := disk.Get(context.Background(), match[1])
+	if err != nil || len(content) != 30*1024 {
+		t.Errorf("uploaded attachment = %d bytes, %v, want 30 KB", len(content), err)
+	}
+}
+
+func TestSend_AttachmentLinks_Unnamed(t *testing.T) {
+	disk := storagefake.New()
+	disk.BaseURL = "https://files.example.com"
+
+	f := pmail.Fake(
+		pmail.WithFakeTemplates(testTemplatesFS),
+		pmail.WithFakeMaxMessageSize(20*1024),
+		pmail.WithFakeAttachmentLinks(disk, 24*time.Hour),
+	)
+
+	mailable := withAttachments(nil)
+	mailable.attachments = mailables.Attachments(
+		mailables.Attachment(mailables.WithContent(bytes.Repeat([]byte("x"), 30*1024))),
+		mailables.Attachment(mailables.WithName(".."), mailables.WithContent(bytes.Repeat([]byte("y"), 30*1024))),
+	)
+
+	if err := pmail.Send(mailable); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	link := regexp.MustCompile(`<a href="https://files\.example\.com/(mail-attachments/[0-9a-f]+/attachment-(\d))\?`)
+
+	matches := link.FindAllStringSubmatch(f.LastCall().Input.Html.String(), -1)
+	if len(matches) != 2 || matches[0][2] != "0" || matches[1][2] != "1" {
+		t.Fatalf("Html = %q, want links to attachment-0 and attachment-1", f.LastCall().Input.Html.String())
+	}
+
+	for i, match := range matches {
+		if content, err := disk.Get(context.Background(), match[1]); err != nil || len(content) != 30*1024 {
+			t.Errorf("uploaded attachment %d = %d bytes, %v, want 30 KB", i, len(content), err)
+		}
+	}
+}
+
+func TestEncodedSize(t *testing.T) {
+	// 57 bytes encode to a single line of 76 characters
+	if got := pmail.EncodedSize(57); got != 78 {
+		t.Errorf("EncodedSize(57) = %d, want 78", got)
+	}
+
+	if got := pmail.EncodedSize(58); got != 84 {
+		t.Errorf("EncodedSize(58) = %d, want 84", got)
+	}
+}
+
+func TestSend_AttachmentLinks_Translated(t *testing.T) {
+	tests := map[string]string{
+		"with placeholder":    "Download until {expires}:",
+		"without placeholder": "Download the attachments below:",
+	}
+
+	for name, translation := range tests {
+		f := pmail.Fake(
+			pmail.WithFakeTemplates(testTemplatesFS),
+			pmail.WithFakeMaxMessageSize(20*1024),
+			pmail.WithFakeAttachmentLinks(storagefake.New(), 24*time.Hour),
+			pmail.WithFakeDefaultLocale("nl"),
+			pmail.WithFakeTranslations(mailables.Messages{"nl": {"mail.attachment_links": translation}}),
+		)
+
+		if err := pmail.Send(withAttachments(map[string]int{"large.pdf": 30 * 1024})); err != nil {
+			t.Fatalf("%s: Send() error = %v", name, err)
+		}
+
+		body := f.LastCall().Input.Html.String()
+		want := strings.ReplaceAll(translation, "{expires}", time.Now().Add(24*time.Hour).Format("2006-01-02"))
+
+		if !strings.Contains(body, "<p>"+want+"</p>") || strings.Contains(body, "%!") {
+			t.Errorf("%s: Html = %q, want the translated intro %q", name, body, want)
+		}
+	}
+}
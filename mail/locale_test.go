@@ -0,0 +1,108 @@
+package mail_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gonstruct/providers/entities/mailables"
+	pmail "github.com/gonstruct/providers/mail"
+)
+
+type localizedMailable struct {
+	testMailable
+	locale string
+}
+
+func (m localizedMailable) Locale() string {
+	return m.locale
+}
+
+func invoiceMailable(locale string) localizedMailable {
+	return localizedMailable{
+		testMailable: testMailable{
+			envelope: mailables.Envelope{
+				Subject: "Invoice",
+				To:      mailables.Addresses("customer@example.com"),
+			},
+			content: mailables.Content{
+				View: "invoice.html",
+				With: map[string]any{
+					"name":  "Anna",
+					"due":   time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC),
+					"total": 1234.5,
+				},
+			},
+		},
+		locale: locale,
+	}
+}
+
+func TestSend_Localized(t *testing.T) {
+	catalog, err := mailables.LoadCatalog(os.DirFS("testdata"), "lang")
+	if err != nil {
+		t.Fatalf("LoadCatalog() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		locale  string
+		options []pmail.Option
+		want    string
+	}{
+		{
+			name:   "default locale",
+			locale: "",
+			want:   "<p>Hello Anna,</p><p>Due on March 5, 2026: € 1,234.50</p>\n",
+		},
+		{
+			// nl-BE falls back to the nl view and messages, missing messages fall back to en
+			name:   "mailable locale",
+			locale: "nl-BE",
+			want:   "<p lang=\"nl-BE\">Beste Anna,</p><p>Due on 5 maart 2026: € 1.234,50</p>\n",
+		},
+		{
+			name:    "send option overrides mailable",
+			locale:  "nl",
+			options: []pmail.Option{pmail.WithLocale("en")},
+			want:    "<p>Hello Anna,</p><p>Due on March 5, 2026: € 1,234.50</p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := pmail.Fake(
+				pmail.WithFakeTemplates(testTemplatesFS),
+				pmail.WithFakeDefaultEnvelope(mailables.Envelope{From: mailables.Address("billing@example.com", "")}),
+				pmail.WithFakeDefaultLocale("en"),
+				pmail.WithFakeFallbackLocales("en"),
+				pmail.WithFakeTranslations(catalog),
+			)
+
+			if err := pmail.Send(invoiceMailable(tt.locale), tt.options...); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+
+			if got := f.LastCall().Input.Html.String(); got != tt.want {
+				t.Errorf("Html = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalization_Translate(t *testing.T) {
+	localization := mailables.Localization{
+		Locale: "nl_be",
+		Catalog: mailables.Messages{
+			"nl": {"hello": "Hallo"},
+		},
+	}
+
+	if got := localization.Translate("hello"); got != "Hallo" {
+		t.Errorf("Translate(hello) = %q, want %q", got, "Hallo")
+	}
+
+	if got := localization.Translate("missing.key"); got != "missing.key" {
+		t.Errorf("Translate(missing.key) = %q, want the key", got)
+	}
+}
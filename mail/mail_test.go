@@ -1,8 +1,12 @@
 package mail_test
 
 import (
+	"context"
 	"embed"
+	"errors"
+	"fmt"
 	"net/mail"
+	"sync"
 	"testing"
 
 	"github.com/gonstruct/providers/adapters/mail/fake"
@@ -146,4 +150,285 @@ func TestEnvelope_Merge(t *testing.T) {
 	if len(merged.To) != 2 {
 		t.Errorf("To count = %d, want 2", len(merged.To))
 	}
+
+	// The base envelope must not be modified
+	if len(base.To) != 1 || base.Subject != "Original Subject" {
+		t.Errorf("base envelope was modified: %+v", base)
+	}
+}
+
+func TestEnvelope_Merge_DoesNotShareBackingArray(t *testing.T) {
+	to := make([]*mail.Address, 1, 4)
+	to[0] = mailables.Address("default@example.com", "")
+	base := mailables.Envelope{To: to}
+
+	first := base.Merge(mailables.Envelope{To: mailables.Addresses(mailables.Address("first@example.com", ""))})
+	second := base.Merge(mailables.Envelope{To: mailables.Addresses(mailables.Address("second@example.com", ""))})
+
+	if first.To[1].Address != "first@example.com" {
+		t.Errorf("first.To[1] = %q, want %q", first.To[1].Address, "first@example.com")
+	}
+
+	if second.To[1].Address != "second@example.com" {
+		t.Errorf("second.To[1] = %q, want %q", second.To[1].Address, "second@example.com")
+	}
+}
+
+func TestEnvelope_Merge_Strategies(t *testing.T) {
+	base := mailables.Envelope{
+		From: mailables.Address("noreply@example.com", "App"),
+		To:   mailables.Addresses(mailables.Address("default@example.com", "")),
+		Cc:   mailables.Addresses(mailables.Address("cc@example.com", "")),
+	}
+
+	override := mailables.Envelope{
+		From: mailables.Address("sales@example.com", "Sales"),
+		To:   mailables.Addresses(mailables.Address("user@example.com", "")),
+		Cc:   mailables.Addresses(mailables.Address("manager@example.com", "")),
+	}
+
+	t.Run("replace to, append cc", func(t *testing.T) {
+		merged := base.Merge(override, mailables.WithToStrategy(mailables.MergeReplace))
+
+		if len(merged.To) != 1 || merged.To[0].Address != "user@example.com" {
+			t.Errorf("To = %v, want only user@example.com", merged.To.String())
+		}
+
+		if len(merged.Cc) != 2 {
+			t.Errorf("Cc count = %d, want 2", len(merged.Cc))
+		}
+	})
+
+	t.Run("replace keeps base when override is empty", func(t *testing.T) {
+		merged := base.Merge(mailables.Envelope{}, mailables.WithRecipientStrategy(mailables.MergeReplace))
+
+		if len(merged.To) != 1 || merged.To[0].Address != "default@example.com" {
+			t.Errorf("To = %v, want only default@example.com", merged.To.String())
+		}
+	})
+
+	t.Run("from override", func(t *testing.T) {
+		merged := base.Merge(override)
+
+		if merged.From.Address != "sales@example.com" {
+			t.Errorf("From = %q, want %q", merged.From.Address, "sales@example.com")
+		}
+	})
+
+	t.Run("from keep base", func(t *testing.T) {
+		merged := base.Merge(override, mailables.WithFromPolicy(mailables.FromKeepBase))
+
+		if merged.From.Address != "noreply@example.com" {
+			t.Errorf("From = %q, want %q", merged.From.Address, "noreply@example.com")
+		}
+
+		if merged.ReplyTo != nil {
+			t.Errorf("ReplyTo = %v, want nil", merged.ReplyTo)
+		}
+	})
+
+	t.Run("from as reply-to", func(t *testing.T) {
+		merged := base.Merge(override, mailables.WithFromPolicy(mailables.FromAsReplyTo))
+
+		if merged.From.Address != "noreply@example.com" {
+			t.Errorf("From = %q, want %q", merged.From.Address, "noreply@example.com")
+		}
+
+		if merged.ReplyTo == nil || merged.ReplyTo.Address != "sales@example.com" {
+			t.Errorf("ReplyTo = %v, want sales@example.com", merged.ReplyTo)
+		}
+	})
+
+	t.Run("always bcc", func(t *testing.T) {
+		archive := mailables.Address("archive@example.com", "")
+		merged := base.Merge(override, mailables.WithAlwaysBcc(archive))
+		merged = merged.Merge(mailables.Envelope{}, mailables.WithAlwaysBcc(archive))
+
+		if len(merged.Bcc) != 1 || merged.Bcc[0].Address != "archive@example.com" {
+			t.Errorf("Bcc = %v, want only archive@example.com", merged.Bcc.String())
+		}
+	})
+}
+
+func TestSend_WithoutDefaultEnvelope(t *testing.T) {
+	f := pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS))
+
+	mailable := testMailable{
+		envelope: mailables.Envelope{
+			Subject: "No defaults",
+			From:    mailables.Address("noreply@test.com", ""),
+			To:      mailables.Addresses(mailables.Address("user@example.com", "")),
+		},
+		content: mailables.Content{View: "welcome.html"},
+	}
+
+	if err := pmail.Send(mailable); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	f.AssertSentTo(t, "user@example.com")
+}
+
+func TestSend_InvalidAddress(t *testing.T) {
+	f := pmail.Fake(
+		pmail.WithFakeTemplates(testTemplatesFS),
+		pmail.WithFakeDefaultEnvelope(mailables.Envelope{
+			From: mailables.Address("noreply@test.com", "Test App"),
+		}),
+	)
+
+	err := pmail.Send(testMailable{
+		envelope: mailables.Envelope{
+			Subject: "Invalid",
+			To:      mailables.Addresses("user@example.com", "not-an-address"),
+			Cc:      mailables.Addresses("also bad"),
+		},
+		content: mailables.Content{View: "welcome.html"},
+	})
+
+	if !errors.Is(err, pmail.ErrInvalidAddress) {
+		t.Fatalf("Send() error = %v, want ErrInvalidAddress", err)
+	}
+
+	var validation *mailables.ValidationError
+	if !errors.As(err, &validation) || len(validation.Errors) != 2 {
+		t.Errorf("Send() error = %v, want a ValidationError with 2 addresses", err)
+	}
+
+	f.AssertNothingSent(t)
+}
+
+func TestSend_DefaultEnvelopeIsNotMutated(t *testing.T) {
+	const senders = 50
+
+	f := pmail.Fake(
+		pmail.WithFakeTemplates(testTemplatesFS),
+		pmail.WithFakeDefaultEnvelope(mailables.Envelope{
+			From: mailables.Address("noreply@test.com", "Test App"),
+			Bcc:  mailables.Addresses(mailables.Address("audit@test.com", "")),
+		}),
+		pmail.WithFakeEnvelopeMerge(mailables.WithAlwaysBcc(mailables.Address("archive@test.com", ""))),
+	)
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, senders)
+
+	for i := range senders {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			errs <- pmail.Send(testMailable{
+				envelope: mailables.Envelope{
+					Subject: fmt.Sprintf("Mail %d", i),
+					To:      mailables.Addresses(mailables.Address(fmt.Sprintf("user%d@example.com", i), "")),
+					Bcc:     mailables.Addresses(mailables.Address(fmt.Sprintf("bcc%d@example.com", i), "")),
+				},
+				content: mailables.Content{View: "welcome.html", With: map[string]any{"name": i}},
+			})
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	f.AssertSentCount(t, senders)
+
+	for _, call := range f.Calls {
+		envelope := call.Input.Envelope
+
+		if len(envelope.To) != 1 {
+			t.Errorf("%s: To count = %d, want 1", envelope.Subject, len(envelope.To))
+		}
+
+		if len(envelope.Bcc) != 3 {
+			t.Errorf("%s: Bcc = %v, want audit, own and archive address", envelope.Subject, envelope.Bcc.String())
+		}
+	}
+}
+
+type namedProcessor string
+
+func (p namedProcessor) Process(_ context.Context, _ mailables.Envelope, message []byte) ([]byte, error) {
+	return message, nil
+}
+
+type processedMailable struct {
+	testMailable
+}
+
+func (processedMailable) Processors() []mailables.MessageProcessor {
+	return []mailables.MessageProcessor{namedProcessor("mailable")}
+}
+
+func TestSend_MessageProcessors(t *testing.T) {
+	f := pmail.Fake(
+		pmail.WithFakeTemplates(testTemplatesFS),
+		pmail.WithFakeMessageProcessors(namedProcessor("mailer")),
+	)
+
+	mailable := processedMailable{testMailable{
+		envelope: mailables.Envelope{
+			From:    mailables.Address("noreply@test.com", ""),
+			To:      mailables.Addresses("user@example.com"),
+			Subject: "Signed",
+		},
+		content: mailables.Content{View: "welcome.html"},
+	}}
+
+	if err := pmail.Send(mailable); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	got := f.LastCall().Input.Processors
+	if len(got) != 2 || got[0] != namedProcessor("mailer") || got[1] != namedProcessor("mailable") {
+		t.Errorf("Processors = %v, want [mailer mailable]", got)
+	}
+}
+
+func TestSend_FakeInspection(t *testing.T) {
+	f := pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS))
+
+	mailable := testMailable{
+		envelope: mailables.Envelope{
+			From:    mailables.Address("billing@test.com", ""),
+			To:      mailables.Addresses("user@example.com"),
+			Cc:      mailables.Addresses("finance@example.com"),
+			Bcc:     mailables.Addresses("archive@example.com"),
+			ReplyTo: mailables.Address("support@test.com", ""),
+			Subject: "Invoice",
+		},
+		content: mailables.Content{View: "welcome.html", With: map[string]any{"name": "Alice"}},
+		attachments: mailables.Attachments(mailables.Attachment(
+			mailables.WithName("invoice.pdf"),
+			mailables.WithContent([]byte("%PDF")),
+		)),
+	}
+
+	if err := pmail.Send(mailable); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	f.AssertSentCc(t, "finance@example.com")
+	f.AssertSentBcc(t, "archive@example.com")
+	f.AssertSentReplyTo(t, "support@test.com")
+	f.AssertSentWithAttachment(t, "invoice.pdf")
+	f.AssertSentWithAttachmentMatching(t, func(attachment fake.Attachment) bool {
+		return attachment.Mime == "application/pdf" && string(attachment.Content) == "%PDF"
+	})
+	f.AssertSentHTMLContains(t, "Hello Alice")
+	f.AssertSentHTMLNotContains(t, "Hello Bob")
+	f.AssertSentMatching(t, func(call fake.SendCall) bool { return call.Subject == "Invoice" })
+	f.AssertNotSentMatching(t, func(call fake.SendCall) bool { return call.Subject == "Receipt" })
+
+	fake.AssertSentMailable(t, f, func(m testMailable) bool { return m.envelope.Subject == "Invoice" })
+	fake.AssertNotSentMailable[processedMailable](t, f)
 }
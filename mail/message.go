@@ -0,0 +1,131 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"io"
+	netmail "net/mail"
+
+	"github.com/gonstruct/providers/entities"
+	"gopkg.in/gomail.v2"
+)
+
+// Validate checks that the input has the fields every transport requires.
+func Validate(input entities.MailInput) error {
+	switch {
+	case input.Envelope.Subject == "":
+		return Err("validate", ErrNoSubject)
+	case input.Envelope.From == nil:
+		return Err("validate", ErrNoSender)
+	case len(input.Envelope.To) == 0:
+		return Err("validate", ErrNoRecipients)
+	}
+
+	return nil
+}
+
+// NewMessage builds the MIME message for the given input. Adapters that send raw
+// messages use it so every transport produces the same message layout.
+//
+// Calendar attachments are added as a text/calendar alternative of the HTML body
+// and as an application/ics file, the layout mail clients expect for invitations.
+//
+// Attachment content is streamed into the message when it is written, using ctx to open lazy attachments.
+func NewMessage(ctx context.Context, input entities.MailInput) (*gomail.Message, error) {
+	if err := Validate(input); err != nil {
+		return nil, err
+	}
+
+	message := gomail.NewMessage()
+	message.SetHeader("Subject", input.Envelope.Subject)
+	message.SetHeader("From", input.Envelope.From.String())
+	message.SetHeader("To", input.Envelope.To.String()...)
+
+	if input.Envelope.ReplyTo != nil {
+		message.SetHeader("Reply-To", input.Envelope.ReplyTo.String())
+	}
+
+	if len(input.Envelope.Cc) > 0 {
+		message.SetHeader("Cc", input.Envelope.Cc.String()...)
+	}
+
+	if len(input.Envelope.Bcc) > 0 {
+		message.SetHeader("Bcc", input.Envelope.Bcc.String()...)
+	}
+
+	for name, value := range Headers(input) {
+		message.SetHeader(name, value)
+	}
+
+	message.SetBody("text/html", input.Html.String())
+
+	for _, attachment := range input.Attachments {
+		contentType := attachment.Mime
+
+		if attachment.IsCalendar() {
+			content, err := attachment.ReadContent(ctx)
+			if err != nil {
+				return nil, Err("read attachment", err)
+			}
+
+			message.AddAlternative(attachment.Mime, string(content))
+
+			contentType = "application/ics"
+		}
+
+		message.Attach(attachment.Name,
+			gomail.SetHeader(map[string][]string{
+				"Content-Type": {contentType},
+			}),
+			gomail.SetCopyFunc(func(w io.Writer) error {
+				reader, err := attachment.Open(ctx)
+				if err != nil {
+					return err
+				}
+				defer reader.Close()
+
+				_, err = io.Copy(w, reader)
+
+				return err
+			}),
+		)
+	}
+
+	return message, nil
+}
+
+// Raw builds the MIME message for the given input and applies its processors.
+func Raw(ctx context.Context, input entities.MailInput) ([]byte, error) {
+	message, err := NewMessage(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw bytes.Buffer
+	if _, err := message.WriteTo(&raw); err != nil {
+		return nil, Err("build raw message", err)
+	}
+
+	data := raw.Bytes()
+
+	for _, processor := range input.Processors {
+		if data, err = processor.Process(ctx, input.Envelope, data); err != nil {
+			return nil, Err("process message", err)
+		}
+	}
+
+	return data, nil
+}
+
+// Recipients returns the addresses of every To, Cc and Bcc recipient, as used in the SMTP envelope.
+func Recipients(input entities.MailInput) []string {
+	var recipients []string
+
+	for _, list := range [][]*netmail.Address{input.Envelope.To, input.Envelope.Cc, input.Envelope.Bcc} {
+		for _, address := range list {
+			recipients = append(recipients, address.Address)
+		}
+	}
+
+	return recipients
+}
@@ -0,0 +1,224 @@
+package mail_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gonstruct/providers/adapters/storage/local"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/entities/mailables"
+	pmail "github.com/gonstruct/providers/mail"
+	"github.com/gonstruct/providers/storage"
+)
+
+func TestNewMessage_Validation(t *testing.T) {
+	_, err := pmail.NewMessage(context.Background(), entities.MailInput{})
+	if !errors.Is(err, pmail.ErrNoSubject) {
+		t.Errorf("NewMessage() error = %v, want ErrNoSubject", err)
+	}
+}
+
+func TestNewMessage_CalendarInvite(t *testing.T) {
+	invite, err := mailables.Calendar{
+		Events: []mailables.Event{{
+			UID:       "meeting-1@example.com",
+			Summary:   "Kick-off",
+			Start:     time.Date(2026, time.October, 20, 9, 0, 0, 0, time.UTC),
+			End:       time.Date(2026, time.October, 20, 10, 0, 0, 0, time.UTC),
+			Organizer: mailables.Address("organizer@example.com", ""),
+		}},
+	}.Attachment()
+	if err != nil {
+		t.Fatalf("Attachment() error = %v", err)
+	}
+
+	input := entities.MailInput{
+		Envelope: mailables.Envelope{
+			Subject: "Invitation",
+			From:    mailables.Address("organizer@example.com", ""),
+			To:      mailables.Addresses("attendee@example.com"),
+		},
+		Attachments: mailables.Attachments(invite),
+		Html:        *bytes.NewBufferString("<p>You are invited</p>"),
+	}
+
+	message, err := pmail.NewMessage(context.Background(), input)
+	if err != nil {
+		t.Fatalf("NewMessage() error = %v", err)
+	}
+
+	var raw bytes.Buffer
+	if _, err := message.WriteTo(&raw); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+
+	parsed, err := mail.ReadMessage(&raw)
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+
+	// multipart/mixed [ multipart/alternative [ text/html, text/calendar ], application/ics ]
+	mixed := readParts(t, parsed.Header.Get("Content-Type"), parsed.Body)
+	if len(mixed) != 2 {
+		t.Fatalf("mixed part count = %d, want 2", len(mixed))
+	}
+
+	alternative := readParts(t, mixed[0].Header.Get("Content-Type"), bytes.NewReader(mixed[0].body))
+	if len(alternative) != 2 {
+		t.Fatalf("alternative part count = %d, want 2", len(alternative))
+	}
+
+	if contentType := alternative[0].Header.Get("Content-Type"); !strings.HasPrefix(contentType, "text/html") {
+		t.Errorf("first alternative = %q, want text/html", contentType)
+	}
+
+	if contentType := alternative[1].Header.Get("Content-Type"); !strings.HasPrefix(contentType, "text/calendar; method=REQUEST") {
+		t.Errorf("second alternative = %q, want text/calendar; method=REQUEST", contentType)
+	}
+
+	if contentType := mixed[1].Header.Get("Content-Type"); contentType != "application/ics" {
+		t.Errorf("attachment = %q, want application/ics", contentType)
+	}
+}
+
+type part struct {
+	multipart.Part
+	body []byte
+}
+
+func readParts(t *testing.T, contentType string, body io.Reader) []part {
+	t.Helper()
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("ParseMediaType(%q) error = %v", contentType, err)
+	}
+
+	reader := multipart.NewReader(body, params["boundary"])
+
+	var parts []part
+
+	for {
+		p, err := reader.NextRawPart()
+		if errors.Is(err, io.EOF) {
+			return parts
+		}
+
+		if err != nil {
+			t.Fatalf("NextRawPart() error = %v", err)
+		}
+
+		content, err := io.ReadAll(p)
+		if err != nil {
+			t.Fatalf("ReadAll() error = %v", err)
+		}
+
+		parts = append(parts, part{Part: *p, body: content})
+	}
+}
+
+func TestNewMessage_StreamsLazyAttachments(t *testing.T) {
+	ctx := context.Background()
+	disk := local.NewAdapter(t.TempDir())
+
+	if err := disk.Put(ctx, "invoices/2026-001.pdf", []byte("%PDF-stored")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "terms.txt")
+	if err := os.WriteFile(path, []byte("local terms"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	opened := 0
+
+	attachments, err := mailables.Attachments(
+		mailables.Attachment(mailables.WithStorage(disk, "invoices/2026-001.pdf")),
+		mailables.Attachment(mailables.WithPath(path)),
+		mailables.Attachment(mailables.WithName("report.csv"), mailables.WithReader(func() (io.ReadCloser, error) {
+			opened++
+
+			return io.NopCloser(strings.NewReader("a,b\n1,2\n")), nil
+		})),
+	).Resolve(ctx)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	if opened != 0 {
+		t.Error("Resolve() should not open the attachment content")
+	}
+
+	want := []struct{ name, mime, content string }{
+		{"2026-001.pdf", "application/pdf", "%PDF-stored"},
+		{"terms.txt", "text/plain", "local terms"},
+		{"report.csv", "text/csv", "a,b\n1,2\n"},
+	}
+
+	for i, attachment := range attachments {
+		if attachment.Name != want[i].name || attachment.Mime != want[i].mime {
+			t.Errorf("attachment %d = %q (%s), want %q (%s)", i, attachment.Name, attachment.Mime, want[i].name, want[i].mime)
+		}
+	}
+
+	message, err := pmail.NewMessage(ctx, entities.MailInput{
+		Envelope: mailables.Envelope{
+			Subject: "Your invoice",
+			From:    mailables.Address("billing@example.com", ""),
+			To:      mailables.Addresses("customer@example.com"),
+		},
+		Attachments: attachments,
+		Html:        *bytes.NewBufferString("<p>Attached</p>"),
+	})
+	if err != nil {
+		t.Fatalf("NewMessage() error = %v", err)
+	}
+
+	var raw bytes.Buffer
+	if _, err := message.WriteTo(&raw); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+
+	parsed, err := mail.ReadMessage(&raw)
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+
+	parts := readParts(t, parsed.Header.Get("Content-Type"), parsed.Body)
+	if len(parts) != 4 {
+		t.Fatalf("part count = %d, want 4", len(parts))
+	}
+
+	for i, part := range parts[1:] {
+		content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(part.body), "\r\n", ""))
+		if err != nil {
+			t.Fatalf("attachment %d: decode error = %v", i, err)
+		}
+
+		if string(content) != want[i].content {
+			t.Errorf("attachment %d content = %q, want %q", i, content, want[i].content)
+		}
+	}
+}
+
+func TestNewMessage_MissingStoredAttachment(t *testing.T) {
+	disk := local.NewAdapter(t.TempDir())
+
+	_, err := mailables.Attachments(
+		mailables.Attachment(mailables.WithStorage(disk, "missing.pdf")),
+	).Resolve(context.Background())
+	if !errors.Is(err, storage.ErrFileNotFound) {
+		t.Errorf("Resolve() error = %v, want ErrFileNotFound", err)
+	}
+}
@@ -3,6 +3,7 @@ package mail
 import (
 	"context"
 	"embed"
+	"time"
 
 	"github.com/gonstruct/providers/contracts"
 	"github.com/gonstruct/providers/entities/mailables"
@@ -13,6 +14,21 @@ type options struct {
 	Adapter         contracts.Mail
 	Templates       embed.FS
 	DefaultEnvelope *mailables.Envelope
+	MergeOptions    []mailables.MergeOption
+	Locale          string
+	Localization    mailables.Localization
+	Concurrency     int
+	BatchSize       int
+	Processors      []mailables.MessageProcessor
+
+	MaxMessageSize    int64
+	MaxAttachmentSize int64
+	AttachmentLinks   *attachmentLinks
+
+	SendAt         time.Time
+	IdempotencyKey string
+	ScheduleStore  contracts.MailScheduleStore
+	ScheduleErrors func(error)
 }
 
 type Option func(*options)
@@ -23,6 +39,17 @@ func apply(optionSlice ...Option) *options {
 		Adapter:         globalProvider.adapter,
 		Templates:       globalProvider.templates,
 		DefaultEnvelope: globalProvider.defaultEnvelope,
+		MergeOptions:    globalProvider.mergeOptions,
+		Localization:    globalProvider.localization,
+		Concurrency:     DefaultConcurrency,
+		BatchSize:       DefaultBatchSize,
+		Processors:      globalProvider.processors,
+
+		MaxMessageSize:    globalProvider.maxMessageSize,
+		MaxAttachmentSize: globalProvider.maxAttachmentSize,
+		AttachmentLinks:   globalProvider.attachmentLinks,
+
+		ScheduleStore: globalProvider.scheduleStore,
 	}
 
 	for _, option := range optionSlice {
@@ -43,3 +70,33 @@ func WithAdapter(adapter contracts.Mail) Option {
 		options.Adapter = adapter
 	}
 }
+
+// WithLocale renders the mailable in the given locale, overriding the locale of the mailable.
+func WithLocale(locale string) Option {
+	return func(options *options) {
+		options.Locale = locale
+	}
+}
+
+// SendAt delivers the message at the given time instead of now, see Schedule.
+// Times that have passed send the message immediately.
+func SendAt(at time.Time) Option {
+	return func(options *options) {
+		options.SendAt = at
+	}
+}
+
+// WithIdempotencyKey identifies the message, so scheduling it again replaces the scheduled message
+// instead of adding one. Adapters whose provider deduplicates requests send the key along.
+func WithIdempotencyKey(key string) Option {
+	return func(options *options) {
+		options.IdempotencyKey = key
+	}
+}
+
+// WithScheduleErrorHandler receives the errors of RunScheduler, which keeps running after them.
+func WithScheduleErrorHandler(handler func(error)) Option {
+	return func(options *options) {
+		options.ScheduleErrors = handler
+	}
+}
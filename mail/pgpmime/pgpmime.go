@@ -0,0 +1,196 @@
+// Package pgpmime signs and encrypts outgoing mail with OpenPGP/MIME (RFC 3156).
+//
+// A Processor is a mailables.MessageProcessor and is configured on the mailer with
+// mail.WithMessageProcessors or returned by a mailable's Processors method:
+//
+//	keyring, _ := openpgp.ReadArmoredKeyRing(file)
+//	processor := pgpmime.New(pgpmime.WithSigner(sender), pgpmime.WithEncryption(pgpmime.Keyring(keyring)))
+//
+//	mail.Adapt(adapter, mail.WithMessageProcessors(processor))
+package pgpmime
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	netmail "net/mail"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/gonstruct/providers/entities/mailables"
+	"github.com/gonstruct/providers/mail/internal/mimepart"
+)
+
+var (
+	ErrNoKey         = errors.New("no OpenPGP key for recipient")
+	ErrNotConfigured = errors.New("neither signing nor encryption is configured")
+	ErrEncryptedBcc  = errors.New("encrypted messages cannot have Bcc recipients")
+)
+
+// KeyResolver looks up the public key of a recipient to encrypt a message for.
+type KeyResolver interface {
+	Key(ctx context.Context, address string) (*openpgp.Entity, error)
+}
+
+// Keyring is a KeyResolver that finds keys by the email address of their identities.
+type Keyring openpgp.EntityList
+
+func (keyring Keyring) Key(_ context.Context, address string) (*openpgp.Entity, error) {
+	for _, entity := range keyring {
+		for _, identity := range entity.Identities {
+			if identity.UserId != nil && strings.EqualFold(identity.UserId.Email, address) {
+				return entity, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNoKey, address)
+}
+
+// Processor signs and/or encrypts messages. When both are configured the message is
+// signed first and the signed message is encrypted, as described in RFC 3156 section 6.1.
+type Processor struct {
+	signer     *openpgp.Entity
+	recipients KeyResolver
+	config     *packet.Config
+}
+
+type Option func(*Processor)
+
+// New creates an OpenPGP/MIME processor that uses SHA-256 signatures and AES-256 encryption.
+func New(options ...Option) *Processor {
+	processor := &Processor{
+		config: &packet.Config{
+			DefaultHash:   crypto.SHA256,
+			DefaultCipher: packet.CipherAES256,
+		},
+	}
+
+	for _, option := range options {
+		option(processor)
+	}
+
+	return processor
+}
+
+// WithSigner signs messages with the private key of the entity.
+func WithSigner(signer *openpgp.Entity) Option {
+	return func(p *Processor) {
+		p.signer = signer
+	}
+}
+
+// WithEncryption encrypts messages for every recipient with the keys of the resolver.
+// When a signer is configured the message is also encrypted for the signer, so the sent copy can be read.
+// Messages with Bcc recipients fail with ErrEncryptedBcc, the key IDs of the encrypted message would
+// reveal them to the other recipients.
+func WithEncryption(recipients KeyResolver) Option {
+	return func(p *Processor) {
+		p.recipients = recipients
+	}
+}
+
+func (p *Processor) Process(ctx context.Context, envelope mailables.Envelope, message []byte) ([]byte, error) {
+	if p.signer == nil && p.recipients == nil {
+		return nil, ErrNotConfigured
+	}
+
+	outer, entity, err := mimepart.Split(message)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.signer != nil {
+		if entity, err = p.sign(entity); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.recipients != nil {
+		if entity, err = p.encrypt(ctx, envelope, entity); err != nil {
+			return nil, err
+		}
+	}
+
+	return mimepart.Join(outer, entity), nil
+}
+
+func (p *Processor) sign(entity []byte) ([]byte, error) {
+	var signature bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&signature, p.signer, bytes.NewReader(entity), p.config); err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+
+	boundary, body := mimepart.Multipart(entity, mimepart.Entity(
+		mimepart.Canonicalize(signature.Bytes()),
+		`Content-Type: application/pgp-signature; name="signature.asc"`,
+		`Content-Disposition: attachment; filename="signature.asc"`,
+	))
+
+	return mimepart.Entity(body,
+		fmt.Sprintf(`Content-Type: multipart/signed; protocol="application/pgp-signature"; micalg=pgp-sha256; boundary="%s"`, boundary),
+	), nil
+}
+
+func (p *Processor) encrypt(ctx context.Context, envelope mailables.Envelope, entity []byte) ([]byte, error) {
+	if len(envelope.Bcc) > 0 {
+		return nil, ErrEncryptedBcc
+	}
+
+	var keys []*openpgp.Entity
+
+	for _, list := range [][]*netmail.Address{envelope.To, envelope.Cc} {
+		for _, recipient := range list {
+			key, err := p.recipients.Key(ctx, recipient.Address)
+			if err != nil {
+				return nil, err
+			}
+
+			keys = append(keys, key)
+		}
+	}
+
+	if p.signer != nil {
+		keys = append(keys, p.signer)
+	}
+
+	var encrypted bytes.Buffer
+
+	armored, err := armor.Encode(&encrypted, "PGP MESSAGE", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %w", err)
+	}
+
+	plaintext, err := openpgp.Encrypt(armored, keys, nil, nil, p.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %w", err)
+	}
+
+	if _, err := plaintext.Write(entity); err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %w", err)
+	}
+
+	if err := plaintext.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %w", err)
+	}
+
+	if err := armored.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %w", err)
+	}
+
+	boundary, body := mimepart.Multipart(
+		mimepart.Entity([]byte("Version: 1\r\n"), "Content-Type: application/pgp-encrypted"),
+		mimepart.Entity(mimepart.Canonicalize(encrypted.Bytes()),
+			`Content-Type: application/octet-stream; name="encrypted.asc"`,
+			`Content-Disposition: inline; filename="encrypted.asc"`,
+		),
+	)
+
+	return mimepart.Entity(body,
+		fmt.Sprintf(`Content-Type: multipart/encrypted; protocol="application/pgp-encrypted"; boundary="%s"`, boundary),
+	), nil
+}
@@ -0,0 +1,148 @@
+package pgpmime_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/entities/mailables"
+	pmail "github.com/gonstruct/providers/mail"
+	"github.com/gonstruct/providers/mail/pgpmime"
+)
+
+func entity(t *testing.T, email string) *openpgp.Entity {
+	t.Helper()
+
+	key, err := openpgp.NewEntity("", "", email, nil)
+	if err != nil {
+		t.Fatalf("NewEntity() error = %v", err)
+	}
+
+	return key
+}
+
+func input(processors ...mailables.MessageProcessor) entities.MailInput {
+	return entities.MailInput{
+		Envelope: mailables.Envelope{
+			From:    mailables.Address("doctor@example.com", ""),
+			To:      mailables.Addresses("patient@example.com"),
+			Subject: "Lab results",
+		},
+		Html:       *bytes.NewBufferString("<p>Your results are ready</p>"),
+		Processors: processors,
+	}
+}
+
+// parts returns the raw parts of a multipart entity, the first part exactly as it was signed.
+func parts(t *testing.T, entity []byte, wantType string) [][]byte {
+	t.Helper()
+
+	message, err := mail.ReadMessage(bytes.NewReader(entity))
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != wantType {
+		t.Fatalf("Content-Type = %q, want %s", message.Header.Get("Content-Type"), wantType)
+	}
+
+	body, _ := io.ReadAll(message.Body)
+
+	delimiter := []byte("--" + params["boundary"] + "\r\n")
+	start := bytes.Index(body, delimiter) + len(delimiter)
+	end := bytes.Index(body, []byte("\r\n--"+params["boundary"]))
+	result := [][]byte{body[start:end]}
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	if _, err := reader.NextPart(); err != nil {
+		t.Fatalf("NextPart() error = %v", err)
+	}
+
+	part, err := reader.NextPart()
+	if err != nil {
+		t.Fatalf("NextPart() error = %v", err)
+	}
+
+	second, _ := io.ReadAll(part)
+
+	return append(result, second)
+}
+
+func TestProcess_SignAndEncrypt(t *testing.T) {
+	sender := entity(t, "doctor@example.com")
+	recipient := entity(t, "patient@example.com")
+
+	processor := pgpmime.New(
+		pgpmime.WithSigner(sender),
+		pgpmime.WithEncryption(pgpmime.Keyring{recipient}),
+	)
+
+	raw, err := pmail.Raw(context.Background(), input(processor))
+	if err != nil {
+		t.Fatalf("Raw() error = %v", err)
+	}
+
+	encrypted := parts(t, raw, "multipart/encrypted")
+	if !bytes.Contains(encrypted[0], []byte("Version: 1")) {
+		t.Errorf("control part = %q, want Version: 1", encrypted[0])
+	}
+
+	block, err := armor.Decode(bytes.NewReader(encrypted[1]))
+	if err != nil {
+		t.Fatalf("armor.Decode() error = %v", err)
+	}
+
+	details, err := openpgp.ReadMessage(block.Body, openpgp.EntityList{recipient}, nil, nil)
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+
+	signed, err := io.ReadAll(details.UnverifiedBody)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	signedParts := parts(t, signed, "multipart/signed")
+
+	if _, err := openpgp.CheckArmoredDetachedSignature(openpgp.EntityList{sender}, bytes.NewReader(signedParts[0]), bytes.NewReader(signedParts[1]), nil); err != nil {
+		t.Errorf("CheckArmoredDetachedSignature() error = %v", err)
+	}
+
+	if !strings.Contains(string(signedParts[0]), "Your results are ready") {
+		t.Errorf("signed content = %q, want the HTML body", signedParts[0])
+	}
+}
+
+func TestProcess_MissingRecipientKey(t *testing.T) {
+	processor := pgpmime.New(pgpmime.WithEncryption(pgpmime.Keyring{}))
+
+	_, err := pmail.Raw(context.Background(), input(processor))
+	if !errors.Is(err, pgpmime.ErrNoKey) {
+		t.Errorf("Raw() error = %v, want ErrNoKey", err)
+	}
+}
+
+func TestProcess_EncryptedBcc(t *testing.T) {
+	processor := pgpmime.New(pgpmime.WithEncryption(pgpmime.Keyring{
+		entity(t, "patient@example.com"),
+		entity(t, "insurer@example.com"),
+	}))
+
+	message := input(processor)
+	message.Envelope.Bcc = mailables.Addresses("insurer@example.com")
+
+	_, err := pmail.Raw(context.Background(), message)
+	if !errors.Is(err, pgpmime.ErrEncryptedBcc) {
+		t.Errorf("Raw() error = %v, want ErrEncryptedBcc", err)
+	}
+}
@@ -0,0 +1,117 @@
+package mail
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/entities/mailables"
+	"github.com/google/uuid"
+)
+
+// SendWithReceipt sends the mailable like Send and returns the receipt of the message, with its
+// Message-ID to thread replies with and the ID the provider assigned to it.
+//
+// Adapters implementing contracts.ReceiptMail report the provider ID and the rejected recipients,
+// for other adapters every recipient is reported as accepted. With SendAt the message is scheduled
+// and the receipt holds the ScheduleID instead.
+func SendWithReceipt(mailable contracts.Mailable, optionSlice ...Option) (*entities.MailReceipt, error) {
+	options := apply(optionSlice...)
+
+	input, err := build(mailable, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return send(options, input)
+}
+
+// NewReceipt returns the receipt of an input the transport accepted for every recipient.
+// Adapters use it to implement contracts.ReceiptMail.
+func NewReceipt(input entities.MailInput, providerID string) *entities.MailReceipt {
+	return &entities.MailReceipt{
+		MessageID:  input.MessageID,
+		ProviderID: providerID,
+		Accepted:   Recipients(input),
+		SentAt:     time.Now(),
+	}
+}
+
+// Headers returns the custom headers of the input with the Message-ID, In-Reply-To
+// and References headers, for adapters that set the headers of a message themselves.
+func Headers(input entities.MailInput) map[string]string {
+	headers := maps.Clone(input.Envelope.Headers)
+	if headers == nil {
+		headers = make(map[string]string, 3)
+	}
+
+	if input.MessageID != "" {
+		headers["Message-ID"] = mailables.MessageID(input.MessageID)
+	}
+
+	if input.Envelope.InReplyTo != "" {
+		headers["In-Reply-To"] = mailables.MessageID(input.Envelope.InReplyTo)
+	}
+
+	if len(input.Envelope.References) > 0 {
+		references := make([]string, len(input.Envelope.References))
+		for i, reference := range input.Envelope.References {
+			references[i] = mailables.MessageID(reference)
+		}
+
+		headers["References"] = strings.Join(references, " ")
+	}
+
+	return headers
+}
+
+// send schedules the input when SendAt is set and delivers it otherwise.
+func send(options *options, input entities.MailInput) (*entities.MailReceipt, error) {
+	if scheduled(options) {
+		id, err := schedule(options, input)
+		if err != nil {
+			return nil, err
+		}
+
+		return &entities.MailReceipt{MessageID: input.MessageID, SentAt: options.SendAt, ScheduleID: id}, nil
+	}
+
+	return deliver(options.Context, options.Adapter, input)
+}
+
+// deliver sends the input and returns its receipt, from the adapter when it reports one.
+func deliver(ctx context.Context, adapter contracts.Mail, input entities.MailInput) (*entities.MailReceipt, error) {
+	if receipts, ok := adapter.(contracts.ReceiptMail); ok {
+		return receipts.SendWithReceipt(ctx, input)
+	}
+
+	if err := adapter.Send(ctx, input); err != nil {
+		return nil, err
+	}
+
+	return NewReceipt(input, ""), nil
+}
+
+// newMessageID generates a Message-ID on the domain of the sender. Messages with an idempotency
+// key get the same Message-ID on every attempt, so receivers can recognize duplicates.
+func newMessageID(envelope mailables.Envelope, idempotencyKey string) string {
+	domain := "localhost"
+	if envelope.From != nil {
+		if at := strings.LastIndex(envelope.From.Address, "@"); at >= 0 {
+			domain = envelope.From.Address[at+1:]
+		}
+	}
+
+	id := uuid.NewString()
+	if idempotencyKey != "" {
+		sum := sha256.Sum256([]byte(idempotencyKey))
+		id = hex.EncodeToString(sum[:16])
+	}
+
+	return "<" + id + "@" + domain + ">"
+}
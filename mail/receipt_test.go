@@ -0,0 +1,155 @@
+package mail_test
+
+import (
+	"bytes"
+	"context"
+	netmail "net/mail"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/entities/mailables"
+	pmail "github.com/gonstruct/providers/mail"
+)
+
+func TestSendWithReceipt(t *testing.T) {
+	f := pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS))
+
+	receipt, err := pmail.SendWithReceipt(withAttachments(nil))
+	if err != nil {
+		t.Fatalf("SendWithReceipt() error = %v", err)
+	}
+
+	if !regexp.MustCompile(`^<[0-9a-f-]+@test\.com>$`).MatchString(receipt.MessageID) {
+		t.Errorf("MessageID = %q, want an ID on the domain of the sender", receipt.MessageID)
+	}
+
+	if call := f.LastCall(); call.MessageID != receipt.MessageID {
+		t.Errorf("sent Message-ID = %q, want %q", call.MessageID, receipt.MessageID)
+	}
+
+	if !slices.Equal(receipt.Accepted, []string{"user@example.com"}) || receipt.SentAt.IsZero() {
+		t.Errorf("receipt = %+v, want the recipient accepted", receipt)
+	}
+}
+
+func TestSend_IdempotentMessageID(t *testing.T) {
+	f := pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS))
+
+	for range 2 {
+		if err := pmail.Send(withAttachments(nil), pmail.WithIdempotencyKey("invoice-42")); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	if err := pmail.Send(withAttachments(nil)); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if f.Calls[0].MessageID != f.Calls[1].MessageID {
+		t.Errorf("Message-IDs = %q, %q, want the same ID for the same idempotency key", f.Calls[0].MessageID, f.Calls[1].MessageID)
+	}
+
+	if f.Calls[2].MessageID == f.Calls[0].MessageID {
+		t.Errorf("Message-ID = %q, want a new ID without idempotency key", f.Calls[2].MessageID)
+	}
+}
+
+func TestSend_Threaded(t *testing.T) {
+	f := pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS))
+
+	reply := withAttachments(nil)
+	reply.envelope = reply.envelope.Threaded("order-1@test.com", "<thread-1@test.com>")
+
+	if err := pmail.Send(reply); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	f.AssertSentInReplyTo(t, "<order-1@test.com>")
+
+	call := f.LastCall()
+	if want := []string{"<thread-1@test.com>", "<order-1@test.com>"}; !slices.Equal(call.References, want) {
+		t.Errorf("References = %v, want %v", call.References, want)
+	}
+
+	message, err := pmail.NewMessage(context.Background(), call.Input)
+	if err != nil {
+		t.Fatalf("NewMessage() error = %v", err)
+	}
+
+	var raw bytes.Buffer
+	if _, err := message.WriteTo(&raw); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+
+	parsed, err := netmail.ReadMessage(&raw)
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+
+	headers := map[string]string{
+		"Message-Id":  call.MessageID,
+		"In-Reply-To": "<order-1@test.com>",
+		"References":  "<thread-1@test.com> <order-1@test.com>",
+	}
+
+	for name, want := range headers {
+		if got := parsed.Header.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestEnvelope_Merge_Threading(t *testing.T) {
+	base := mailables.Envelope{InReplyTo: "<a@test.com>", References: []string{"<a@test.com>"}}
+
+	merged := base.Merge(mailables.Envelope{})
+	if merged.InReplyTo != "<a@test.com>" || !slices.Equal(merged.References, base.References) {
+		t.Errorf("merged = %+v, want the threading headers of the base", merged)
+	}
+
+	merged = base.Merge(mailables.Envelope{}.Threaded("<b@test.com>", "<a@test.com>"))
+	if merged.InReplyTo != "<b@test.com>" || !slices.Equal(merged.References, []string{"<a@test.com>", "<b@test.com>"}) {
+		t.Errorf("merged = %+v, want the threading headers of the override", merged)
+	}
+}
+
+func TestSendMany_Receipts(t *testing.T) {
+	pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS))
+
+	result := pmail.SendMany(newsletter(), recipients("anna@example.com", "bob@example.com"), pmail.WithIdempotencyKey("news-1"))
+	if err := result.Err(); err != nil {
+		t.Fatalf("SendMany() error = %v", err)
+	}
+
+	var ids []string
+
+	for _, r := range result.Results {
+		if r.Receipt == nil || !slices.Equal(r.Receipt.Accepted, []string{r.Recipient.Address.Address}) {
+			t.Fatalf("Receipt = %+v, want the recipient accepted", r.Receipt)
+		}
+
+		ids = append(ids, r.Receipt.MessageID)
+	}
+
+	if ids[0] == ids[1] {
+		t.Errorf("Message-IDs = %v, want a Message-ID per copy", ids)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	input := entities.MailInput{
+		MessageID: "<m@test.com>",
+		Envelope:  mailables.Envelope{Headers: map[string]string{"X-Ref": "1"}},
+	}
+
+	headers := pmail.Headers(input)
+	if len(headers) != 2 || headers["Message-ID"] != "<m@test.com>" || headers["X-Ref"] != "1" {
+		t.Errorf("Headers() = %v, want the custom headers and the Message-ID", headers)
+	}
+
+	if len(input.Envelope.Headers) != 1 {
+		t.Errorf("Envelope.Headers = %v, want it unchanged", input.Envelope.Headers)
+	}
+}
@@ -0,0 +1,79 @@
+package mail
+
+import (
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/entities/mailables"
+)
+
+// Rendered is a mailable rendered by Render.
+type Rendered struct {
+	// Envelope is the envelope merged with the default envelope and normalized
+	Envelope  mailables.Envelope
+	MessageID string
+	Html      string
+	// Text is the text of the HTML body, see PlainText
+	Text        string
+	Attachments mailables.AttachmentSlice
+	// Raw is the MIME message with the processors applied, as adapters that send raw messages send it
+	Raw []byte
+	// Input is the input Send hands to the adapter, before attachments that do not fit are
+	// replaced by download links
+	Input entities.MailInput
+
+	localization mailables.Localization
+}
+
+// Render renders the mailable without sending it, with the same pipeline as Send: the default
+// envelope, localization, processors and attachment limits are applied like they are when sending.
+//
+// The Message-ID and Date differ from a message sent separately. Render and send with the same
+// WithIdempotencyKey to get the same Message-ID, or send the rendered message with SendRendered.
+//
+// Render has no side effects: attachments that WithAttachmentLinks would link are not uploaded,
+// their links in the preview point to "#".
+func Render(mailable contracts.Mailable, optionSlice ...Option) (*Rendered, error) {
+	options := apply(optionSlice...)
+
+	input, localization, err := compose(mailable, options)
+	if err != nil {
+		return nil, err
+	}
+
+	preview, err := enforceLimits(options, localization, input, false)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := Raw(options.Context, preview)
+	if err != nil {
+		return nil, err
+	}
+
+	html := preview.Html.String()
+
+	return &Rendered{
+		Envelope:     preview.Envelope,
+		MessageID:    preview.MessageID,
+		Html:         html,
+		Text:         PlainText(html),
+		Attachments:  preview.Attachments,
+		Raw:          raw,
+		Input:        input,
+		localization: localization,
+	}, nil
+}
+
+// SendRendered sends a message rendered by Render, so the archived copy is the message that went out.
+// Attachments that do not fit are uploaded and linked now, so the links differ from the preview.
+// With SendAt the message is scheduled, like SendWithReceipt does.
+func SendRendered(rendered *Rendered, optionSlice ...Option) (*entities.MailReceipt, error) {
+	options := apply(optionSlice...)
+
+	input, err := enforceLimits(options, rendered.localization, rendered.Input, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return send(options, input)
+}
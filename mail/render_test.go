@@ -0,0 +1,93 @@
+package mail_test
+
+import (
+	"bytes"
+	"context"
+	netmail "net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	storagefake "github.com/gonstruct/providers/adapters/storage/fake"
+	"github.com/gonstruct/providers/entities/mailables"
+	pmail "github.com/gonstruct/providers/mail"
+)
+
+func TestRender(t *testing.T) {
+	f := pmail.Fake(
+		pmail.WithFakeTemplates(testTemplatesFS),
+		pmail.WithFakeDefaultEnvelope(mailables.Envelope{Bcc: mailables.Addresses("archive@test.com")}),
+	)
+
+	rendered, err := pmail.Render(withAttachments(map[string]int{"small.pdf": 1024}))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	f.AssertNothingSent(t)
+
+	if len(rendered.Envelope.Bcc) != 1 || rendered.Envelope.Bcc[0].Address != "archive@test.com" {
+		t.Errorf("Envelope.Bcc = %v, want the default envelope merged", rendered.Envelope.Bcc)
+	}
+
+	if !strings.Contains(rendered.Html, "Anna") || !strings.Contains(rendered.Text, "Anna") || strings.Contains(rendered.Text, "<") {
+		t.Errorf("Html = %q, Text = %q, want the rendered body and its text", rendered.Html, rendered.Text)
+	}
+
+	if len(rendered.Attachments) != 1 || rendered.Attachments[0].Name != "small.pdf" {
+		t.Errorf("Attachments = %v, want small.pdf", rendered.Attachments)
+	}
+
+	message, err := netmail.ReadMessage(bytes.NewReader(rendered.Raw))
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+
+	if message.Header.Get("Message-Id") != rendered.MessageID || message.Header.Get("Subject") != "Reports" {
+		t.Errorf("Raw headers = %v, want the Message-ID and subject of the rendered message", message.Header)
+	}
+
+	receipt, err := pmail.SendRendered(rendered)
+	if err != nil {
+		t.Fatalf("SendRendered() error = %v", err)
+	}
+
+	if receipt.MessageID != rendered.MessageID || f.LastCall().HTML != rendered.Html {
+		t.Errorf("SendRendered() sent %q, want the rendered message %q", receipt.MessageID, rendered.MessageID)
+	}
+}
+
+func TestRender_AttachmentLinks(t *testing.T) {
+	disk := storagefake.New()
+	disk.BaseURL = "https://files.example.com"
+
+	f := pmail.Fake(
+		pmail.WithFakeTemplates(testTemplatesFS),
+		pmail.WithFakeMaxMessageSize(20*1024),
+		pmail.WithFakeAttachmentLinks(disk, 24*time.Hour),
+	)
+
+	rendered, err := pmail.Render(withAttachments(map[string]int{"small.pdf": 4 * 1024, "large.pdf": 30 * 1024}))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	disk.AssertNothingStored(t)
+
+	if len(rendered.Attachments) != 1 || !strings.Contains(rendered.Html, `<a href="#">large.pdf</a>`) {
+		t.Errorf("Render() = %v, %q, want a preview link for large.pdf", rendered.Attachments, rendered.Html)
+	}
+
+	if _, err := pmail.SendRendered(rendered); err != nil {
+		t.Fatalf("SendRendered() error = %v", err)
+	}
+
+	files, err := disk.AllFiles(context.Background(), pmail.AttachmentLinkDirectory)
+	if err != nil || len(files) != 1 {
+		t.Errorf("uploaded files = %v, %v, want large.pdf once", files, err)
+	}
+
+	if html := f.LastCall().HTML; !strings.Contains(html, `<a href="https://files.example.com/mail-attachments/`) {
+		t.Errorf("sent Html = %q, want a download link", html)
+	}
+}
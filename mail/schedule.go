@@ -0,0 +1,262 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/entities/mailables"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultScheduleInterval is how often RunScheduler checks for due messages when no interval is given.
+	DefaultScheduleInterval = time.Minute
+	// ScheduleLease is how long a message is locked while it is delivered. A message whose delivery
+	// did not finish, for example because the process stopped, is sent again after the lease.
+	ScheduleLease = 5 * time.Minute
+	// MaxScheduleAttempts is how often the local scheduler tries a message before marking it as failed.
+	MaxScheduleAttempts = 10
+)
+
+var (
+	ErrNotScheduled          = errors.New("no scheduled message with this ID")
+	ErrSchedulingUnavailable = errors.New("adapter cannot schedule messages and no schedule store is configured")
+	// ErrUnschedulable is returned when the mailable has processors, which cannot be persisted by the local scheduler
+	ErrUnschedulable = errors.New("message processors of the mailable cannot be persisted")
+)
+
+// Schedule renders the mailable now, delivers it at the given time and returns the ID to cancel it with.
+// Times that have passed send the message immediately and return an empty ID.
+//
+// Adapters implementing contracts.ScheduledMail schedule the message at their provider when the
+// time is within their MaxScheduleDelay. Other messages are persisted in the store configured with
+// WithScheduleStore and sent by RunScheduler. The local scheduler delivers messages at least once:
+// a message is sent again, with the same IdempotencyKey, when the process stops while sending it.
+func Schedule(mailable contracts.Mailable, at time.Time, optionSlice ...Option) (string, error) {
+	options := apply(append(slices.Clip(optionSlice), SendAt(at))...)
+
+	input, err := build(mailable, options)
+	if err != nil {
+		return "", err
+	}
+
+	receipt, err := send(options, input)
+	if err != nil {
+		return "", err
+	}
+
+	return receipt.ScheduleID, nil
+}
+
+// Cancel cancels a message scheduled with SendAt or Schedule. Messages in the schedule store are
+// removed from it, other IDs are cancelled at the adapter.
+func Cancel(id string, optionSlice ...Option) error {
+	options := apply(optionSlice...)
+
+	if options.ScheduleStore != nil {
+		deleted, err := options.ScheduleStore.Delete(options.Context, id)
+		if err != nil {
+			return Err("cancel", err)
+		}
+
+		if deleted {
+			return nil
+		}
+	}
+
+	if scheduler, ok := options.Adapter.(contracts.ScheduledMail); ok {
+		return scheduler.CancelScheduled(options.Context, id)
+	}
+
+	return Err("cancel", ErrNotScheduled)
+}
+
+// SendDue sends the messages in the schedule store that are due and returns how many were sent.
+// Failed messages are retried with an increasing delay, up to MaxScheduleAttempts times.
+func SendDue(optionSlice ...Option) (int, error) {
+	options := apply(optionSlice...)
+	if options.ScheduleStore == nil {
+		return 0, Err("send due", ErrSchedulingUnavailable)
+	}
+
+	ctx, store := options.Context, options.ScheduleStore
+	now := time.Now()
+
+	due, err := store.Due(ctx, now)
+	if err != nil {
+		return 0, Err("send due", err)
+	}
+
+	var (
+		sent int
+		errs []error
+	)
+
+	for _, message := range due {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+
+			break
+		}
+
+		// The message is locked before sending, so it is retried after the lease when the process stops
+		message.Attempts++
+		message.LockedUntil = now.Add(ScheduleLease)
+
+		if err := store.Save(ctx, message); err != nil {
+			errs = append(errs, Err("send due", err))
+
+			continue
+		}
+
+		if err := options.Adapter.Send(ctx, scheduledInput(message, options.Processors)); err != nil {
+			errs = append(errs, fmt.Errorf("scheduled message %q: %w", message.ID, err))
+
+			message.LastError = err.Error()
+			message.LockedUntil = time.Now().Add(retryDelay(message.Attempts))
+
+			if message.Attempts >= MaxScheduleAttempts {
+				failedAt := time.Now()
+				message.FailedAt = &failedAt
+			}
+
+			if err := store.Save(ctx, message); err != nil {
+				errs = append(errs, Err("send due", err))
+			}
+
+			continue
+		}
+
+		sent++
+
+		if _, err := store.Delete(ctx, message.ID); err != nil {
+			errs = append(errs, Err("send due", err))
+		}
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// RunScheduler calls SendDue every interval until the context of the options is done and returns
+// the context error. Errors of SendDue are passed to WithScheduleErrorHandler.
+//
+// Run one scheduler per store: the store locks messages against overlapping runs and restarts,
+// not against schedulers in other processes.
+func RunScheduler(interval time.Duration, optionSlice ...Option) error {
+	options := apply(optionSlice...)
+
+	if interval <= 0 {
+		interval = DefaultScheduleInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := SendDue(optionSlice...); err != nil && options.ScheduleErrors != nil {
+			options.ScheduleErrors(err)
+		}
+
+		select {
+		case <-options.Context.Done():
+			return options.Context.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func scheduled(options *options) bool {
+	return !options.SendAt.IsZero() && options.SendAt.After(time.Now())
+}
+
+// schedule hands the input to the adapter when it can schedule it and to the schedule store otherwise.
+func schedule(options *options, input entities.MailInput) (string, error) {
+	if scheduler, ok := options.Adapter.(contracts.ScheduledMail); ok {
+		if limit := scheduler.MaxScheduleDelay(); limit == 0 || time.Until(options.SendAt) <= limit {
+			return scheduler.Schedule(options.Context, input, options.SendAt)
+		}
+	}
+
+	if options.ScheduleStore == nil {
+		return "", Err("schedule", ErrSchedulingUnavailable)
+	}
+
+	// The processors of the provider are applied again when the message is sent
+	if len(input.Processors) > len(options.Processors) {
+		return "", Err("schedule", ErrUnschedulable)
+	}
+
+	message, err := newScheduledMail(options.Context, input, options.SendAt)
+	if err != nil {
+		return "", err
+	}
+
+	if err := options.ScheduleStore.Save(options.Context, message); err != nil {
+		return "", Err("schedule", err)
+	}
+
+	return message.ID, nil
+}
+
+func newScheduledMail(ctx context.Context, input entities.MailInput, at time.Time) (entities.ScheduledMail, error) {
+	id := input.IdempotencyKey
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	message := entities.ScheduledMail{
+		ID:        id,
+		SendAt:    at,
+		MessageID: input.MessageID,
+		Envelope:  input.Envelope,
+		Html:      input.Html.String(),
+		Data:      input.Data,
+	}
+
+	for _, attachment := range input.Attachments {
+		content, err := attachment.ReadContent(ctx)
+		if err != nil {
+			return message, Err("read attachment", err)
+		}
+
+		message.Attachments = append(message.Attachments, entities.ScheduledAttachment{
+			Name:    attachment.Name,
+			Mime:    attachment.Mime,
+			Content: content,
+		})
+	}
+
+	return message, nil
+}
+
+func scheduledInput(message entities.ScheduledMail, processors []mailables.MessageProcessor) entities.MailInput {
+	input := entities.MailInput{
+		Envelope:       message.Envelope,
+		Data:           message.Data,
+		Processors:     processors,
+		IdempotencyKey: message.ID,
+		MessageID:      message.MessageID,
+	}
+
+	input.Html.WriteString(message.Html)
+
+	for _, attachment := range message.Attachments {
+		input.Attachments = append(input.Attachments, mailables.Attachment(
+			mailables.WithName(attachment.Name),
+			mailables.WithMime(attachment.Mime),
+			mailables.WithContent(attachment.Content),
+		))
+	}
+
+	return input
+}
+
+// retryDelay doubles from a minute with every attempt, up to an hour.
+func retryDelay(attempts int) time.Duration {
+	return min(time.Minute<<min(attempts-1, 6), time.Hour)
+}
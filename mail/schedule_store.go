@@ -0,0 +1,96 @@
+package mail
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+)
+
+// DefaultScheduleDirectory is the directory NewStorageScheduleStore keeps messages in.
+const DefaultScheduleDirectory = "mail-schedule"
+
+type storageScheduleStore struct {
+	disk      contracts.Storage
+	directory string
+}
+
+// NewStorageScheduleStore persists scheduled messages as JSON files in a directory of a storage disk,
+// DefaultScheduleDirectory when empty. Due reads every file, so it suits thousands of scheduled
+// messages rather than millions.
+func NewStorageScheduleStore(disk contracts.Storage, directory string) contracts.MailScheduleStore {
+	if directory == "" {
+		directory = DefaultScheduleDirectory
+	}
+
+	return &storageScheduleStore{disk: disk, directory: directory}
+}
+
+func (store *storageScheduleStore) Save(ctx context.Context, message entities.ScheduledMail) error {
+	content, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	return store.disk.Put(ctx, store.path(message.ID), content)
+}
+
+func (store *storageScheduleStore) Delete(ctx context.Context, id string) (bool, error) {
+	exists, err := store.disk.Exists(ctx, store.path(id))
+	if err != nil || !exists {
+		return false, err
+	}
+
+	if err := store.disk.Delete(ctx, store.path(id)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (store *storageScheduleStore) Due(ctx context.Context, now time.Time) ([]entities.ScheduledMail, error) {
+	files, err := store.disk.Files(ctx, store.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var due []entities.ScheduledMail
+
+	for _, file := range files {
+		if !strings.HasSuffix(file, ".json") {
+			continue
+		}
+
+		content, err := store.disk.Get(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+
+		var message entities.ScheduledMail
+		if err := json.Unmarshal(content, &message); err != nil {
+			return nil, err
+		}
+
+		if message.FailedAt == nil && !message.SendAt.After(now) && !message.LockedUntil.After(now) {
+			due = append(due, message)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool { return due[i].SendAt.Before(due[j].SendAt) })
+
+	return due, nil
+}
+
+// path hashes the ID, as idempotency keys may contain characters that are not valid in paths.
+func (store *storageScheduleStore) path(id string) string {
+	sum := sha256.Sum256([]byte(id))
+
+	return path.Join(store.directory, hex.EncodeToString(sum[:16])+".json")
+}
@@ -0,0 +1,249 @@
+package mail_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gonstruct/providers/adapters/mail/fake"
+	storagefake "github.com/gonstruct/providers/adapters/storage/fake"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/entities/mailables"
+	pmail "github.com/gonstruct/providers/mail"
+)
+
+func reminder() testMailable {
+	return testMailable{
+		envelope: mailables.Envelope{
+			From:    mailables.Address("noreply@test.com", ""),
+			To:      mailables.Addresses("anna@example.com"),
+			Subject: "Reminder",
+		},
+		content: mailables.Content{View: "welcome.html", With: map[string]any{"name": "Anna"}},
+		attachments: mailables.Attachments(mailables.Attachment(
+			mailables.WithName("agenda.txt"),
+			mailables.WithContent([]byte("09:00 standup")),
+		)),
+	}
+}
+
+func TestSendAt_Fake(t *testing.T) {
+	f := pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS))
+
+	at := time.Now().Add(24 * time.Hour)
+
+	if err := pmail.Send(reminder(), pmail.SendAt(at)); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	id, err := pmail.Schedule(reminder(), at, pmail.WithIdempotencyKey("reminder-42"))
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+
+	f.AssertNothingSent(t)
+	f.AssertQueuedCount(t, 2)
+	f.AssertQueuedTo(t, "anna@example.com")
+	f.AssertQueuedAt(t, at)
+	f.AssertQueuedMatching(t, func(call fake.QueuedCall) bool { return call.ID == "reminder-42" })
+	fake.AssertQueuedMailable(t, f, func(m testMailable) bool { return m.envelope.Subject == "Reminder" })
+
+	if err := pmail.Cancel(id); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+
+	f.AssertCancelled(t, "reminder-42")
+	f.AssertQueuedCount(t, 1)
+
+	// Times that have passed are sent immediately
+	if err := pmail.Send(reminder(), pmail.SendAt(time.Now().Add(-time.Minute))); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	f.AssertSentCount(t, 1)
+}
+
+func TestSchedule_FakeSendFunc(t *testing.T) {
+	f := pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS))
+
+	var scheduled []string
+
+	f.SendFunc = func(_ context.Context, input entities.MailInput) error {
+		scheduled = append(scheduled, input.Envelope.Subject)
+
+		if input.IdempotencyKey == "rejected" {
+			return pmail.ErrRejected
+		}
+
+		return nil
+	}
+
+	at := time.Now().Add(time.Hour)
+
+	if _, err := pmail.Schedule(reminder(), at, pmail.WithIdempotencyKey("rejected")); !errors.Is(err, pmail.ErrRejected) {
+		t.Errorf("Schedule() error = %v, want the error of SendFunc", err)
+	}
+
+	if _, err := pmail.Schedule(reminder(), at, pmail.WithIdempotencyKey("accepted")); err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+
+	if len(scheduled) != 2 {
+		t.Errorf("SendFunc calls = %v, want one per Schedule", scheduled)
+	}
+
+	f.AssertQueuedCount(t, 1)
+	f.AssertQueuedMatching(t, func(call fake.QueuedCall) bool { return call.ID == "accepted" })
+}
+
+// receiptAdapter implements contracts.ReceiptMail, Send should not be called for it.
+type receiptAdapter struct {
+	receipts int
+}
+
+func (a *receiptAdapter) Send(context.Context, entities.MailInput) error {
+	return errors.New("Send should not be called for receipt adapters")
+}
+
+func (a *receiptAdapter) SendWithReceipt(_ context.Context, input entities.MailInput) (*entities.MailReceipt, error) {
+	a.receipts++
+
+	return pmail.NewReceipt(input, "provider"), nil
+}
+
+func TestSchedule_Past(t *testing.T) {
+	pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS))
+
+	adapter := &receiptAdapter{}
+
+	id, err := pmail.Schedule(reminder(), time.Now().Add(-time.Minute), pmail.WithAdapter(adapter))
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+
+	if id != "" || adapter.receipts != 1 {
+		t.Errorf("Schedule() = %q with %d receipts, want the message delivered like Send", id, adapter.receipts)
+	}
+}
+
+func TestSendAt_LocalScheduler(t *testing.T) {
+	disk := storagefake.New()
+	store := pmail.NewStorageScheduleStore(disk, "")
+	f := pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS), pmail.WithFakeScheduleStore(store))
+
+	soon := time.Now().Add(50 * time.Millisecond)
+
+	id, err := pmail.Schedule(reminder(), soon, pmail.WithIdempotencyKey("reminder/42"))
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+
+	// Scheduling with the same key replaces the message
+	if _, err := pmail.Schedule(reminder(), soon, pmail.WithIdempotencyKey("reminder/42")); err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+
+	cancelled, err := pmail.Schedule(reminder(), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+
+	if err := pmail.Cancel(cancelled); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+
+	if err := pmail.Cancel(cancelled); !errors.Is(err, pmail.ErrNotScheduled) {
+		t.Errorf("Cancel() error = %v, want ErrNotScheduled", err)
+	}
+
+	if sent, err := pmail.SendDue(); sent != 0 || err != nil {
+		t.Fatalf("SendDue() = %d, %v before the message is due", sent, err)
+	}
+
+	time.Sleep(time.Until(soon))
+
+	if sent, err := pmail.SendDue(); sent != 1 || err != nil {
+		t.Fatalf("SendDue() = %d, %v, want 1 message sent", sent, err)
+	}
+
+	f.AssertSentCount(t, 1)
+	f.AssertSentWithAttachment(t, "agenda.txt")
+	f.AssertSentHTMLContains(t, "Hello Anna")
+
+	if key := f.LastCall().Input.IdempotencyKey; key != id {
+		t.Errorf("IdempotencyKey = %q, want %q", key, id)
+	}
+
+	if files, _ := disk.Files(context.Background(), pmail.DefaultScheduleDirectory); len(files) != 0 {
+		t.Errorf("store files = %v, want none after sending", files)
+	}
+}
+
+func TestSendDue_Retry(t *testing.T) {
+	disk := storagefake.New()
+	store := pmail.NewStorageScheduleStore(disk, "")
+	f := pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS), pmail.WithFakeScheduleStore(store))
+	f.SendError = pmail.ErrRateLimited
+
+	ctx := context.Background()
+
+	if err := store.Save(ctx, entities.ScheduledMail{
+		ID:       "reminder-42",
+		SendAt:   time.Now().Add(-time.Minute),
+		Envelope: reminder().envelope,
+		Html:     "<p>Hi</p>",
+	}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if sent, err := pmail.SendDue(); sent != 0 || !errors.Is(err, pmail.ErrRateLimited) {
+		t.Fatalf("SendDue() = %d, %v, want ErrRateLimited", sent, err)
+	}
+
+	// The failed message waits before it is retried
+	due, err := store.Due(ctx, time.Now())
+	if err != nil || len(due) != 0 {
+		t.Fatalf("Due() = %v, %v, want no messages", due, err)
+	}
+
+	due, err = store.Due(ctx, time.Now().Add(2*time.Minute))
+	if err != nil || len(due) != 1 {
+		t.Fatalf("Due() = %v, %v, want the failed message", due, err)
+	}
+
+	if due[0].Attempts != 1 || due[0].LastError == "" {
+		t.Errorf("Attempts = %d, LastError = %q, want 1 attempt with an error", due[0].Attempts, due[0].LastError)
+	}
+}
+
+func TestSendAt_Unavailable(t *testing.T) {
+	pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS))
+
+	// Hide the scheduling of the fake adapter
+	err := pmail.Send(reminder(), pmail.WithAdapter(struct{ fakeMail }{fake.New()}), pmail.SendAt(time.Now().Add(time.Hour)))
+	if !errors.Is(err, pmail.ErrSchedulingUnavailable) {
+		t.Errorf("Send() error = %v, want ErrSchedulingUnavailable", err)
+	}
+}
+
+type fakeMail interface {
+	Send(ctx context.Context, input entities.MailInput) error
+}
+
+func TestSendMany_SendAt(t *testing.T) {
+	f := pmail.Fake(pmail.WithFakeTemplates(testTemplatesFS))
+
+	result := pmail.SendMany(newsletter(), recipients("anna@example.com", "bob@example.com"),
+		pmail.SendAt(time.Now().Add(time.Hour)), pmail.WithIdempotencyKey("newsletter-12"))
+	if err := result.Err(); err != nil {
+		t.Fatalf("SendMany() error = %v", err)
+	}
+
+	f.AssertNothingSent(t)
+	f.AssertQueuedCount(t, 2)
+
+	if id := result.Results[1].ID; id != "newsletter-12:bob@example.com" {
+		t.Errorf("Results[1].ID = %q, want %q", id, "newsletter-12:bob@example.com")
+	}
+}
@@ -1,23 +1,101 @@
 package mail
 
 import (
+	"slices"
+
 	"github.com/gonstruct/providers/contracts"
 	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/entities/mailables"
 )
 
 func Send(mailable contracts.Mailable, optionSlice ...Option) error {
 	options := apply(optionSlice...)
 
-	envelope := options.DefaultEnvelope.Merge(mailable.Envelope())
-
-	content, err := mailable.Content().Parse(options.Templates)
+	input, err := build(mailable, options)
 	if err != nil {
 		return err
 	}
 
-	return options.Adapter.Send(options.Context, entities.MailInput{
+	_, err = send(options, input)
+
+	return err
+}
+
+// wrappedMailable is implemented by mailables that decorate another mailable, like the
+// per-recipient mailables of SendMany. Fakes record the mailable they wrap.
+type wrappedMailable interface {
+	Unwrap() contracts.Mailable
+}
+
+// build renders the mailable into the input handed to the adapter, attachments that do not fit
+// are uploaded and replaced by download links.
+func build(mailable contracts.Mailable, options *options) (entities.MailInput, error) {
+	input, localization, err := compose(mailable, options)
+	if err != nil {
+		return entities.MailInput{}, err
+	}
+
+	return enforceLimits(options, localization, input, true)
+}
+
+// compose renders the mailable into the input handed to the adapter, before the size limits are
+// enforced, and returns the localization of the mailable.
+func compose(mailable contracts.Mailable, options *options) (entities.MailInput, mailables.Localization, error) {
+	var defaults mailables.Envelope
+	if options.DefaultEnvelope != nil {
+		defaults = *options.DefaultEnvelope
+	}
+
+	envelope, err := defaults.Merge(mailable.Envelope(), options.MergeOptions...).Normalize()
+	if err != nil {
+		return entities.MailInput{}, mailables.Localization{}, Err("validate", err)
+	}
+
+	localization := options.Localization
+	if localized, ok := mailable.(contracts.LocalizedMailable); ok && localized.Locale() != "" {
+		localization.Locale = localized.Locale()
+	}
+
+	if options.Locale != "" {
+		localization.Locale = options.Locale
+	}
+
+	content := mailable.Content()
+
+	html, err := content.ParseLocalized(options.Templates, localization)
+	if err != nil {
+		return entities.MailInput{}, localization, err
+	}
+
+	attachments, err := mailable.Attachments().Resolve(options.Context)
+	if err != nil {
+		return entities.MailInput{}, localization, Err("resolve attachments", err)
+	}
+
+	processors := options.Processors
+	if processed, ok := mailable.(contracts.ProcessedMailable); ok {
+		processors = append(slices.Clip(processors), processed.Processors()...)
+	}
+
+	original := mailable
+	for {
+		wrapped, ok := original.(wrappedMailable)
+		if !ok {
+			break
+		}
+
+		original = wrapped.Unwrap()
+	}
+
+	return entities.MailInput{
 		Envelope:    envelope,
-		Attachments: mailable.Attachments(),
-		Html:        content,
-	})
+		Attachments: attachments,
+		Html:        html,
+		Data:        content.With,
+		Processors:  processors,
+		Mailable:    original,
+
+		IdempotencyKey: options.IdempotencyKey,
+		MessageID:      newMessageID(envelope, options.IdempotencyKey),
+	}, localization, nil
 }
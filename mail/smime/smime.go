@@ -0,0 +1,193 @@
+// Package smime signs and encrypts outgoing mail with S/MIME (RFC 8551).
+//
+// A Processor is a mailables.MessageProcessor and is configured on the mailer with
+// mail.WithMessageProcessors or returned by a mailable's Processors method:
+//
+//	processor := smime.New(
+//	    smime.WithSigner(certificate, key),
+//	    smime.WithEncryption(smime.Certificates{"patient@example.com": patientCertificate}),
+//	)
+//
+//	mail.Adapt(adapter, mail.WithMessageProcessors(processor))
+package smime
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	netmail "net/mail"
+	"strings"
+	"sync"
+
+	"github.com/gonstruct/providers/entities/mailables"
+	"github.com/gonstruct/providers/mail/internal/mimepart"
+	"github.com/smallstep/pkcs7"
+)
+
+var (
+	ErrNoCertificate = errors.New("no certificate for recipient")
+	ErrNotConfigured = errors.New("neither signing nor encryption is configured")
+	ErrEncryptedBcc  = errors.New("encrypted messages cannot have Bcc recipients")
+)
+
+// CertificateResolver looks up the certificate of a recipient to encrypt a message for.
+type CertificateResolver interface {
+	Certificate(ctx context.Context, address string) (*x509.Certificate, error)
+}
+
+// Certificates is a CertificateResolver backed by a map of addresses to certificates.
+type Certificates map[string]*x509.Certificate
+
+func (certificates Certificates) Certificate(_ context.Context, address string) (*x509.Certificate, error) {
+	for candidate, certificate := range certificates {
+		if strings.EqualFold(candidate, address) {
+			return certificate, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNoCertificate, address)
+}
+
+// Processor signs and/or encrypts messages. When both are configured the message is signed first.
+//
+// Messages are encrypted with AES-256-CBC. pkcs7 has no per-call option for the content encryption
+// algorithm, so encrypting sets the process-wide pkcs7.ContentEncryptionAlgorithm, which also applies
+// to other users of pkcs7 in the process.
+type Processor struct {
+	certificate *x509.Certificate
+	key         crypto.PrivateKey
+	chain       []*x509.Certificate
+	recipients  CertificateResolver
+}
+
+type Option func(*Processor)
+
+// New creates an S/MIME processor.
+func New(options ...Option) *Processor {
+	processor := &Processor{}
+
+	for _, option := range options {
+		option(processor)
+	}
+
+	return processor
+}
+
+// WithSigner signs messages with a detached PKCS#7 signature. The chain is included in the
+// signature so recipients can verify it up to their trusted roots.
+func WithSigner(certificate *x509.Certificate, key crypto.PrivateKey, chain ...*x509.Certificate) Option {
+	return func(p *Processor) {
+		p.certificate = certificate
+		p.key = key
+		p.chain = chain
+	}
+}
+
+// WithEncryption encrypts messages for every recipient with the certificates of the resolver.
+// When a signer is configured the message is also encrypted for the sender's certificate,
+// so the sent copy can be read. Messages with Bcc recipients fail with ErrEncryptedBcc, the
+// recipient list of the encrypted message would reveal them to the other recipients.
+func WithEncryption(recipients CertificateResolver) Option {
+	return func(p *Processor) {
+		p.recipients = recipients
+	}
+}
+
+func (p *Processor) Process(ctx context.Context, envelope mailables.Envelope, message []byte) ([]byte, error) {
+	if p.certificate == nil && p.recipients == nil {
+		return nil, ErrNotConfigured
+	}
+
+	outer, entity, err := mimepart.Split(message)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.certificate != nil {
+		if entity, err = p.sign(entity); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.recipients != nil {
+		if entity, err = p.encrypt(ctx, envelope, entity); err != nil {
+			return nil, err
+		}
+	}
+
+	return mimepart.Join(outer, entity), nil
+}
+
+func (p *Processor) sign(entity []byte) ([]byte, error) {
+	signed, err := pkcs7.NewSignedData(entity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+
+	signed.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+
+	if err := signed.AddSignerChain(p.certificate, p.key, p.chain, pkcs7.SignerInfoConfig{}); err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+
+	signed.Detach()
+
+	signature, err := signed.Finish()
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+
+	boundary, body := mimepart.Multipart(entity, mimepart.Entity(
+		mimepart.Base64(signature),
+		`Content-Type: application/pkcs7-signature; name="smime.p7s"`,
+		"Content-Transfer-Encoding: base64",
+		`Content-Disposition: attachment; filename="smime.p7s"`,
+	))
+
+	return mimepart.Entity(body,
+		fmt.Sprintf(`Content-Type: multipart/signed; protocol="application/pkcs7-signature"; micalg=sha-256; boundary="%s"`, boundary),
+	), nil
+}
+
+// encryptMu guards pkcs7.ContentEncryptionAlgorithm between setting it and encrypting.
+var encryptMu sync.Mutex
+
+func (p *Processor) encrypt(ctx context.Context, envelope mailables.Envelope, entity []byte) ([]byte, error) {
+	if len(envelope.Bcc) > 0 {
+		return nil, ErrEncryptedBcc
+	}
+
+	var certificates []*x509.Certificate
+
+	for _, list := range [][]*netmail.Address{envelope.To, envelope.Cc} {
+		for _, recipient := range list {
+			certificate, err := p.recipients.Certificate(ctx, recipient.Address)
+			if err != nil {
+				return nil, err
+			}
+
+			certificates = append(certificates, certificate)
+		}
+	}
+
+	if p.certificate != nil {
+		certificates = append(certificates, p.certificate)
+	}
+
+	encryptMu.Lock()
+	pkcs7.ContentEncryptionAlgorithm = pkcs7.EncryptionAlgorithmAES256CBC
+	encrypted, err := pkcs7.Encrypt(entity, certificates)
+	encryptMu.Unlock()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %w", err)
+	}
+
+	return mimepart.Entity(mimepart.Base64(encrypted),
+		`Content-Type: application/pkcs7-mime; smime-type=enveloped-data; name="smime.p7m"`,
+		"Content-Transfer-Encoding: base64",
+		`Content-Disposition: attachment; filename="smime.p7m"`,
+	), nil
+}
@@ -0,0 +1,204 @@
+package smime_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"io"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/entities/mailables"
+	pmail "github.com/gonstruct/providers/mail"
+	"github.com/gonstruct/providers/mail/smime"
+	"github.com/smallstep/pkcs7"
+)
+
+func certificate(t *testing.T, email string) (*x509.Certificate, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:   big.NewInt(time.Now().UnixNano()),
+		Subject:        pkix.Name{CommonName: email},
+		EmailAddresses: []string{email},
+		NotBefore:      time.Now().Add(-time.Hour),
+		NotAfter:       time.Now().Add(time.Hour),
+		KeyUsage:       x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageEmailProtection},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+
+	parsed, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+
+	return parsed, key
+}
+
+func input(processors ...mailables.MessageProcessor) entities.MailInput {
+	return entities.MailInput{
+		Envelope: mailables.Envelope{
+			From:    mailables.Address("doctor@example.com", ""),
+			To:      mailables.Addresses("patient@example.com"),
+			Subject: "Lab results",
+		},
+		Html:       *bytes.NewBufferString("<p>Your results are ready</p>"),
+		Processors: processors,
+	}
+}
+
+func TestProcess_SignAndEncrypt(t *testing.T) {
+	senderCertificate, senderKey := certificate(t, "doctor@example.com")
+	recipientCertificate, recipientKey := certificate(t, "patient@example.com")
+
+	processor := smime.New(
+		smime.WithSigner(senderCertificate, senderKey),
+		smime.WithEncryption(smime.Certificates{"Patient@Example.com": recipientCertificate}),
+	)
+
+	raw, err := pmail.Raw(context.Background(), input(processor))
+	if err != nil {
+		t.Fatalf("Raw() error = %v", err)
+	}
+
+	message, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+
+	if subject := message.Header.Get("Subject"); subject != "Lab results" {
+		t.Errorf("Subject = %q, want the subject to stay readable", subject)
+	}
+
+	if contentType := message.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "application/pkcs7-mime; smime-type=enveloped-data") {
+		t.Fatalf("Content-Type = %q, want application/pkcs7-mime", contentType)
+	}
+
+	body, _ := io.ReadAll(message.Body)
+
+	der, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(body), "\r\n", ""))
+	if err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+
+	enveloped, err := pkcs7.Parse(der)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// Both the recipient and the sender can decrypt the message
+	if _, err := enveloped.Decrypt(senderCertificate, senderKey); err != nil {
+		t.Errorf("Decrypt() with sender key error = %v", err)
+	}
+
+	signed, err := enveloped.Decrypt(recipientCertificate, recipientKey)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+
+	content, signature := signedParts(t, signed)
+
+	detached, err := pkcs7.Parse(signature)
+	if err != nil {
+		t.Fatalf("Parse(signature) error = %v", err)
+	}
+
+	detached.Content = content
+	if err := detached.Verify(); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+
+	if !bytes.Contains(content, []byte("Your results are ready")) {
+		t.Errorf("signed content = %q, want the HTML body", content)
+	}
+}
+
+func TestProcess_MissingRecipientCertificate(t *testing.T) {
+	processor := smime.New(smime.WithEncryption(smime.Certificates{}))
+
+	_, err := pmail.Raw(context.Background(), input(processor))
+	if !errors.Is(err, smime.ErrNoCertificate) {
+		t.Errorf("Raw() error = %v, want ErrNoCertificate", err)
+	}
+}
+
+func TestProcess_EncryptedBcc(t *testing.T) {
+	recipientCertificate, _ := certificate(t, "patient@example.com")
+	insurerCertificate, _ := certificate(t, "insurer@example.com")
+
+	processor := smime.New(smime.WithEncryption(smime.Certificates{
+		"patient@example.com": recipientCertificate,
+		"insurer@example.com": insurerCertificate,
+	}))
+
+	message := input(processor)
+	message.Envelope.Bcc = mailables.Addresses("insurer@example.com")
+
+	_, err := pmail.Raw(context.Background(), message)
+	if !errors.Is(err, smime.ErrEncryptedBcc) {
+		t.Errorf("Raw() error = %v, want ErrEncryptedBcc", err)
+	}
+}
+
+// signedParts returns the signed content and the DER signature of a multipart/signed entity.
+func signedParts(t *testing.T, entity []byte) ([]byte, []byte) {
+	t.Helper()
+
+	message, err := mail.ReadMessage(bytes.NewReader(entity))
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/signed" {
+		t.Fatalf("Content-Type = %q, want multipart/signed", message.Header.Get("Content-Type"))
+	}
+
+	body, _ := io.ReadAll(message.Body)
+
+	// The signed content is the exact bytes between the first two boundaries
+	delimiter := []byte("--" + params["boundary"] + "\r\n")
+	start := bytes.Index(body, delimiter) + len(delimiter)
+	end := bytes.Index(body, []byte("\r\n--"+params["boundary"]))
+	content := body[start:end]
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	if _, err := reader.NextPart(); err != nil {
+		t.Fatalf("NextPart() error = %v", err)
+	}
+
+	part, err := reader.NextPart()
+	if err != nil {
+		t.Fatalf("NextPart() error = %v", err)
+	}
+
+	encoded, _ := io.ReadAll(part)
+
+	signature, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	if err != nil {
+		t.Fatalf("decode signature error = %v", err)
+	}
+
+	return content, signature
+}
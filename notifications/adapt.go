@@ -0,0 +1,48 @@
+package notifications
+
+import (
+	"github.com/gonstruct/providers/contracts"
+)
+
+// Channels that are built in or provided by the adapters in adapters/notifications.
+const (
+	ChannelMail     = "mail"
+	ChannelWebhook  = "webhook"
+	ChannelDatabase = "database"
+)
+
+var globalProvider *provider
+
+type provider struct {
+	channels map[string]contracts.NotificationChannel
+	// fallback receives the notifications of channels that are not registered, used by Fake
+	fallback contracts.NotificationChannel
+}
+
+// Adapt sets up the notification provider. The mail channel is registered by default
+// and sends with mail.Send, other channels are registered with WithChannel.
+func Adapt(options ...func(*provider)) {
+	provider := &provider{
+		channels: map[string]contracts.NotificationChannel{
+			ChannelMail: MailChannel{},
+		},
+	}
+
+	for _, option := range options {
+		option(provider)
+	}
+
+	if globalProvider != nil {
+		panic("notification provider already set")
+	}
+
+	globalProvider = provider
+}
+
+// WithChannel registers the channel under the name notifications use in Via,
+// replacing the channel registered under that name.
+func WithChannel(name string, channel contracts.NotificationChannel) func(*provider) {
+	return func(p *provider) {
+		p.channels[name] = channel
+	}
+}
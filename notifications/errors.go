@@ -0,0 +1,19 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors for notification operations.
+var (
+	ErrUnknownChannel = errors.New("no channel registered with this name")
+	// ErrUnsupportedChannel is returned by channels when the notification has no representation for them
+	ErrUnsupportedChannel = errors.New("notification has no representation for the channel")
+	ErrDeliveryFailed     = errors.New("channel failed to deliver the notification")
+)
+
+// Err wraps an error with notifications context.
+func Err(op string, err error) error {
+	return fmt.Errorf("notifications: %s: %w", op, err)
+}
@@ -0,0 +1,48 @@
+package notifications
+
+import (
+	"github.com/gonstruct/providers/adapters/notifications/fake"
+	"github.com/gonstruct/providers/contracts"
+)
+
+// FakeOption configures the fake notification provider.
+type FakeOption func(*provider)
+
+// WithFakeChannel registers a real channel with the fake provider, so its notifications are
+// sent instead of recorded. Use it with MailChannel and mail.Fake to assert on the rendered mail.
+func WithFakeChannel(name string, channel contracts.NotificationChannel) FakeOption {
+	return func(p *provider) {
+		p.channels[name] = channel
+	}
+}
+
+// Fake sets up a fake notification channel for testing and returns it for assertions.
+// Notifications on every channel are recorded by the fake. This replaces any existing
+// notification provider.
+//
+// Example:
+//
+//	func TestInvoicePaid(t *testing.T) {
+//	    fake := notifications.Fake()
+//
+//	    // Your code that uses notifications.Send()
+//	    notifications.Send(user, InvoicePaid{Invoice: invoice})
+//
+//	    // Assert
+//	    fake.AssertSentTo(t, user)
+//	    fake.AssertSentOn(t, notifications.ChannelMail, "user@example.com")
+//	}
+func Fake(options ...FakeOption) *fake.Adapter {
+	adapter := fake.New()
+
+	globalProvider = &provider{
+		channels: map[string]contracts.NotificationChannel{},
+		fallback: adapter,
+	}
+
+	for _, opt := range options {
+		opt(globalProvider)
+	}
+
+	return adapter
+}
@@ -0,0 +1,65 @@
+package notifications
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/entities/mailables"
+	"github.com/gonstruct/providers/mail"
+)
+
+// MailChannel sends the mail representation of notifications with mail.Send,
+// so the templates, default envelope and adapter of the mail provider are used.
+type MailChannel struct {
+	// Options are passed to mail.Send after the context of the notification
+	Options []mail.Option
+}
+
+func (channel MailChannel) Send(ctx context.Context, input entities.NotificationInput) error {
+	notification, ok := input.Notification.(contracts.MailNotification)
+	if !ok {
+		return ErrUnsupportedChannel
+	}
+
+	mailable := routedMailable{Mailable: notification.ToMail(), route: input.Route}
+
+	return mail.Send(mailable, append([]mail.Option{mail.WithContext(ctx)}, slices.Clip(channel.Options)...)...)
+}
+
+// routedMailable sends the mailable to the route of the notifiable when it has no recipients.
+type routedMailable struct {
+	contracts.Mailable
+	route string
+}
+
+func (m routedMailable) Envelope() mailables.Envelope {
+	envelope := m.Mailable.Envelope()
+	if len(envelope.To) == 0 {
+		envelope.To = mailables.Addresses(m.route)
+	}
+
+	return envelope
+}
+
+// Unwrap lets the mail fake record the mailable of the notification.
+func (m routedMailable) Unwrap() contracts.Mailable {
+	return m.Mailable
+}
+
+func (m routedMailable) Locale() string {
+	if localized, ok := m.Mailable.(contracts.LocalizedMailable); ok {
+		return localized.Locale()
+	}
+
+	return ""
+}
+
+func (m routedMailable) Processors() []mailables.MessageProcessor {
+	if processed, ok := m.Mailable.(contracts.ProcessedMailable); ok {
+		return processed.Processors()
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package notifications_test
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"testing"
+
+	mailfake "github.com/gonstruct/providers/adapters/mail/fake"
+	"github.com/gonstruct/providers/adapters/notifications/fake"
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/entities/mailables"
+	"github.com/gonstruct/providers/mail"
+	"github.com/gonstruct/providers/notifications"
+)
+
+//go:embed mail
+var templates embed.FS
+
+type user struct {
+	ID    string
+	Email string
+	Slack string
+}
+
+func (u user) RouteNotificationFor(channel string) string {
+	switch channel {
+	case notifications.ChannelMail:
+		return u.Email
+	case notifications.ChannelWebhook:
+		return u.Slack
+	case notifications.ChannelDatabase:
+		return u.ID
+	}
+
+	return ""
+}
+
+type invoicePaid struct {
+	Invoice string
+}
+
+func (invoicePaid) Via(contracts.Notifiable) []string {
+	return []string{notifications.ChannelMail, notifications.ChannelWebhook, notifications.ChannelDatabase}
+}
+
+func (n invoicePaid) ToMail() contracts.Mailable {
+	return invoiceMail{invoice: n.Invoice}
+}
+
+func (n invoicePaid) ToWebhook() entities.WebhookMessage {
+	return entities.WebhookMessage{Payload: map[string]string{"text": "Invoice " + n.Invoice + " was paid"}}
+}
+
+func (n invoicePaid) ToDatabase() map[string]any {
+	return map[string]any{"invoice": n.Invoice}
+}
+
+type invoiceMail struct {
+	invoice string
+}
+
+func (m invoiceMail) Envelope() mailables.Envelope {
+	return mailables.Envelope{From: mailables.Address("billing@example.com", ""), Subject: "Invoice " + m.invoice + " paid"}
+}
+
+func (m invoiceMail) Content() mailables.Content {
+	return mailables.Content{View: "invoice-paid.html", With: map[string]any{"invoice": m.invoice}}
+}
+
+func (invoiceMail) Attachments() mailables.AttachmentSlice {
+	return nil
+}
+
+// mailOnly has no webhook or database representation.
+type mailOnly struct {
+	channels []string
+}
+
+func (n mailOnly) Via(contracts.Notifiable) []string {
+	return n.channels
+}
+
+func (mailOnly) ToMail() contracts.Mailable {
+	return invoiceMail{invoice: "2026-001"}
+}
+
+func TestSend_Fake(t *testing.T) {
+	f := notifications.Fake()
+
+	anna := user{ID: "42", Email: "anna@example.com", Slack: "https://hooks.slack.com/services/T/B/X"}
+	bob := user{ID: "43", Email: "bob@example.com"}
+
+	if err := notifications.SendAll([]user{anna, bob}, invoicePaid{Invoice: "2026-001"}); err != nil {
+		t.Fatalf("SendAll() error = %v", err)
+	}
+
+	// Bob has no webhook route, so that channel is skipped for him
+	f.AssertSentCount(t, 5)
+	f.AssertSentTo(t, anna)
+	f.AssertSentOn(t, notifications.ChannelMail, "bob@example.com")
+	f.AssertSentOn(t, notifications.ChannelWebhook, anna.Slack)
+	f.AssertSentOn(t, notifications.ChannelDatabase, "43")
+	f.AssertNotSentMatching(t, func(call fake.SendCall) bool {
+		return call.Channel == notifications.ChannelWebhook && call.Notifiable == bob
+	})
+	f.AssertSentMatching(t, func(call fake.SendCall) bool {
+		data, ok := call.Representation.(map[string]any)
+
+		return ok && data["invoice"] == "2026-001"
+	})
+	fake.AssertSentNotification(t, f, func(n invoicePaid) bool { return n.Invoice == "2026-001" })
+	fake.AssertNotSentNotification[mailOnly](t, f)
+}
+
+func TestSend_Errors(t *testing.T) {
+	f := notifications.Fake()
+	anna := user{ID: "42", Email: "anna@example.com", Slack: "https://hooks.slack.com/services/T/B/X"}
+
+	// The fake fails like the real channel when the notification has no representation
+	err := notifications.Send(anna, mailOnly{channels: []string{notifications.ChannelWebhook, notifications.ChannelMail}})
+	if err == nil {
+		t.Fatal("Send() error = nil, want an error for the webhook channel")
+	}
+
+	f.AssertSentOn(t, notifications.ChannelMail, "anna@example.com")
+	f.AssertNotSentOn(t, notifications.ChannelWebhook)
+
+	f.SendError = errors.New("unavailable")
+
+	if err := notifications.Send(anna, mailOnly{channels: []string{"mail"}}); !errors.Is(err, f.SendError) {
+		t.Errorf("Send() error = %v, want %v", err, f.SendError)
+	}
+}
+
+func TestMailChannel(t *testing.T) {
+	mails := mail.Fake(mail.WithFakeTemplates(templates))
+	f := notifications.Fake(notifications.WithFakeChannel(notifications.ChannelMail, notifications.MailChannel{}))
+
+	anna := user{ID: "42", Email: "anna@example.com"}
+
+	if err := notifications.Send(anna, invoicePaid{Invoice: "2026-001"}, notifications.WithContext(context.Background())); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	// The mailable has no recipients, so the mail route of the notifiable is used
+	mails.AssertSentTo(t, "anna@example.com")
+	mails.AssertSentWithSubject(t, "Invoice 2026-001 paid")
+	mails.AssertSentHTMLContains(t, "Invoice 2026-001 was paid.")
+	mailfake.AssertSentMailable(t, mails, func(m invoiceMail) bool { return m.invoice == "2026-001" })
+
+	f.AssertNotSentOn(t, notifications.ChannelMail)
+	f.AssertSentOn(t, notifications.ChannelDatabase, "42")
+}
@@ -0,0 +1,35 @@
+package notifications
+
+import (
+	"context"
+
+	"github.com/gonstruct/providers/contracts"
+)
+
+type options struct {
+	Context  context.Context
+	Channels map[string]contracts.NotificationChannel
+	Fallback contracts.NotificationChannel
+}
+
+type Option func(*options)
+
+func apply(optionSlice ...Option) *options {
+	options := &options{
+		Context:  context.Background(),
+		Channels: globalProvider.channels,
+		Fallback: globalProvider.fallback,
+	}
+
+	for _, option := range optionSlice {
+		option(options)
+	}
+
+	return options
+}
+
+func WithContext(ctx context.Context) Option {
+	return func(options *options) {
+		options.Context = ctx
+	}
+}
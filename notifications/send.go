@@ -0,0 +1,60 @@
+package notifications
+
+import (
+	"errors"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+)
+
+// Send sends the notification to the notifiable on every channel returned by Via.
+// Channels for which the notifiable has no route are skipped. A failing channel does not
+// stop the other channels, the errors of all channels are joined.
+func Send(notifiable contracts.Notifiable, notification contracts.Notification, optionSlice ...Option) error {
+	options := apply(optionSlice...)
+
+	var errs []error
+
+	for _, name := range notification.Via(notifiable) {
+		route := notifiable.RouteNotificationFor(name)
+		if route == "" {
+			continue
+		}
+
+		channel, ok := options.Channels[name]
+		if !ok {
+			channel = options.Fallback
+		}
+
+		if channel == nil {
+			errs = append(errs, Err("send via "+name, ErrUnknownChannel))
+
+			continue
+		}
+
+		err := channel.Send(options.Context, entities.NotificationInput{
+			Channel:      name,
+			Route:        route,
+			Notifiable:   notifiable,
+			Notification: notification,
+		})
+		if err != nil {
+			errs = append(errs, Err("send via "+name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SendAll sends the notification to every notifiable, see Send.
+func SendAll[T contracts.Notifiable](notifiables []T, notification contracts.Notification, optionSlice ...Option) error {
+	var errs []error
+
+	for _, notifiable := range notifiables {
+		if err := Send(notifiable, notification, optionSlice...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -3,15 +3,29 @@ package storage
 import (
 	"errors"
 	"fmt"
+
+	"github.com/gonstruct/providers/entities"
 )
 
 // Sentinel errors for storage operations.
 var (
-	ErrFileNotFound      = errors.New("file not found")
+	ErrFileNotFound      = entities.ErrStorageFileNotFound
 	ErrDirectoryNotFound = errors.New("directory not found")
 	ErrPermissionDenied  = errors.New("permission denied")
-	ErrInvalidPath       = errors.New("invalid path")
-	ErrAlreadyExists     = errors.New("file already exists")
+	ErrNotSupported      = errors.New("not supported by the storage adapter")
+	ErrInvalidSignature  = errors.New("invalid URL signature")
+	ErrExpiredSignature  = errors.New("expired URL signature")
+
+	// ErrInvalidPath is returned for paths that are absolute, escape the root or contain NUL bytes or backslashes.
+	ErrInvalidPath = entities.ErrStorageInvalidPath
+	// ErrAlreadyExists is returned by writes with IfNotExists when the file exists.
+	ErrAlreadyExists = entities.ErrStorageAlreadyExists
+	// ErrPreconditionFailed is returned by writes with IfMatch when the ETag of the file is another one.
+	ErrPreconditionFailed = entities.ErrStoragePreconditionFailed
+	// ErrInvalidRange is returned by range reads that start beyond the end of the file.
+	ErrInvalidRange = entities.ErrStorageInvalidRange
+	// ErrUploadRejected is returned for uploads with another content type or size than they were presigned for.
+	ErrUploadRejected = entities.ErrStorageUploadRejected
 )
 
 // Err wraps an error with storage context.
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"cmp"
+	"errors"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Handler returns an http.Handler that serves the files of the adapter at the paths of the requests,
+// with range requests and conditional requests on the ETag and modification time of the files, for
+// adapters without URLs of their own. Mount it with http.StripPrefix:
+//
+//	signer := storage.NewURLSigner(key)
+//	mux.Handle("/files/", http.StripPrefix("/files", storage.Handler(storage.WithSigner(signer))))
+//
+// Without WithSigner it serves every file of the adapter. Files of content types browsers run scripts
+// of, like HTML and SVG, are sent as downloads with a "Content-Security-Policy: sandbox" header.
+func Handler(optionSlice ...Option) http.Handler {
+	options := apply(optionSlice...)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serve(w, r, options)
+	})
+}
+
+// WithSigner makes Handler only serve the URLs signed by signer that have not expired, it responds
+// with 403 Forbidden to the others.
+func WithSigner(signer *URLSigner) Option {
+	return func(options *options) {
+		options.Signer = signer
+	}
+}
+
+// AsAttachment makes Handler send the files that were written without WithContentDisposition as
+// downloads, with their base name as file name.
+func AsAttachment() Option {
+	return func(options *options) {
+		options.Attachment = true
+	}
+}
+
+func serve(w http.ResponseWriter, r *http.Request, options *options) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	filePath, err := NormalizePath(strings.TrimPrefix(r.URL.Path, "/"))
+	if err != nil || filePath == "" {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	if options.Signer != nil {
+		// Upload URLs do not allow downloads, see UploadHandler
+		if err := options.Signer.Verify(filePath, r.URL.Query()); err != nil || r.URL.Query().Has(UploadParameter) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+
+			return
+		}
+	}
+
+	metadata, err := Stat(filePath, WithAdapter(options.Adapter), WithContext(r.Context()))
+	if err != nil {
+		serveError(w, r, err)
+
+		return
+	}
+
+	file, err := Open(filePath, WithAdapter(options.Adapter), WithContext(r.Context()))
+	if err != nil {
+		serveError(w, r, err)
+
+		return
+	}
+	defer file.Close()
+
+	header := w.Header()
+	header.Set("X-Content-Type-Options", "nosniff")
+
+	if metadata.ContentType != "" {
+		header.Set("Content-Type", metadata.ContentType)
+	}
+
+	if metadata.ETag != "" {
+		header.Set("ETag", metadata.ETag)
+	}
+
+	if metadata.CacheControl != "" {
+		header.Set("Cache-Control", metadata.CacheControl)
+	}
+
+	if metadata.ContentEncoding != "" {
+		header.Set("Content-Encoding", metadata.ContentEncoding)
+	}
+
+	disposition := metadata.ContentDisposition
+	if disposition == "" && options.Attachment {
+		disposition = attachment(disposition, filePath)
+	}
+
+	// Files browsers would run scripts of, like HTML and SVG, are downloaded in a sandbox instead of
+	// rendered on the origin of the application
+	if !inlineSafe(cmp.Or(metadata.ContentType, mime.TypeByExtension(path.Ext(filePath)))) {
+		header.Set("Content-Security-Policy", "sandbox")
+		disposition = attachment(disposition, filePath)
+	}
+
+	if disposition != "" {
+		header.Set("Content-Disposition", disposition)
+	}
+
+	// ServeContent answers range requests and the If-None-Match, If-Modified-Since and If-Range headers
+	http.ServeContent(w, r, path.Base(filePath), metadata.LastModified, file)
+}
+
+// attachment returns disposition as an attachment, with the base name of filePath as file name when it has none.
+func attachment(disposition, filePath string) string {
+	mediaType, params, err := mime.ParseMediaType(disposition)
+	if err == nil && mediaType == "attachment" {
+		return disposition
+	}
+
+	return mime.FormatMediaType("attachment", map[string]string{"filename": cmp.Or(params["filename"], path.Base(filePath))})
+}
+
+// inlineSafe reports whether browsers display files of contentType without running scripts.
+func inlineSafe(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	switch {
+	case mediaType == "image/svg+xml":
+		return false
+	case strings.HasPrefix(mediaType, "image/"), strings.HasPrefix(mediaType, "video/"), strings.HasPrefix(mediaType, "audio/"):
+		return true
+	}
+
+	switch mediaType {
+	case "text/plain", "text/csv", "application/pdf", "application/json", "application/octet-stream":
+		return true
+	}
+
+	return false
+}
+
+func serveError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, ErrFileNotFound) {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
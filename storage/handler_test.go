@@ -0,0 +1,189 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gonstruct/providers/adapters/encryption/aes_256_gcm"
+	"github.com/gonstruct/providers/adapters/storage/fake"
+	"github.com/gonstruct/providers/storage"
+)
+
+func serveRequest(handler http.Handler, method, target string, header http.Header) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, nil)
+	for name, values := range header {
+		request.Header[name] = values
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func TestHandler(t *testing.T) {
+	adapter := fake.New()
+	ctx := context.Background()
+
+	if err := adapter.Put(ctx, "videos/intro.mp4", []byte("0123456789")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	err := storage.Put("docs/report.pdf", []byte("report"), storage.WithAdapter(adapter),
+		storage.WithContentDisposition(`inline; filename="report.pdf"`), storage.WithCacheControl("private"))
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	handler := storage.Handler(storage.WithAdapter(adapter), storage.AsAttachment())
+
+	response := serveRequest(handler, http.MethodGet, "/videos/intro.mp4", nil)
+	if response.Code != http.StatusOK || response.Body.String() != "0123456789" {
+		t.Fatalf("GET = %d %q, want the file", response.Code, response.Body.String())
+	}
+
+	etag := response.Header().Get("ETag")
+	if etag == "" || response.Header().Get("Last-Modified") == "" || response.Header().Get("Content-Type") == "" {
+		t.Errorf("GET headers = %v, want the ETag, modification time and content type", response.Header())
+	}
+
+	if disposition := response.Header().Get("Content-Disposition"); disposition != "attachment; filename=intro.mp4" {
+		t.Errorf("Content-Disposition = %q, want an attachment", disposition)
+	}
+
+	response = serveRequest(handler, http.MethodGet, "/videos/intro.mp4", http.Header{"Range": {"bytes=2-4"}})
+	if response.Code != http.StatusPartialContent || response.Body.String() != "234" {
+		t.Errorf("GET range = %d %q, want 206 \"234\"", response.Code, response.Body.String())
+	}
+
+	response = serveRequest(handler, http.MethodGet, "/videos/intro.mp4", http.Header{"If-None-Match": {etag}})
+	if response.Code != http.StatusNotModified {
+		t.Errorf("GET If-None-Match = %d, want 304", response.Code)
+	}
+
+	response = serveRequest(handler, http.MethodGet, "/docs/report.pdf", nil)
+	if response.Header().Get("Content-Disposition") != `inline; filename="report.pdf"` || response.Header().Get("Cache-Control") != "private" {
+		t.Errorf("GET headers = %v, want the stored write options", response.Header())
+	}
+
+	response = serveRequest(handler, http.MethodHead, "/docs/report.pdf", nil)
+	if response.Code != http.StatusOK || response.Body.Len() != 0 || response.Header().Get("Content-Length") != "6" {
+		t.Errorf("HEAD = %d %q, want the headers only", response.Code, response.Body.String())
+	}
+
+	for _, target := range []string{"/docs/missing.pdf", "/../etc/passwd", "/"} {
+		if response := serveRequest(handler, http.MethodGet, target, nil); response.Code != http.StatusNotFound {
+			t.Errorf("GET %s = %d, want 404", target, response.Code)
+		}
+	}
+
+	if response := serveRequest(handler, http.MethodPost, "/docs/report.pdf", nil); response.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST = %d, want 405", response.Code)
+	}
+}
+
+func TestHandler_UnsafeContentType(t *testing.T) {
+	adapter := fake.New()
+
+	for name, contentType := range map[string]string{"page.html": "text/html", "logo.svg": "image/svg+xml", "photo.png": "image/png"} {
+		err := storage.Put("uploads/"+name, []byte("<script>alert(1)</script>"), storage.WithAdapter(adapter),
+			storage.WithContentType(contentType), storage.WithContentDisposition("inline"))
+		if err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	handler := storage.Handler(storage.WithAdapter(adapter))
+
+	for _, name := range []string{"page.html", "logo.svg"} {
+		response := serveRequest(handler, http.MethodGet, "/uploads/"+name, nil)
+
+		if policy := response.Header().Get("Content-Security-Policy"); policy != "sandbox" {
+			t.Errorf("GET %s Content-Security-Policy = %q, want sandbox", name, policy)
+		}
+
+		if disposition := response.Header().Get("Content-Disposition"); disposition != "attachment; filename="+name {
+			t.Errorf("GET %s Content-Disposition = %q, want an attachment", name, disposition)
+		}
+	}
+
+	response := serveRequest(handler, http.MethodGet, "/uploads/photo.png", nil)
+	if response.Header().Get("Content-Security-Policy") != "" || response.Header().Get("Content-Disposition") != "inline" {
+		t.Errorf("GET photo.png headers = %v, want it inline", response.Header())
+	}
+}
+
+func TestHandler_Signed(t *testing.T) {
+	adapter := fake.New()
+	if err := adapter.Put(context.Background(), "docs/report.pdf", []byte("report")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	signer := storage.NewURLSigner([]byte("0123456789abcdef0123456789abcdef"))
+	handler := http.StripPrefix("/files", storage.Handler(storage.WithAdapter(adapter), storage.WithSigner(signer)))
+
+	query, err := signer.Sign("docs/report.pdf", nil, time.Minute)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	if response := serveRequest(handler, http.MethodGet, "/files/docs/report.pdf?"+query.Encode(), nil); response.Code != http.StatusOK {
+		t.Errorf("GET signed = %d, want 200", response.Code)
+	}
+
+	for _, target := range []string{"/files/docs/report.pdf", "/files/docs/other.pdf?" + query.Encode()} {
+		if response := serveRequest(handler, http.MethodGet, target, nil); response.Code != http.StatusForbidden {
+			t.Errorf("GET %s = %d, want 403", target, response.Code)
+		}
+	}
+}
+
+func TestURLSigner(t *testing.T) {
+	signers := map[string]*storage.URLSigner{
+		"hmac":       storage.NewURLSigner([]byte("0123456789abcdef0123456789abcdef")),
+		"encryption": storage.NewEncryptionURLSigner(aes_256_gcm.Adapter{Key: func() []byte { return []byte("0123456789abcdef0123456789abcdef") }}),
+	}
+
+	for name, signer := range signers {
+		t.Run(name, func(t *testing.T) {
+			query, err := signer.Sign("docs//report.pdf", url.Values{"download": {"1"}}, time.Minute)
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+
+			// The path is normalized, and the parameters are part of the signature
+			if err := signer.Verify("docs/report.pdf", query); err != nil {
+				t.Errorf("Verify() error = %v", err)
+			}
+
+			tampered, _ := url.ParseQuery(query.Encode())
+			tampered.Set("download", "0")
+
+			if err := signer.Verify("docs/report.pdf", tampered); !errors.Is(err, storage.ErrInvalidSignature) {
+				t.Errorf("Verify() error = %v, want storage.ErrInvalidSignature for a changed parameter", err)
+			}
+
+			expired, err := signer.Sign("docs/report.pdf", nil, -time.Minute)
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+
+			if err := signer.Verify("docs/report.pdf", expired); !errors.Is(err, storage.ErrExpiredSignature) {
+				t.Errorf("Verify() error = %v, want storage.ErrExpiredSignature", err)
+			}
+
+			if err := signer.Verify("docs/report.pdf", url.Values{}); !errors.Is(err, storage.ErrInvalidSignature) {
+				t.Errorf("Verify() error = %v, want storage.ErrInvalidSignature without a signature", err)
+			}
+		})
+	}
+
+	if _, err := storage.NewURLSigner(nil).Sign("../secret", nil, time.Minute); !errors.Is(err, storage.ErrInvalidPath) {
+		t.Errorf("Sign() error = %v, want storage.ErrInvalidPath", err)
+	}
+}
@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+)
+
+// DefaultPageSize is the number of entries List fetches at once, the maximum S3 returns.
+const DefaultPageSize = 1000
+
+// Cursor iterates over the entries of a listing and fetches the next page when the current one is consumed.
+//
+//	cursor := storage.List("exports", storage.Recursive(), storage.WithPattern("*.csv"))
+//	for cursor.Next() {
+//	    fmt.Println(cursor.Entry().Path, cursor.Entry().Size)
+//	}
+//
+//	if err := cursor.Err(); err != nil {
+//	    return err
+//	}
+type Cursor struct {
+	ctx     context.Context
+	adapter contracts.Storage
+	input   entities.StorageListInput
+
+	page  []entities.StorageEntry
+	entry entities.StorageEntry
+	done  bool
+	err   error
+}
+
+// List returns a cursor over the entries of directory. Without options it lists the files and
+// directories directly in directory, see Recursive, WithPrefix, WithPattern and WithPageSize.
+func List(directory string, optionSlice ...Option) *Cursor {
+	options := apply(optionSlice...)
+
+	return &Cursor{
+		ctx:     options.Context,
+		adapter: options.Adapter,
+		input:   listInput(directory, options),
+	}
+}
+
+// Next advances to the next entry and reports whether there is one.
+func (cursor *Cursor) Next() bool {
+	for len(cursor.page) == 0 {
+		if cursor.done || cursor.err != nil {
+			return false
+		}
+
+		page, err := listPage(cursor.ctx, cursor.adapter, cursor.input)
+		if err != nil {
+			cursor.err = err
+
+			return false
+		}
+
+		cursor.page = page.Entries
+		cursor.input.PageToken = page.NextPageToken
+		cursor.done = page.NextPageToken == ""
+	}
+
+	cursor.entry, cursor.page = cursor.page[0], cursor.page[1:]
+
+	return true
+}
+
+// Entry returns the current entry.
+func (cursor *Cursor) Entry() entities.StorageEntry {
+	return cursor.entry
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (cursor *Cursor) Err() error {
+	return cursor.err
+}
+
+// ListPage returns a single page of the entries of directory, for APIs that paginate with the
+// NextPageToken of the page, see WithPageToken.
+func ListPage(directory string, optionSlice ...Option) (*entities.StoragePage, error) {
+	options := apply(optionSlice...)
+
+	return listPage(options.Context, options.Adapter, listInput(directory, options))
+}
+
+// Recursive lists the files of all subdirectories instead of the files and directories directly in the directory.
+func Recursive() Option {
+	return func(options *options) {
+		options.List.Recursive = true
+	}
+}
+
+// WithPrefix only lists entries whose path relative to the directory starts with prefix.
+func WithPrefix(prefix string) Option {
+	return func(options *options) {
+		options.List.Prefix = prefix
+	}
+}
+
+// WithPattern only lists entries whose name matches the pattern, like "*.csv", see path.Match.
+func WithPattern(pattern string) Option {
+	return func(options *options) {
+		options.List.Pattern = pattern
+	}
+}
+
+// WithPageSize sets the maximum number of entries fetched at once.
+func WithPageSize(size int) Option {
+	return func(options *options) {
+		options.List.PageSize = size
+	}
+}
+
+// WithPageToken continues a listing after the page that returned the token.
+func WithPageToken(token string) Option {
+	return func(options *options) {
+		options.List.PageToken = token
+	}
+}
+
+func listInput(directory string, options *options) entities.StorageListInput {
+	input := options.List
+	input.Directory = strings.TrimSuffix(directory, "/")
+
+	if input.PageSize <= 0 {
+		input.PageSize = DefaultPageSize
+	}
+
+	return input
+}
+
+func listPage(ctx context.Context, adapter contracts.Storage, input entities.StorageListInput) (*entities.StoragePage, error) {
+	if _, err := path.Match(input.Pattern, ""); err != nil {
+		return nil, PathErr("list", input.Directory, err)
+	}
+
+	if lister, ok := adapter.(contracts.StorageLister); ok {
+		return lister.List(ctx, input)
+	}
+
+	entries, err := listAll(ctx, adapter, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return input.Paginate(entries), nil
+}
+
+// listAll lists the entries of adapters without contracts.StorageLister with their Files and Directories methods.
+func listAll(ctx context.Context, adapter contracts.Storage, input entities.StorageListInput) ([]entities.StorageEntry, error) {
+	var entries []entities.StorageEntry
+
+	if input.Recursive {
+		files, err := adapter.AllFiles(ctx, input.Directory)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			entries = append(entries, entities.StorageEntry{Path: file})
+		}
+
+		return entries, nil
+	}
+
+	files, err := adapter.Files(ctx, input.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	directories, err := adapter.Directories(ctx, input.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		entries = append(entries, entities.StorageEntry{Path: file})
+	}
+
+	for _, directory := range directories {
+		entries = append(entries, entities.StorageEntry{Path: directory, IsDir: true})
+	}
+
+	return entries, nil
+}
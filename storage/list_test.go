@@ -0,0 +1,66 @@
+package storage_test
+
+import (
+	"errors"
+	"path"
+	"slices"
+	"testing"
+
+	"github.com/gonstruct/providers/storage"
+)
+
+func TestList(t *testing.T) {
+	storage.Fake()
+
+	for _, name := range []string{"exports/a.csv", "exports/b.pdf", "exports/2026/c.csv", "exports/2026/q1/d.csv", "other/e.csv"} {
+		if err := storage.Put(name, []byte(name)); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	var listed []string
+
+	cursor := storage.List("exports", storage.Recursive(), storage.WithPattern("*.csv"), storage.WithPageSize(1))
+	for cursor.Next() {
+		listed = append(listed, cursor.Entry().Path)
+	}
+
+	if err := cursor.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	if want := []string{"exports/2026/c.csv", "exports/2026/q1/d.csv", "exports/a.csv"}; !slices.Equal(listed, want) {
+		t.Errorf("List() = %v, want %v", listed, want)
+	}
+
+	page, err := storage.ListPage("exports", storage.WithPageSize(2))
+	if err != nil {
+		t.Fatalf("ListPage() error = %v", err)
+	}
+
+	if len(page.Entries) != 2 || page.Entries[0].Path != "exports/2026" || !page.Entries[0].IsDir || page.NextPageToken == "" {
+		t.Fatalf("ListPage() = %+v, want the directory first and a page token", page)
+	}
+
+	page, err = storage.ListPage("exports", storage.WithPageSize(2), storage.WithPageToken(page.NextPageToken))
+	if err != nil {
+		t.Fatalf("ListPage() error = %v", err)
+	}
+
+	if len(page.Entries) != 1 || page.Entries[0].Path != "exports/b.pdf" || page.NextPageToken != "" {
+		t.Errorf("ListPage() = %+v, want the last entry without a page token", page)
+	}
+}
+
+func TestList_InvalidPattern(t *testing.T) {
+	storage.Fake()
+
+	cursor := storage.List("exports", storage.WithPattern("[a-"))
+	if cursor.Next() {
+		t.Fatal("Next() = true, want false for an invalid pattern")
+	}
+
+	if !errors.Is(cursor.Err(), path.ErrBadPattern) {
+		t.Errorf("Err() = %v, want path.ErrBadPattern", cursor.Err())
+	}
+}
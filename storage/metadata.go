@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+)
+
+// Stat returns the metadata of the file at path, with a single request on adapters that implement contracts.StorageStater.
+func Stat(path string, optionSlice ...Option) (*entities.StorageMetadata, error) {
+	options := apply(optionSlice...)
+
+	if stater, ok := options.Adapter.(contracts.StorageStater); ok {
+		return stater.Stat(options.Context, path)
+	}
+
+	return stat(options.Context, options.Adapter, path)
+}
+
+// stat returns the metadata of adapters without contracts.StorageStater with their metadata methods.
+func stat(ctx context.Context, adapter contracts.Storage, path string) (*entities.StorageMetadata, error) {
+	size, err := adapter.Size(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	lastModified, err := adapter.LastModified(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType, err := adapter.MimeType(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	visibility, err := adapter.GetVisibility(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.StorageMetadata{
+		Path:         path,
+		Size:         size,
+		LastModified: lastModified,
+		ContentType:  contentType,
+		Visibility:   visibility,
+	}, nil
+}
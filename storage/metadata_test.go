@@ -0,0 +1,92 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gonstruct/providers/adapters/storage/fake"
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/storage"
+)
+
+func TestStat(t *testing.T) {
+	fakeAdapter := storage.Fake()
+
+	err := storage.PutStream("exports/report.csv", strings.NewReader("a,b"),
+		storage.WithMetadata(map[string]string{"Owner": "finance"}),
+		storage.WithCacheControl("no-cache"),
+		storage.WithContentDisposition(`attachment; filename="report.csv"`),
+	)
+	if err != nil {
+		t.Fatalf("PutStream() error = %v", err)
+	}
+
+	fakeAdapter.AssertStoredWithMetadata(t, "exports/report.csv", "owner", "finance")
+
+	metadata, err := storage.Stat("exports/report.csv")
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+
+	if metadata.Size != 3 || metadata.ETag == "" || metadata.Checksum == "" || metadata.CacheControl != "no-cache" ||
+		metadata.ContentDisposition != `attachment; filename="report.csv"` {
+		t.Errorf("Stat() = %+v, want the size, ETag, checksum and write options", metadata)
+	}
+}
+
+func TestStat_Fallback(t *testing.T) {
+	adapter := fake.New()
+	if err := adapter.Put(context.Background(), "notes.txt", []byte("notes")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	// Hide the optional interfaces of the fake
+	basic := struct{ contracts.Storage }{adapter}
+
+	metadata, err := storage.Stat("notes.txt", storage.WithAdapter(basic))
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+
+	if metadata.Size != 5 || metadata.LastModified.IsZero() || metadata.Visibility == "" {
+		t.Errorf("Stat() = %+v, want the metadata of the Size, LastModified, MimeType and GetVisibility methods", metadata)
+	}
+
+	err = storage.Put("notes.txt", []byte("notes"), storage.WithAdapter(basic), storage.WithCacheControl("no-cache"))
+	if !errors.Is(err, storage.ErrNotSupported) {
+		t.Errorf("Put() error = %v, want storage.ErrNotSupported for write options", err)
+	}
+}
+
+func TestPut_WriteOptions(t *testing.T) {
+	storage.Fake()
+
+	err := storage.Put("avatars/1", []byte("png"), storage.WithContentType("image/png"), storage.WithVisibility(entities.VisibilityPublic))
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	metadata, err := storage.Stat("avatars/1")
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+
+	if metadata.ContentType != "image/png" || metadata.Visibility != entities.VisibilityPublic {
+		t.Errorf("Stat() = %+v, want a public image/png", metadata)
+	}
+
+	if err := storage.Put("avatars/1", []byte("png"), storage.IfNotExists()); !errors.Is(err, storage.ErrAlreadyExists) {
+		t.Errorf("Put() error = %v, want storage.ErrAlreadyExists", err)
+	}
+
+	if err := storage.Put("avatars/1", []byte("jpg"), storage.IfMatch(`"stale"`)); !errors.Is(err, storage.ErrPreconditionFailed) {
+		t.Errorf("Put() error = %v, want storage.ErrPreconditionFailed", err)
+	}
+
+	if err := storage.Put("avatars/1", []byte("jpg"), storage.IfMatch(metadata.ETag)); err != nil {
+		t.Errorf("Put() error = %v, want the file overwritten when the ETag matches", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
 	"github.com/google/uuid"
 )
 
@@ -11,6 +12,12 @@ type options struct {
 	Context          context.Context
 	Adapter          contracts.Storage
 	GenerateUniqueID func() string
+	List             entities.StorageListInput
+	Write            entities.StorageWriteOptions
+	Signer           *URLSigner
+	Attachment       bool
+	Upload           entities.StorageUploadInput
+	OnUpload         func(ctx context.Context, metadata *entities.StorageMetadata) error
 }
 
 type Option func(*options)
@@ -18,10 +25,14 @@ type Option func(*options)
 func apply(optionSlice ...Option) *options {
 	options := &options{
 		Context:          context.Background(),
-		Adapter:          globalProvider.adapter,
 		GenerateUniqueID: func() string { return uuid.NewString() },
 	}
 
+	// WithAdapter works without a global adapter
+	if globalProvider != nil {
+		options.Adapter = globalProvider.adapter
+	}
+
 	for _, option := range optionSlice {
 		option(options)
 	}
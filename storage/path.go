@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"github.com/gonstruct/providers/entities"
+)
+
+// NormalizePath returns the clean, slash separated form of a path relative to the root of a disk,
+// see entities.NormalizeStoragePath. Adapters normalize every path with it, so a path refers to
+// the same file on every disk, and paths that would escape the root fail with ErrInvalidPath.
+func NormalizePath(path string) (string, error) {
+	normalized, err := entities.NormalizeStoragePath(path)
+	if err != nil {
+		return "", PathErr("normalize", path, err)
+	}
+
+	return normalized, nil
+}
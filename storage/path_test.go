@@ -0,0 +1,62 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gonstruct/providers/storage"
+)
+
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    string
+		invalid bool
+	}{
+		{path: "reports/q1.csv", want: "reports/q1.csv"},
+		{path: "./reports//q1.csv", want: "reports/q1.csv"},
+		{path: "reports/2026/../q1.csv", want: "reports/q1.csv"},
+		{path: "reports/", want: "reports"},
+		{path: "", want: ""},
+		{path: ".", want: ""},
+		{path: "/etc/passwd", invalid: true},
+		{path: "../../etc/passwd", invalid: true},
+		{path: "reports/../../secret", invalid: true},
+		{path: "..", invalid: true},
+		{path: "reports\\..\\..\\secret", invalid: true},
+		{path: "q1.csv\x00.png", invalid: true},
+	}
+
+	for _, tt := range tests {
+		got, err := storage.NormalizePath(tt.path)
+		if tt.invalid {
+			if !errors.Is(err, storage.ErrInvalidPath) {
+				t.Errorf("NormalizePath(%q) error = %v, want storage.ErrInvalidPath", tt.path, err)
+			}
+
+			continue
+		}
+
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizePath(%q) = %q, %v, want %q", tt.path, got, err, tt.want)
+		}
+	}
+}
+
+func TestNormalizePath_Adapters(t *testing.T) {
+	fake := storage.Fake()
+
+	if err := storage.Put("./reports//q1.csv", []byte("q1")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	fake.AssertStored(t, "reports/q1.csv")
+
+	if _, err := storage.Get("reports/2026/../q1.csv"); err != nil {
+		t.Errorf("Get() error = %v, want the normalized path to refer to the same file", err)
+	}
+
+	if err := storage.Put("../outside.csv", []byte("q1")); !errors.Is(err, storage.ErrInvalidPath) {
+		t.Errorf("Put() error = %v, want storage.ErrInvalidPath", err)
+	}
+}
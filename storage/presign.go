@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+)
+
+// TemporaryUploadURL returns a presigned PUT request that uploads a file to path until it expires
+// after expiration, so clients upload it straight to the storage. WithContentType, WithContentLength,
+// WithMaxSize and WithVisibility constrain the upload. S3 cannot limit the size of PUT uploads with
+// WithMaxSize, use TemporaryUploadForm or WithContentLength.
+func TemporaryUploadURL(path string, expiration time.Duration, optionSlice ...Option) (*entities.StorageUpload, error) {
+	options := apply(optionSlice...)
+
+	uploader, ok := options.Adapter.(contracts.StorageUploader)
+	if !ok {
+		return nil, PathErr("temporary upload url", path, ErrNotSupported)
+	}
+
+	return uploader.TemporaryUploadURL(options.Context, uploadInput(path, expiration, options))
+}
+
+// TemporaryUploadForm returns a presigned POST request for an HTML form that uploads a file to path,
+// see TemporaryUploadURL.
+func TemporaryUploadForm(path string, expiration time.Duration, optionSlice ...Option) (*entities.StorageUpload, error) {
+	options := apply(optionSlice...)
+
+	uploader, ok := options.Adapter.(contracts.StorageUploader)
+	if !ok {
+		return nil, PathErr("temporary upload form", path, ErrNotSupported)
+	}
+
+	return uploader.TemporaryUploadForm(options.Context, uploadInput(path, expiration, options))
+}
+
+// CompleteUpload checks a file that a client uploaded with a presigned request against the
+// constraints of the options the request was presigned with, when the client reports that it is
+// done. It deletes files that do not meet them and returns ErrUploadRejected.
+//
+//	metadata, err := storage.CompleteUpload("avatars/42.png", storage.WithContentType("image/png"), storage.WithMaxSize(1<<20))
+func CompleteUpload(path string, optionSlice ...Option) (*entities.StorageMetadata, error) {
+	options := apply(optionSlice...)
+	input := uploadInput(path, 0, options)
+
+	metadata, err := Stat(path, optionSlice...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkUpload(options.Context, options.Adapter, input, metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// WithContentLength makes presigned uploads only accept files of exactly size bytes.
+func WithContentLength(size int64) Option {
+	return func(options *options) {
+		options.Upload.ContentLength = size
+	}
+}
+
+// WithMaxSize makes presigned uploads only accept files of at most size bytes.
+func WithMaxSize(size int64) Option {
+	return func(options *options) {
+		options.Upload.MaxSize = size
+	}
+}
+
+func uploadInput(path string, expiration time.Duration, options *options) entities.StorageUploadInput {
+	return entities.StorageUploadInput{
+		Path:          path,
+		Expiration:    expiration,
+		ContentType:   options.Write.ContentType,
+		ContentLength: options.Upload.ContentLength,
+		MaxSize:       options.Upload.MaxSize,
+		Visibility:    options.Write.Visibility,
+	}
+}
+
+// checkUpload deletes an uploaded file that does not meet the constraints of input.
+func checkUpload(ctx context.Context, adapter contracts.Storage, input entities.StorageUploadInput, metadata *entities.StorageMetadata) error {
+	err := input.CheckContentType(metadata.ContentType)
+	if err == nil {
+		err = input.CheckSize(metadata.Size)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	if deleteErr := adapter.Delete(ctx, metadata.Path); deleteErr != nil {
+		return PathErr("delete rejected upload", metadata.Path, deleteErr)
+	}
+
+	return PathErr("complete upload", metadata.Path, err)
+}
@@ -0,0 +1,168 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gonstruct/providers/adapters/storage/fake"
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/storage"
+)
+
+func TestTemporaryUploadURL(t *testing.T) {
+	adapter := fake.New()
+
+	upload, err := storage.TemporaryUploadURL("avatars/42.png", time.Minute, storage.WithAdapter(adapter),
+		storage.WithContentType("image/png"), storage.WithMaxSize(1024))
+	if err != nil {
+		t.Fatalf("TemporaryUploadURL() error = %v", err)
+	}
+
+	if upload.Method != http.MethodPut || upload.URL == "" {
+		t.Errorf("TemporaryUploadURL() = %+v, want a PUT request", upload)
+	}
+
+	adapter.AssertUploadPresigned(t, "avatars/42.png")
+
+	if input := adapter.UploadCalls[0]; input.ContentType != "image/png" || input.MaxSize != 1024 || input.Expiration != time.Minute {
+		t.Errorf("TemporaryUploadURL() input = %+v, want the constraints", input)
+	}
+
+	// Hide the optional interfaces of the fake
+	basic := struct{ contracts.Storage }{adapter}
+
+	if _, err := storage.TemporaryUploadForm("avatars/42.png", time.Minute, storage.WithAdapter(basic)); !errors.Is(err, storage.ErrNotSupported) {
+		t.Errorf("TemporaryUploadForm() error = %v, want storage.ErrNotSupported", err)
+	}
+}
+
+func TestCompleteUpload(t *testing.T) {
+	adapter := fake.New()
+
+	if err := storage.Put("avatars/42.png", make([]byte, 2048), storage.WithAdapter(adapter), storage.WithContentType("image/png")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	metadata, err := storage.CompleteUpload("avatars/42.png", storage.WithAdapter(adapter), storage.WithContentType("image/png"))
+	if err != nil || metadata.Size != 2048 {
+		t.Fatalf("CompleteUpload() = %+v, %v, want the metadata", metadata, err)
+	}
+
+	_, err = storage.CompleteUpload("avatars/42.png", storage.WithAdapter(adapter), storage.WithMaxSize(1024))
+	if !errors.Is(err, storage.ErrUploadRejected) {
+		t.Errorf("CompleteUpload() error = %v, want storage.ErrUploadRejected", err)
+	}
+
+	adapter.AssertDeleted(t, "avatars/42.png")
+}
+
+func TestUploadHandler(t *testing.T) {
+	adapter := fake.New()
+	signer := storage.NewURLSigner([]byte("0123456789abcdef0123456789abcdef"))
+
+	var uploaded []string
+
+	handler := http.StripPrefix("/files", storage.UploadHandler(storage.WithAdapter(adapter), storage.WithSigner(signer),
+		storage.WithUploadCallback(func(ctx context.Context, metadata *entities.StorageMetadata) error {
+			uploaded = append(uploaded, metadata.Path)
+
+			return nil
+		})))
+
+	signed := func(method, path string) string {
+		query, err := signer.SignUpload(method, entities.StorageUploadInput{
+			Path: path, Expiration: time.Minute, ContentType: "text/plain", MaxSize: 5,
+		})
+		if err != nil {
+			t.Fatalf("SignUpload() error = %v", err)
+		}
+
+		return "/files/" + path + "?" + query.Encode()
+	}
+
+	put := func(target, contentType, body string) int {
+		request := httptest.NewRequest(http.MethodPut, target, strings.NewReader(body))
+		request.Header.Set("Content-Type", contentType)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		return recorder.Code
+	}
+
+	if code := put(signed(http.MethodPut, "notes/a.txt"), "text/plain", "hello"); code != http.StatusNoContent {
+		t.Errorf("PUT = %d, want 204", code)
+	}
+
+	adapter.AssertStoredContent(t, "notes/a.txt", []byte("hello"))
+
+	if code := put(signed(http.MethodPut, "notes/b.txt"), "text/html", "hello"); code != http.StatusBadRequest {
+		t.Errorf("PUT with another content type = %d, want 400", code)
+	}
+
+	if code := put(signed(http.MethodPut, "notes/c.txt"), "text/plain", "hello, world"); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("PUT too large = %d, want 413", code)
+	}
+
+	adapter.AssertNotStored(t, "notes/c.txt")
+
+	unsigned, _ := signer.Sign("notes/d.txt", nil, time.Minute)
+
+	for _, target := range []string{"/files/notes/d.txt", "/files/notes/d.txt?" + unsigned.Encode(), signed(http.MethodPost, "notes/d.txt")} {
+		if code := put(target, "text/plain", "hello"); code != http.StatusForbidden {
+			t.Errorf("PUT %s = %d, want 403", target, code)
+		}
+	}
+
+	var form bytes.Buffer
+
+	writer := multipart.NewWriter(&form)
+	_ = writer.WriteField("Content-Type", "text/plain")
+	part, _ := writer.CreateFormFile("file", "e.txt")
+	_, _ = part.Write([]byte("form"))
+	_ = writer.Close()
+
+	request := httptest.NewRequest(http.MethodPost, signed(http.MethodPost, "notes/e.txt"), &form)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("POST = %d, want 204", recorder.Code)
+	}
+
+	adapter.AssertStoredContent(t, "notes/e.txt", []byte("form"))
+
+	if len(uploaded) != 2 || uploaded[0] != "notes/a.txt" || uploaded[1] != "notes/e.txt" {
+		t.Errorf("callback paths = %v, want the stored files", uploaded)
+	}
+
+	// Without a signed content type the content type of the extension is stored
+	query, err := signer.SignUpload(http.MethodPut, entities.StorageUploadInput{Path: "notes/f.png", Expiration: time.Minute})
+	if err != nil {
+		t.Fatalf("SignUpload() error = %v", err)
+	}
+
+	if code := put("/files/notes/f.png?"+query.Encode(), "text/html", "<p>"); code != http.StatusNoContent {
+		t.Errorf("PUT without content type = %d, want 204", code)
+	}
+
+	if metadata, err := storage.Stat("notes/f.png", storage.WithAdapter(adapter)); err != nil || metadata.ContentType != "image/png" {
+		t.Errorf("Stat() = %v, %v, want the content type of the extension", metadata, err)
+	}
+
+	// Upload URLs do not allow downloads
+	download := storage.Handler(storage.WithAdapter(adapter), storage.WithSigner(signer))
+	if response := serveRequest(download, http.MethodGet, strings.TrimPrefix(signed(http.MethodPut, "notes/a.txt"), "/files"), nil); response.Code != http.StatusForbidden {
+		t.Errorf("GET upload URL = %d, want 403", response.Code)
+	}
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/gonstruct/providers/contracts"
+)
+
+// GetRange returns a reader for length bytes of the file at path from offset, or for the rest of
+// the file when length is negative. It fails with ErrInvalidRange when offset is beyond the end.
+// Adapters without contracts.StorageRangeReader read and discard the bytes before offset.
+func GetRange(path string, offset, length int64, optionSlice ...Option) (io.ReadCloser, error) {
+	options := apply(optionSlice...)
+
+	if reader, ok := options.Adapter.(contracts.StorageRangeReader); ok {
+		return reader.GetRange(options.Context, path, offset, length)
+	}
+
+	return getRange(options.Context, options.Adapter, path, offset, length)
+}
+
+// Open returns a seekable reader for the file at path, which can back http.ServeContent:
+//
+//	file, err := storage.Open("videos/intro.mp4")
+//	if err != nil {
+//	    return err
+//	}
+//	defer file.Close()
+//
+//	http.ServeContent(w, r, "intro.mp4", lastModified, file)
+//
+// Adapters without contracts.StorageRangeReader read the whole file into memory.
+func Open(path string, optionSlice ...Option) (io.ReadSeekCloser, error) {
+	options := apply(optionSlice...)
+
+	if reader, ok := options.Adapter.(contracts.StorageRangeReader); ok {
+		return reader.Open(options.Context, path)
+	}
+
+	content, err := options.Adapter.Get(options.Context, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return readSeekNopCloser{bytes.NewReader(content)}, nil
+}
+
+func getRange(ctx context.Context, adapter contracts.Storage, path string, offset, length int64) (io.ReadCloser, error) {
+	if offset < 0 {
+		return nil, PathErr("get range", path, ErrInvalidRange)
+	}
+
+	stream, err := adapter.GetStream(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.CopyN(io.Discard, stream, offset); err != nil {
+		stream.Close()
+
+		if errors.Is(err, io.EOF) {
+			return nil, PathErr("get range", path, ErrInvalidRange)
+		}
+
+		return nil, PathErr("get range", path, err)
+	}
+
+	if length < 0 {
+		return stream, nil
+	}
+
+	return limitedReadCloser{io.LimitReader(stream, length), stream}, nil
+}
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+type readSeekNopCloser struct {
+	*bytes.Reader
+}
+
+func (readSeekNopCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,64 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gonstruct/providers/adapters/storage/fake"
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/storage"
+)
+
+func TestGetRange_Open(t *testing.T) {
+	adapter := fake.New()
+	if err := adapter.Put(context.Background(), "videos/intro.mp4", []byte("0123456789")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	adapters := map[string]contracts.Storage{
+		"range reader": adapter,
+		// Hide the optional interfaces of the fake
+		"fallback": struct{ contracts.Storage }{adapter},
+	}
+
+	for name, adapter := range adapters {
+		t.Run(name, func(t *testing.T) {
+			reader, err := storage.GetRange("videos/intro.mp4", 2, 3, storage.WithAdapter(adapter))
+			if err != nil {
+				t.Fatalf("GetRange() error = %v", err)
+			}
+
+			got, _ := io.ReadAll(reader)
+			reader.Close()
+
+			if string(got) != "234" {
+				t.Errorf("GetRange() = %q, want 234", got)
+			}
+
+			if _, err := storage.GetRange("videos/intro.mp4", 11, -1, storage.WithAdapter(adapter)); !errors.Is(err, storage.ErrInvalidRange) {
+				t.Errorf("GetRange() error = %v, want storage.ErrInvalidRange", err)
+			}
+
+			file, err := storage.Open("videos/intro.mp4", storage.WithAdapter(adapter))
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			defer file.Close()
+
+			request := httptest.NewRequest(http.MethodGet, "/intro.mp4", nil)
+			request.Header.Set("Range", "bytes=7-")
+
+			recorder := httptest.NewRecorder()
+			http.ServeContent(recorder, request, "intro.mp4", time.Time{}, file)
+
+			if recorder.Code != http.StatusPartialContent || recorder.Body.String() != "789" {
+				t.Errorf("ServeContent() = %d %q, want 206 \"789\"", recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
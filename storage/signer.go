@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+)
+
+// The query parameters of signed URLs.
+const (
+	ExpiresParameter   = "expires"
+	SignatureParameter = "signature"
+	// UploadParameter is the method of signed upload URLs, see SignUpload
+	UploadParameter = "upload"
+
+	contentTypeParameter   = "content-type"
+	contentLengthParameter = "content-length"
+	maxSizeParameter       = "max-size"
+	visibilityParameter    = "visibility"
+)
+
+// URLSigner signs the query of URLs for storage paths with an expiry, so Handler only serves
+// the URLs the application issued until they expire. The signature covers the normalized
+// storage path instead of the URL path, so handlers can be mounted below any prefix.
+type URLSigner struct {
+	sign   func(payload []byte) (string, error)
+	verify func(payload []byte, signature string) bool
+}
+
+// NewURLSigner returns a signer that signs with an HMAC-SHA256 of key, which should be at least 32 random bytes.
+func NewURLSigner(key []byte) *URLSigner {
+	mac := func(payload []byte) []byte {
+		hash := hmac.New(sha256.New, key)
+		hash.Write(payload)
+
+		return hash.Sum(nil)
+	}
+
+	return &URLSigner{
+		sign: func(payload []byte) (string, error) {
+			return base64.RawURLEncoding.EncodeToString(mac(payload)), nil
+		},
+		verify: func(payload []byte, signature string) bool {
+			decoded, err := base64.RawURLEncoding.DecodeString(signature)
+
+			return err == nil && hmac.Equal(decoded, mac(payload))
+		},
+	}
+}
+
+// NewEncryptionURLSigner returns a signer that signs with an encryption adapter, the signature is
+// an empty ciphertext with the signed URL as additional authenticated data, like AES-256-GCM
+// produces. It shares the key of the encryption adapter, and its key rotation.
+func NewEncryptionURLSigner(encryption contracts.Encryption) *URLSigner {
+	return &URLSigner{
+		sign: func(payload []byte) (string, error) {
+			return encryption.Encrypt(nil, payload)
+		},
+		verify: func(payload []byte, signature string) bool {
+			_, err := encryption.Decrypt(signature, payload)
+
+			return err == nil
+		},
+	}
+}
+
+// Sign returns query with the expiry and signature of a URL for path that expires after
+// expiration. The signature covers all parameters of query.
+func (signer *URLSigner) Sign(path string, query url.Values, expiration time.Duration) (url.Values, error) {
+	path, err := NormalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := url.Values{}
+
+	for name, values := range query {
+		if name != SignatureParameter {
+			signed[name] = append([]string(nil), values...)
+		}
+	}
+
+	signed.Set(ExpiresParameter, strconv.FormatInt(time.Now().Add(expiration).Unix(), 10))
+
+	signature, err := signer.sign(signaturePayload(path, signed))
+	if err != nil {
+		return nil, PathErr("sign URL", path, err)
+	}
+
+	signed.Set(SignatureParameter, signature)
+
+	return signed, nil
+}
+
+// Verify checks the signature and expiry of the query of a URL for path, it returns
+// ErrInvalidSignature or ErrExpiredSignature when the URL may not be served.
+func (signer *URLSigner) Verify(path string, query url.Values) error {
+	path, err := NormalizePath(path)
+	if err != nil {
+		return err
+	}
+
+	signature := query.Get(SignatureParameter)
+	if signature == "" {
+		return PathErr("verify URL", path, ErrInvalidSignature)
+	}
+
+	signed := url.Values{}
+
+	for name, values := range query {
+		if name != SignatureParameter {
+			signed[name] = values
+		}
+	}
+
+	if !signer.verify(signaturePayload(path, signed), signature) {
+		return PathErr("verify URL", path, ErrInvalidSignature)
+	}
+
+	expires, err := strconv.ParseInt(signed.Get(ExpiresParameter), 10, 64)
+	if err != nil {
+		return PathErr("verify URL", path, ErrInvalidSignature)
+	}
+
+	if time.Now().Unix() > expires {
+		return PathErr("verify URL", path, ErrExpiredSignature)
+	}
+
+	return nil
+}
+
+// SignUpload returns the query of a URL that uploads a file with method, PUT or POST, to UploadHandler.
+// The query holds the constraints of input, which UploadHandler enforces, and expires after input.Expiration.
+func (signer *URLSigner) SignUpload(method string, input entities.StorageUploadInput) (url.Values, error) {
+	query := url.Values{UploadParameter: {method}}
+
+	if input.ContentType != "" {
+		query.Set(contentTypeParameter, input.ContentType)
+	}
+
+	if input.ContentLength > 0 {
+		query.Set(contentLengthParameter, strconv.FormatInt(input.ContentLength, 10))
+	}
+
+	if input.MaxSize > 0 {
+		query.Set(maxSizeParameter, strconv.FormatInt(input.MaxSize, 10))
+	}
+
+	if input.Visibility != "" {
+		query.Set(visibilityParameter, string(input.Visibility))
+	}
+
+	return signer.Sign(input.Path, query, input.Expiration)
+}
+
+// uploadConstraints returns the constraints of a signed upload URL, see SignUpload.
+func uploadConstraints(path string, query url.Values) entities.StorageUploadInput {
+	contentLength, _ := strconv.ParseInt(query.Get(contentLengthParameter), 10, 64)
+	maxSize, _ := strconv.ParseInt(query.Get(maxSizeParameter), 10, 64)
+
+	return entities.StorageUploadInput{
+		Path:          path,
+		ContentType:   query.Get(contentTypeParameter),
+		ContentLength: contentLength,
+		MaxSize:       maxSize,
+		Visibility:    entities.Visibility(query.Get(visibilityParameter)),
+	}
+}
+
+// signaturePayload is the signed form of a URL, the parameters are sorted by url.Values.Encode.
+func signaturePayload(path string, query url.Values) []byte {
+	return []byte(fmt.Sprintf("%s\n%s", path, query.Encode()))
+}
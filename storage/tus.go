@@ -0,0 +1,393 @@
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gonstruct/providers/contracts"
+)
+
+// TusVersion is the version of the tus resumable upload protocol TusServer implements.
+const TusVersion = "1.0.0"
+
+const (
+	// DefaultTusExpiration is how long uploads can be resumed after their last request.
+	DefaultTusExpiration = 24 * time.Hour
+	// DefaultTusPartSize is the size of the parts uploads are stored in.
+	DefaultTusPartSize = 8 << 20
+)
+
+var errTusNotFound = errors.New("tus upload not found")
+
+// TusServer is an http.Handler that implements the tus resumable upload protocol 1.0, see https://tus.io,
+// with the creation, termination and expiration extensions, so clients resume interrupted uploads instead
+// of restarting them. The state and the parts of unfinished uploads are stored on the adapter below
+// StateDirectory, so any process with the adapter can resume them. Finished uploads are stored like
+// PutFile stores files, below Directory with the upload ID and the extension of the "filename" metadata
+// as name, with the write options of the server. With WithContentType the server rejects uploads whose
+// "filetype" metadata has another content type, the others are stored with the content type of their
+// extension. On adapters that implement
+// contracts.StorageMultipartUploader, like S3, the parts go straight into a multipart upload.
+//
+//	server := storage.NewTusServer("videos", storage.WithMaxSize(10<<30), storage.WithUploadCallback(onUpload))
+//	mux.Handle("/uploads/", http.StripPrefix("/uploads", server))
+//
+// A PATCH request locks its upload within the process, route the requests of an upload to one process.
+type TusServer struct {
+	// Directory is the directory finished uploads are stored in
+	Directory string
+	// StateDirectory is the directory the state and parts of unfinished uploads are stored in
+	StateDirectory string
+	// Expiration is how long uploads can be resumed after their last request
+	Expiration time.Duration
+	// PartSize is the size of the parts uploads are stored in, at least the minimum part size of
+	// multipart uploads. Every PATCH request buffers a part in memory.
+	PartSize int64
+
+	options *options
+	locks   sync.Map
+}
+
+// NewTusServer returns a server that stores finished uploads below directory. WithAdapter,
+// WithMaxSize, WithUploadCallback, WithUniqueIDGenerator and the write options apply to it.
+func NewTusServer(directory string, optionSlice ...Option) *TusServer {
+	return &TusServer{
+		Directory:      directory,
+		StateDirectory: ".tus",
+		Expiration:     DefaultTusExpiration,
+		PartSize:       DefaultTusPartSize,
+		options:        apply(optionSlice...),
+	}
+}
+
+func (server *TusServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	header := w.Header()
+	header.Set("Tus-Resumable", TusVersion)
+
+	// For clients that cannot send PATCH or DELETE requests
+	if override := r.Header.Get("X-HTTP-Method-Override"); override != "" {
+		r.Method = override
+	}
+
+	if r.Method == http.MethodOptions {
+		header.Set("Tus-Version", TusVersion)
+		header.Set("Tus-Extension", "creation,termination,expiration")
+
+		if server.options.Upload.MaxSize > 0 {
+			header.Set("Tus-Max-Size", strconv.FormatInt(server.options.Upload.MaxSize, 10))
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	if r.Header.Get("Tus-Resumable") != TusVersion {
+		header.Set("Tus-Version", TusVersion)
+		tusError(w, http.StatusPreconditionFailed)
+
+		return
+	}
+
+	id := strings.Trim(r.URL.Path, "/")
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		server.create(w, r)
+	case id == "":
+		header.Set("Allow", "OPTIONS, POST")
+		tusError(w, http.StatusMethodNotAllowed)
+	case !validTusID(id):
+		tusError(w, http.StatusNotFound)
+	case r.Method == http.MethodHead:
+		server.head(w, r, id)
+	case r.Method == http.MethodPatch:
+		server.patch(w, r, id)
+	case r.Method == http.MethodDelete:
+		server.terminate(w, r, id)
+	default:
+		header.Set("Allow", "OPTIONS, HEAD, PATCH, DELETE")
+		tusError(w, http.StatusMethodNotAllowed)
+	}
+}
+
+// create handles the POST requests of the creation extension.
+func (server *TusServer) create(w http.ResponseWriter, r *http.Request) {
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		tusError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	if maxSize := server.options.Upload.MaxSize; maxSize > 0 && length > maxSize {
+		tusError(w, http.StatusRequestEntityTooLarge)
+
+		return
+	}
+
+	metadata, err := parseTusMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		tusError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	upload, err := server.start(r.Context(), length, metadata)
+	if errors.Is(err, ErrUploadRejected) {
+		tusError(w, http.StatusUnsupportedMediaType)
+
+		return
+	}
+
+	if err != nil {
+		tusError(w, http.StatusInternalServerError)
+
+		return
+	}
+
+	// Empty uploads are complete without a PATCH request
+	if length == 0 {
+		if err := server.finish(r.Context(), upload); err != nil {
+			tusError(w, http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	w.Header().Set("Location", tusLocation(r, upload.ID))
+	server.setExpires(w, upload)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// head handles the HEAD requests clients resume uploads with.
+func (server *TusServer) head(w http.ResponseWriter, r *http.Request, id string) {
+	upload, ok := server.find(w, r, id)
+	if !ok {
+		return
+	}
+
+	header := w.Header()
+	header.Set("Cache-Control", "no-store")
+	header.Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	header.Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+
+	if len(upload.Metadata) > 0 {
+		header.Set("Upload-Metadata", formatTusMetadata(upload.Metadata))
+	}
+
+	server.setExpires(w, upload)
+	w.WriteHeader(http.StatusOK)
+}
+
+// patch handles the PATCH requests that append to uploads.
+func (server *TusServer) patch(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Header.Get("Content-Type") != "application/offset+octet-stream" {
+		tusError(w, http.StatusUnsupportedMediaType)
+
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		tusError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	unlock, ok := server.lock(id)
+	if !ok {
+		tusError(w, http.StatusLocked)
+
+		return
+	}
+	defer unlock()
+
+	upload, ok := server.find(w, r, id)
+	if !ok {
+		return
+	}
+
+	if offset != upload.Offset {
+		tusError(w, http.StatusConflict)
+
+		return
+	}
+
+	if r.ContentLength > upload.Length-upload.Offset {
+		tusError(w, http.StatusRequestEntityTooLarge)
+
+		return
+	}
+
+	ctx := r.Context()
+	upload.ExpiresAt = time.Now().Add(server.Expiration)
+
+	if err := server.receive(ctx, upload, r.Body); err != nil {
+		tusError(w, http.StatusInternalServerError)
+
+		return
+	}
+
+	if upload.Offset == upload.Length && !upload.Completed {
+		if err := server.finish(ctx, upload); err != nil {
+			tusError(w, http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	server.setExpires(w, upload)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// terminate handles the DELETE requests of the termination extension. Finished files are kept.
+func (server *TusServer) terminate(w http.ResponseWriter, r *http.Request, id string) {
+	unlock, ok := server.lock(id)
+	if !ok {
+		tusError(w, http.StatusLocked)
+
+		return
+	}
+	defer unlock()
+
+	upload, ok := server.find(w, r, id)
+	if !ok {
+		return
+	}
+
+	if err := server.remove(r.Context(), upload); err != nil {
+		tusError(w, http.StatusInternalServerError)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// find loads an upload and responds with 404 Not Found for unknown uploads, and with 410 Gone
+// for expired uploads, which it removes.
+func (server *TusServer) find(w http.ResponseWriter, r *http.Request, id string) (*tusUpload, bool) {
+	upload, err := server.load(r.Context(), id)
+	if errors.Is(err, errTusNotFound) {
+		tusError(w, http.StatusNotFound)
+
+		return nil, false
+	}
+
+	if err != nil {
+		tusError(w, http.StatusInternalServerError)
+
+		return nil, false
+	}
+
+	if upload.expired(time.Now()) {
+		_ = server.remove(r.Context(), upload)
+		tusError(w, http.StatusGone)
+
+		return nil, false
+	}
+
+	return upload, true
+}
+
+func (server *TusServer) setExpires(w http.ResponseWriter, upload *tusUpload) {
+	if !upload.Completed {
+		w.Header().Set("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+// lock locks an upload for a request, it fails when another request holds the lock.
+func (server *TusServer) lock(id string) (func(), bool) {
+	value, _ := server.locks.LoadOrStore(id, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+
+	if !mu.TryLock() {
+		return nil, false
+	}
+
+	return mu.Unlock, true
+}
+
+func (server *TusServer) multipart() (contracts.StorageMultipartUploader, bool) {
+	multipart, ok := server.options.Adapter.(contracts.StorageMultipartUploader)
+
+	return multipart, ok
+}
+
+// validTusID reports whether id is a single path segment, which keeps the state of uploads in StateDirectory.
+func validTusID(id string) bool {
+	normalized, err := NormalizePath(id)
+
+	return err == nil && normalized == id && !strings.ContainsAny(id, "/.")
+}
+
+// tusLocation returns the URL of an upload below the URL of the creation request, before any http.StripPrefix.
+func tusLocation(r *http.Request, id string) string {
+	base := r.URL.Path
+	if parsed, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		base = parsed.Path
+	}
+
+	return strings.TrimSuffix(base, "/") + "/" + id
+}
+
+func tusError(w http.ResponseWriter, status int) {
+	http.Error(w, http.StatusText(status), status)
+}
+
+// parseTusMetadata parses an Upload-Metadata header, comma separated keys with base64 encoded values.
+func parseTusMetadata(header string) (map[string]string, error) {
+	metadata := map[string]string{}
+
+	if strings.TrimSpace(header) == "" {
+		return metadata, nil
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, fmt.Errorf("empty key in upload metadata %q", header)
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("upload metadata %q: %w", key, err)
+		}
+
+		metadata[key] = string(decoded)
+	}
+
+	return metadata, nil
+}
+
+func formatTusMetadata(metadata map[string]string) string {
+	pairs := make([]string, 0, len(metadata))
+
+	for key, value := range metadata {
+		pairs = append(pairs, key+" "+base64.StdEncoding.EncodeToString([]byte(value)))
+	}
+
+	slices.Sort(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// tusPath returns the path of the state of upload id, or of one of its parts.
+func (server *TusServer) tusPath(id string, elements ...string) string {
+	if len(elements) == 0 {
+		return path.Join(server.StateDirectory, id+".json")
+	}
+
+	return path.Join(append([]string{server.StateDirectory, id}, elements...)...)
+}
@@ -0,0 +1,214 @@
+package storage_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gonstruct/providers/adapters/storage/fake"
+	"github.com/gonstruct/providers/entities"
+	"github.com/gonstruct/providers/storage"
+)
+
+func tusRequest(handler http.Handler, method, target string, body io.Reader, header map[string]string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, body)
+	request.Header.Set("Tus-Resumable", storage.TusVersion)
+
+	for name, value := range header {
+		request.Header.Set(name, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func tusPatch(handler http.Handler, target, offset string, body io.Reader) *httptest.ResponseRecorder {
+	return tusRequest(handler, http.MethodPatch, target, body, map[string]string{
+		"Content-Type":  "application/offset+octet-stream",
+		"Upload-Offset": offset,
+	})
+}
+
+// interruptedReader returns its data and then fails, like the body of a request whose client went away.
+type interruptedReader struct {
+	data io.Reader
+}
+
+func (reader interruptedReader) Read(p []byte) (int, error) {
+	n, err := reader.data.Read(p)
+	if errors.Is(err, io.EOF) {
+		return n, io.ErrUnexpectedEOF
+	}
+
+	return n, err
+}
+
+func TestTusServer(t *testing.T) {
+	adapter := fake.New()
+
+	var uploaded []*entities.StorageMetadata
+
+	server := storage.NewTusServer("videos", storage.WithAdapter(adapter), storage.WithMaxSize(100),
+		storage.WithUniqueIDGenerator(func() string { return "abc" }),
+		storage.WithUploadCallback(func(ctx context.Context, metadata *entities.StorageMetadata) error {
+			uploaded = append(uploaded, metadata)
+
+			return nil
+		}))
+	server.PartSize = 4
+
+	handler := http.StripPrefix("/uploads", server)
+
+	if response := tusRequest(handler, http.MethodOptions, "/uploads/", nil, nil); response.Code != http.StatusNoContent || response.Header().Get("Tus-Max-Size") != "100" {
+		t.Errorf("OPTIONS = %d %v, want 204 with the maximum size", response.Code, response.Header())
+	}
+
+	if response := tusRequest(handler, http.MethodPost, "/uploads/", nil, map[string]string{"Upload-Length": "101"}); response.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("POST too large = %d, want 413", response.Code)
+	}
+
+	created := tusRequest(handler, http.MethodPost, "/uploads/", nil, map[string]string{
+		"Upload-Length":   "11",
+		"Upload-Metadata": "filename " + base64.StdEncoding.EncodeToString([]byte("clip.mp4")),
+	})
+	if created.Code != http.StatusCreated || created.Header().Get("Location") != "/uploads/abc" {
+		t.Fatalf("POST = %d %v, want 201 with the location", created.Code, created.Header())
+	}
+
+	if response := tusPatch(handler, "/uploads/abc", "0", interruptedReader{strings.NewReader("hello ")}); response.Code != http.StatusNoContent {
+		t.Fatalf("interrupted PATCH = %d, want 204", response.Code)
+	}
+
+	head := tusRequest(handler, http.MethodHead, "/uploads/abc", nil, nil)
+	if head.Code != http.StatusOK || head.Header().Get("Upload-Offset") != "6" || head.Header().Get("Upload-Length") != "11" {
+		t.Fatalf("HEAD = %d %v, want the offset of the interrupted request", head.Code, head.Header())
+	}
+
+	if response := tusPatch(handler, "/uploads/abc", "0", strings.NewReader("hello world")); response.Code != http.StatusConflict {
+		t.Errorf("PATCH with another offset = %d, want 409", response.Code)
+	}
+
+	if response := tusPatch(handler, "/uploads/abc", "6", strings.NewReader("world")); response.Code != http.StatusNoContent || response.Header().Get("Upload-Offset") != "11" {
+		t.Fatalf("PATCH = %d %v, want 204 with the final offset", response.Code, response.Header())
+	}
+
+	adapter.AssertStoredContent(t, "videos/abc.mp4", []byte("hello world"))
+	adapter.AssertNotStored(t, ".tus/abc/00001")
+
+	if len(uploaded) != 1 || uploaded[0].Path != "videos/abc.mp4" || uploaded[0].Size != 11 {
+		t.Errorf("callback metadata = %v, want the stored file", uploaded)
+	}
+
+	if response := tusRequest(handler, http.MethodDelete, "/uploads/abc", nil, nil); response.Code != http.StatusNoContent {
+		t.Errorf("DELETE = %d, want 204", response.Code)
+	}
+
+	adapter.AssertStored(t, "videos/abc.mp4")
+	adapter.AssertNotStored(t, ".tus/abc.json")
+
+	if response := tusRequest(handler, http.MethodHead, "/uploads/abc", nil, nil); response.Code != http.StatusNotFound {
+		t.Errorf("HEAD after DELETE = %d, want 404", response.Code)
+	}
+
+	request := httptest.NewRequest(http.MethodHead, "/uploads/abc", nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusPreconditionFailed {
+		t.Errorf("HEAD without Tus-Resumable = %d, want 412", recorder.Code)
+	}
+}
+
+func TestTusServer_Expiration(t *testing.T) {
+	adapter := fake.New()
+	ids := []string{"expired", "active"}
+
+	server := storage.NewTusServer("videos", storage.WithAdapter(adapter), storage.WithUniqueIDGenerator(func() string {
+		id := ids[0]
+		ids = ids[1:]
+
+		return id
+	}))
+
+	server.Expiration = -1
+	tusRequest(server, http.MethodPost, "/", nil, map[string]string{"Upload-Length": "5"})
+	tusPatch(server, "/expired", "0", interruptedReader{strings.NewReader("he")})
+
+	server.Expiration = storage.DefaultTusExpiration
+	tusRequest(server, http.MethodPost, "/", nil, map[string]string{"Upload-Length": "5"})
+
+	if err := server.RemoveExpired(context.Background()); err != nil {
+		t.Fatalf("RemoveExpired() error = %v", err)
+	}
+
+	adapter.AssertNotStored(t, ".tus/expired.json")
+	adapter.AssertNotStored(t, ".tus/expired/00001")
+	adapter.AssertStored(t, ".tus/active.json")
+
+	server.Expiration = -1
+	tusPatch(server, "/active", "0", strings.NewReader("he"))
+
+	if response := tusPatch(server, "/active", "2", strings.NewReader("llo")); response.Code != http.StatusGone {
+		t.Errorf("PATCH expired = %d, want 410", response.Code)
+	}
+
+	adapter.AssertNotStored(t, ".tus/active.json")
+}
+
+func TestTusServer_ContentType(t *testing.T) {
+	adapter := fake.New()
+	ids := []string{"page", "photo", "clip"}
+
+	generator := storage.WithUniqueIDGenerator(func() string {
+		id := ids[0]
+		ids = ids[1:]
+
+		return id
+	})
+
+	metadata := func(filename, filetype string) map[string]string {
+		return map[string]string{
+			"Upload-Length": "0",
+			"Upload-Metadata": "filename " + base64.StdEncoding.EncodeToString([]byte(filename)) +
+				",filetype " + base64.StdEncoding.EncodeToString([]byte(filetype)),
+		}
+	}
+
+	// The client's content type is not stored without WithContentType
+	server := storage.NewTusServer("uploads", storage.WithAdapter(adapter), generator)
+	if response := tusRequest(server, http.MethodPost, "/", nil, metadata("page.html<x>", "text/html")); response.Code != http.StatusCreated {
+		t.Fatalf("POST = %d, want 201", response.Code)
+	}
+
+	if stored, err := storage.Stat("uploads/page", storage.WithAdapter(adapter)); err != nil || stored.ContentType != "application/octet-stream" {
+		t.Errorf("Stat() = %v, %v, want application/octet-stream without a known extension", stored, err)
+	}
+
+	if response := tusRequest(server, http.MethodPost, "/", nil, metadata("photo.PNG", "text/html")); response.Code != http.StatusCreated {
+		t.Fatalf("POST = %d, want 201", response.Code)
+	}
+
+	if stored, err := storage.Stat("uploads/photo.png", storage.WithAdapter(adapter)); err != nil || stored.ContentType != "image/png" {
+		t.Errorf("Stat() = %v, %v, want the content type of the extension", stored, err)
+	}
+
+	server = storage.NewTusServer("uploads", storage.WithAdapter(adapter), generator, storage.WithContentType("video/mp4"))
+	if response := tusRequest(server, http.MethodPost, "/", nil, metadata("clip.html", "text/html")); response.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("POST with another content type = %d, want 415", response.Code)
+	}
+
+	if response := tusRequest(server, http.MethodPost, "/", nil, metadata("clip.mp4", "video/mp4")); response.Code != http.StatusCreated {
+		t.Fatalf("POST = %d, want 201", response.Code)
+	}
+
+	if stored, err := storage.Stat("uploads/clip.mp4", storage.WithAdapter(adapter)); err != nil || stored.ContentType != "video/mp4" {
+		t.Errorf("Stat() = %v, %v, want the configured content type", stored, err)
+	}
+}
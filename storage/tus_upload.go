@@ -0,0 +1,369 @@
+package storage
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+)
+
+// tusUpload is the state of an upload, stored as JSON below StateDirectory.
+type tusUpload struct {
+	ID          string                 `json:"id"`
+	Path        string                 `json:"path"`
+	Length      int64                  `json:"length"`
+	Offset      int64                  `json:"offset"`
+	Metadata    map[string]string      `json:"metadata,omitempty"`
+	ContentType string                 `json:"contentType,omitempty"`
+	ExpiresAt   time.Time              `json:"expiresAt"`
+	MultipartID string                 `json:"multipartId,omitempty"`
+	Parts       []entities.StoragePart `json:"parts,omitempty"`
+	// Pending is the size of the data that is too small for a part of the multipart upload yet
+	Pending   int64 `json:"pending,omitempty"`
+	Completed bool  `json:"completed,omitempty"`
+}
+
+func (upload *tusUpload) expired(now time.Time) bool {
+	return !upload.ExpiresAt.IsZero() && now.After(upload.ExpiresAt)
+}
+
+// RemoveExpired removes the state and the parts of the uploads that expired, run it periodically.
+// Finished files are kept.
+func (server *TusServer) RemoveExpired(ctx context.Context) error {
+	now := time.Now()
+	cursor := List(server.StateDirectory, WithAdapter(server.options.Adapter), WithContext(ctx), WithPattern("*.json"))
+
+	for cursor.Next() {
+		entry := cursor.Entry()
+		if entry.IsDir {
+			continue
+		}
+
+		upload, err := server.load(ctx, strings.TrimSuffix(path.Base(entry.Path), ".json"))
+		if errors.Is(err, errTusNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !upload.expired(now) {
+			continue
+		}
+
+		if err := server.remove(ctx, upload); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// start creates an upload of length bytes, with a multipart upload on adapters that support them.
+func (server *TusServer) start(ctx context.Context, length int64, metadata map[string]string) (*tusUpload, error) {
+	extension, contentType, err := server.fileType(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	id := server.options.GenerateUniqueID()
+
+	upload := &tusUpload{
+		ID:          id,
+		Path:        path.Join(server.Directory, id+extension),
+		Length:      length,
+		Metadata:    metadata,
+		ContentType: contentType,
+		ExpiresAt:   time.Now().Add(server.Expiration),
+	}
+
+	// Multipart uploads need at least one part, empty files are written when they are finished
+	if multipart, ok := server.multipart(); ok && length > 0 {
+		multipartID, err := multipart.CreateMultipartUpload(ctx, upload.Path, server.writeOptions(upload))
+		if err != nil {
+			return nil, err
+		}
+
+		upload.MultipartID = multipartID
+	}
+
+	if err := server.save(ctx, upload); err != nil {
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+// fileType returns the extension and the content type of the file of an upload. The extension of the
+// "filename" metadata is kept when it has a known content type. The content type the client reports
+// is not stored, uploads with another content type than WithContentType fail with ErrUploadRejected,
+// the others get the content type of their extension.
+func (server *TusServer) fileType(metadata map[string]string) (string, string, error) {
+	extension := strings.ToLower(path.Ext(cmp.Or(metadata["filename"], metadata["name"])))
+	extensionType := mime.TypeByExtension(extension)
+
+	if extensionType == "" {
+		extension = ""
+	}
+
+	input := uploadInput("", 0, server.options)
+	if input.ContentType == "" {
+		return extension, cmp.Or(extensionType, "application/octet-stream"), nil
+	}
+
+	if err := input.CheckContentType(cmp.Or(metadata["filetype"], metadata["type"], extensionType)); err != nil {
+		return "", "", err
+	}
+
+	return extension, input.ContentType, nil
+}
+
+func (server *TusServer) load(ctx context.Context, id string) (*tusUpload, error) {
+	data, err := server.options.Adapter.Get(ctx, server.tusPath(id))
+	if errors.Is(err, ErrFileNotFound) {
+		return nil, errTusNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var upload tusUpload
+	if err := json.Unmarshal(data, &upload); err != nil {
+		return nil, PathErr("decode tus upload", server.tusPath(id), err)
+	}
+
+	return &upload, nil
+}
+
+func (server *TusServer) save(ctx context.Context, upload *tusUpload) error {
+	data, err := json.Marshal(upload)
+	if err != nil {
+		return PathErr("encode tus upload", server.tusPath(upload.ID), err)
+	}
+
+	return server.options.Adapter.Put(ctx, server.tusPath(upload.ID), data)
+}
+
+// remove aborts an unfinished upload and deletes its state and parts.
+func (server *TusServer) remove(ctx context.Context, upload *tusUpload) error {
+	adapter := server.options.Adapter
+
+	if multipart, ok := server.multipart(); ok && upload.MultipartID != "" && !upload.Completed {
+		if err := multipart.AbortMultipartUpload(ctx, upload.Path, upload.MultipartID); err != nil {
+			return err
+		}
+	}
+
+	if err := adapter.DeleteDirectory(ctx, server.tusPath(upload.ID, "")); err != nil {
+		return err
+	}
+
+	if err := adapter.Delete(ctx, server.tusPath(upload.ID)); err != nil && !errors.Is(err, ErrFileNotFound) {
+		return err
+	}
+
+	server.locks.Delete(upload.ID)
+
+	return nil
+}
+
+// receive appends body to the upload in parts of PartSize bytes and saves the state after every part,
+// so the data of interrupted requests is kept. Data too small for a part of the multipart upload is
+// stored as a pending part, which the next request completes.
+func (server *TusServer) receive(ctx context.Context, upload *tusUpload, body io.Reader) error {
+	adapter := server.options.Adapter
+	partSize, minimum := server.PartSize, int64(0)
+
+	multipart, ok := server.multipart()
+	if ok && upload.MultipartID != "" {
+		minimum = multipart.MinimumPartSize()
+		partSize = max(partSize, minimum)
+	} else {
+		multipart = nil
+	}
+
+	var pending []byte
+
+	if upload.Pending > 0 {
+		data, err := adapter.Get(ctx, server.tusPath(upload.ID, "pending"))
+		if err != nil {
+			return err
+		}
+
+		pending = data
+	}
+
+	body = io.LimitReader(body, upload.Length-upload.Offset)
+
+	for {
+		buffer := make([]byte, partSize)
+		copied := copy(buffer, pending)
+		pending = nil
+
+		n, readErr := io.ReadFull(body, buffer[copied:])
+		buffer = buffer[:copied+n]
+
+		if n > 0 {
+			if err := server.store(ctx, upload, buffer, minimum, multipart); err != nil {
+				return err
+			}
+		}
+
+		// The body ended, or the client went away and resumes from the saved offset
+		if readErr != nil {
+			return nil
+		}
+	}
+}
+
+// store stores data, which starts with the pending part, as the next part of the upload, or as the
+// pending part when it is too small for a part of the multipart upload and more data follows.
+func (server *TusServer) store(ctx context.Context, upload *tusUpload, data []byte, minimum int64, multipart contracts.StorageMultipartUploader) error {
+	adapter := server.options.Adapter
+	stored := upload.Offset - upload.Pending
+	size := int64(len(data))
+
+	switch {
+	case size < minimum && stored+size < upload.Length:
+		if err := adapter.Put(ctx, server.tusPath(upload.ID, "pending"), data); err != nil {
+			return err
+		}
+
+		upload.Pending = size
+	case multipart != nil:
+		part, err := multipart.UploadPart(ctx, upload.Path, upload.MultipartID, len(upload.Parts)+1, data)
+		if err != nil {
+			return err
+		}
+
+		upload.Parts = append(upload.Parts, *part)
+		upload.Pending = 0
+	default:
+		number := len(upload.Parts) + 1
+		if err := adapter.Put(ctx, server.tusPath(upload.ID, fmt.Sprintf("%05d", number)), data); err != nil {
+			return err
+		}
+
+		upload.Parts = append(upload.Parts, entities.StoragePart{Number: number, Size: size})
+	}
+
+	upload.Offset = stored + size
+
+	return server.save(ctx, upload)
+}
+
+// finish stores the file of a complete upload and calls the upload callback.
+func (server *TusServer) finish(ctx context.Context, upload *tusUpload) error {
+	adapter := server.options.Adapter
+
+	if multipart, ok := server.multipart(); ok && upload.MultipartID != "" {
+		if err := multipart.CompleteMultipartUpload(ctx, upload.Path, upload.MultipartID, upload.Parts); err != nil {
+			return err
+		}
+	} else {
+		parts := &partsReader{ctx: ctx, adapter: adapter}
+		for _, part := range upload.Parts {
+			parts.paths = append(parts.paths, server.tusPath(upload.ID, fmt.Sprintf("%05d", part.Number)))
+		}
+
+		var err error
+		if _, ok := adapter.(contracts.StorageWriter); ok {
+			err = write(ctx, adapter, upload.Path, parts, server.writeOptions(upload))
+		} else {
+			err = adapter.PutStream(ctx, upload.Path, parts)
+		}
+
+		parts.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := adapter.DeleteDirectory(ctx, server.tusPath(upload.ID, "")); err != nil {
+		return err
+	}
+
+	upload.Completed = true
+	upload.Parts = nil
+
+	if err := server.save(ctx, upload); err != nil {
+		return err
+	}
+
+	if server.options.OnUpload == nil {
+		return nil
+	}
+
+	metadata, err := Stat(upload.Path, WithAdapter(adapter), WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	return server.options.OnUpload(ctx, metadata)
+}
+
+func (server *TusServer) writeOptions(upload *tusUpload) entities.StorageWriteOptions {
+	options := server.options.Write
+	options.ContentType = upload.ContentType
+
+	return options
+}
+
+// partsReader reads the parts of an upload one after the other, it opens every part when it reaches it.
+type partsReader struct {
+	ctx     context.Context
+	adapter contracts.Storage
+	paths   []string
+	current io.ReadCloser
+}
+
+func (reader *partsReader) Read(p []byte) (int, error) {
+	for {
+		if reader.current == nil {
+			if len(reader.paths) == 0 {
+				return 0, io.EOF
+			}
+
+			stream, err := reader.adapter.GetStream(reader.ctx, reader.paths[0])
+			if err != nil {
+				return 0, err
+			}
+
+			reader.current, reader.paths = stream, reader.paths[1:]
+		}
+
+		n, err := reader.current.Read(p)
+		if errors.Is(err, io.EOF) {
+			_ = reader.current.Close()
+			reader.current = nil
+
+			if n == 0 {
+				continue
+			}
+
+			err = nil
+		}
+
+		return n, err
+	}
+}
+
+func (reader *partsReader) Close() error {
+	if reader.current == nil {
+		return nil
+	}
+
+	return reader.current.Close()
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bytes"
 	"io"
 	"time"
 
@@ -14,21 +15,30 @@ func PutFile(path string, file file.File, optionSlice ...Option) (*entities.Stor
 	options := apply(optionSlice...)
 
 	return options.Adapter.PutFile(options.Context, entities.StorageInput{
-		ID:   options.GenerateUniqueID(),
-		File: file,
-		Path: path,
+		ID:      options.GenerateUniqueID(),
+		File:    file,
+		Path:    path,
+		Options: options.Write,
 	})
 }
 
 func Put(path string, contents []byte, optionSlice ...Option) error {
 	options := apply(optionSlice...)
 
+	if !options.Write.IsZero() {
+		return write(options.Context, options.Adapter, path, bytes.NewReader(contents), options.Write)
+	}
+
 	return options.Adapter.Put(options.Context, path, contents)
 }
 
 func PutStream(path string, stream io.Reader, optionSlice ...Option) error {
 	options := apply(optionSlice...)
 
+	if !options.Write.IsZero() {
+		return write(options.Context, options.Adapter, path, stream, options.Write)
+	}
+
 	return options.Adapter.PutStream(options.Context, path, stream)
 }
 
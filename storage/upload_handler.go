@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+)
+
+// UploadHandler returns an http.Handler that stores the files uploaded with the PUT and POST
+// requests of URLSigner.SignUpload, which local.Adapter.TemporaryUploadURL and TemporaryUploadForm
+// return, for adapters without presigned uploads of their own. It enforces the constraints the
+// URLs were signed with and responds with 204 No Content when the file is stored. Mount it next
+// to Handler:
+//
+//	mux.Handle("GET /files/", http.StripPrefix("/files", storage.Handler(storage.WithSigner(signer))))
+//	mux.Handle("PUT /files/", http.StripPrefix("/files", storage.UploadHandler(storage.WithSigner(signer))))
+//	mux.Handle("POST /files/", http.StripPrefix("/files", storage.UploadHandler(storage.WithSigner(signer))))
+//
+// It rejects all requests without WithSigner, see WithUploadCallback for the completion callback.
+// Files are stored with the content type the URL was signed with, or else with the content type
+// of their extension, the content type of the request is not trusted.
+func UploadHandler(optionSlice ...Option) http.Handler {
+	options := apply(optionSlice...)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receive(w, r, options)
+	})
+}
+
+// WithUploadCallback makes UploadHandler call callback with the metadata of every stored file
+// before it responds, it responds with 500 Internal Server Error when callback fails.
+func WithUploadCallback(callback func(ctx context.Context, metadata *entities.StorageMetadata) error) Option {
+	return func(options *options) {
+		options.OnUpload = callback
+	}
+}
+
+func receive(w http.ResponseWriter, r *http.Request, options *options) {
+	if r.Method != http.MethodPut && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "PUT, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	filePath, err := NormalizePath(strings.TrimPrefix(r.URL.Path, "/"))
+	if err != nil || filePath == "" {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	query := r.URL.Query()
+
+	if options.Signer == nil || options.Signer.Verify(filePath, query) != nil || query.Get(UploadParameter) != r.Method {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+
+		return
+	}
+
+	input := uploadConstraints(filePath, query)
+
+	body, contentType, err := uploadBody(r)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+
+		return
+	}
+
+	if err := input.CheckContentType(contentType); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	if limit := cmp.Or(input.ContentLength, input.MaxSize); limit > 0 {
+		body = http.MaxBytesReader(w, io.NopCloser(body), limit)
+	}
+
+	// Without a signed content type the client's is not trusted, Handler serves it inline
+	if input.ContentType == "" {
+		contentType = cmp.Or(mime.TypeByExtension(path.Ext(filePath)), "application/octet-stream")
+	}
+
+	ctx := r.Context()
+	writeOptions := entities.StorageWriteOptions{ContentType: contentType, Visibility: input.Visibility}
+
+	if _, ok := options.Adapter.(contracts.StorageWriter); ok {
+		err = write(ctx, options.Adapter, filePath, body, writeOptions)
+	} else {
+		err = options.Adapter.PutStream(ctx, filePath, body)
+	}
+
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return
+	}
+
+	metadata, err := Stat(filePath, WithAdapter(options.Adapter), WithContext(ctx))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return
+	}
+
+	// Catches uploads shorter than ContentLength, which MaxBytesReader lets through
+	if err := checkUpload(ctx, options.Adapter, entities.StorageUploadInput{ContentLength: input.ContentLength}, metadata); err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+
+		return
+	}
+
+	if options.OnUpload != nil {
+		if err := options.OnUpload(ctx, metadata); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// uploadBody returns the file of an upload and its content type, the body of PUT requests and the
+// "file" field of POST requests. The Content-Type field of POST requests precedes the content type
+// of the file part, like on S3.
+func uploadBody(r *http.Request) (io.Reader, string, error) {
+	if r.Method == http.MethodPut {
+		return r.Body, r.Header.Get("Content-Type"), nil
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, "", err
+	}
+
+	fields := map[string]string{}
+
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, "", err
+		}
+
+		if part.FormName() == "file" {
+			return part, cmp.Or(fields["Content-Type"], part.Header.Get("Content-Type")), nil
+		}
+
+		if len(fields) == maxFields {
+			return nil, "", errors.New("too many form fields")
+		}
+
+		fields[part.FormName()], err = readField(part)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+}
+
+// maxFields and maxFieldSize limit the fields before the file of POST requests, which are read into memory.
+const (
+	maxFields    = 64
+	maxFieldSize = 1 << 16
+)
+
+func readField(part *multipart.Part) (string, error) {
+	value, err := io.ReadAll(io.LimitReader(part, maxFieldSize+1))
+	if err != nil {
+		return "", err
+	}
+
+	if len(value) > maxFieldSize {
+		return "", errors.New("form field too large")
+	}
+
+	return string(value), nil
+}
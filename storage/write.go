@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/gonstruct/providers/contracts"
+	"github.com/gonstruct/providers/entities"
+)
+
+// WithVisibility creates the file with the visibility, instead of the default of the adapter.
+func WithVisibility(visibility entities.Visibility) Option {
+	return func(options *options) {
+		options.Write.Visibility = visibility
+	}
+}
+
+// WithContentType stores the file with the MIME type, instead of the one of its extension.
+func WithContentType(contentType string) Option {
+	return func(options *options) {
+		options.Write.ContentType = contentType
+	}
+}
+
+// WithMetadata stores the file with user defined metadata. Keys are lower cased, as S3 does.
+func WithMetadata(metadata map[string]string) Option {
+	return func(options *options) {
+		if options.Write.Metadata == nil {
+			options.Write.Metadata = make(map[string]string, len(metadata))
+		}
+
+		for key, value := range metadata {
+			options.Write.Metadata[strings.ToLower(key)] = value
+		}
+	}
+}
+
+// WithCacheControl stores the file with the Cache-Control header it is served with.
+func WithCacheControl(cacheControl string) Option {
+	return func(options *options) {
+		options.Write.CacheControl = cacheControl
+	}
+}
+
+// WithContentDisposition stores the file with the Content-Disposition header it is served with,
+// like `attachment; filename="report.pdf"`.
+func WithContentDisposition(contentDisposition string) Option {
+	return func(options *options) {
+		options.Write.ContentDisposition = contentDisposition
+	}
+}
+
+// WithContentEncoding stores the file with the Content-Encoding header it is served with, like gzip
+// for content that was compressed before it was stored.
+func WithContentEncoding(contentEncoding string) Option {
+	return func(options *options) {
+		options.Write.ContentEncoding = contentEncoding
+	}
+}
+
+// IfNotExists only writes the file when it does not exist yet, the write fails with ErrAlreadyExists otherwise.
+// S3 checks it atomically with a conditional write.
+func IfNotExists() Option {
+	return func(options *options) {
+		options.Write.IfNoneMatch = "*"
+	}
+}
+
+// IfMatch only overwrites the file when its ETag is etag, see Stat, the write fails with ErrPreconditionFailed
+// otherwise. It prevents lost updates of files that are read, changed and written back.
+func IfMatch(etag string) Option {
+	return func(options *options) {
+		options.Write.IfMatch = etag
+	}
+}
+
+// write stores body with the write options on adapters that implement contracts.StorageWriter.
+func write(ctx context.Context, adapter contracts.Storage, path string, body io.Reader, options entities.StorageWriteOptions) error {
+	writer, ok := adapter.(contracts.StorageWriter)
+	if !ok {
+		return PathErr("put", path, ErrNotSupported)
+	}
+
+	return writer.Write(ctx, path, body, options)
+}